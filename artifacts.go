@@ -0,0 +1,182 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/execext"
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/status"
+	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// publishArtifacts puts the files generated by the task in each of its
+// artifact destinations. It's called after the commands of the task succeed.
+func (e *Executor) publishArtifacts(ctx context.Context, t *taskfile.Task, call taskfile.Call) error {
+	if len(t.Artifacts) == 0 {
+		return nil
+	}
+
+	files := e.generatedFiles(t)
+	if len(files) == 0 {
+		e.Logger.VerboseErrf(logger.Yellow, `task: [%s] no files generated, so no artifacts were published`, e.redact(t.Name()))
+		return nil
+	}
+
+	for _, artifact := range t.Artifacts {
+		var err error
+		switch {
+		case artifact.Dir != "":
+			err = e.copyArtifacts(t, files, filepathext.SmartJoin(t.Dir, artifact.Dir))
+		case artifact.S3 != "":
+			err = e.runArtifactCmds(ctx, t, files, func(file string) (string, error) {
+				url := strings.TrimRight(artifact.S3, "/") + "/" + filepath.ToSlash(file)
+				return fmt.Sprintf("aws s3 cp %s %s", shellQuote(file), shellQuote(url)), nil
+			})
+		case artifact.Cmd != "":
+			err = e.runArtifactCmds(ctx, t, files, func(file string) (string, error) {
+				// The compiled task has no vars, so they're taken from the
+				// original one
+				origTask, err := e.GetTask(call)
+				if err != nil {
+					return "", err
+				}
+				vars, err := e.Compiler.GetVariables(origTask, call)
+				if err != nil {
+					return "", err
+				}
+				vars.Set("ARTIFACT", taskfile.Var{Static: file})
+				r := templater.Templater{Vars: vars, RemoveNoValue: true}
+				cmd := r.Replace(artifact.Cmd)
+				return cmd, r.Err()
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generatedFiles returns the files matched by the "generates" of the task,
+// relative to its directory when they're inside of it
+func (e *Executor) generatedFiles(t *taskfile.Task) []string {
+	return matchedFiles(t, t.Generates)
+}
+
+// sourceFiles returns the files matched by the "sources" of the task, like
+// generatedFiles does for its "generates"
+func (e *Executor) sourceFiles(t *taskfile.Task) []string {
+	return matchedFiles(t, t.Sources)
+}
+
+func matchedFiles(t *taskfile.Task, globs []string) []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, g := range globs {
+		matches, err := status.Glob(t.Dir, g)
+		if err != nil {
+			continue
+		}
+		for _, f := range matches {
+			if rel, err := filepath.Rel(t.Dir, f); err == nil && !strings.HasPrefix(rel, "..") {
+				f = rel
+			}
+			if !seen[f] {
+				seen[f] = true
+				files = append(files, f)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// copyArtifacts copies the files to the given directory, keeping their paths
+// relative to the directory of the task
+func (e *Executor) copyArtifacts(t *taskfile.Task, files []string, dir string) error {
+	if !t.Silent && !e.Silent || e.Verbose {
+		e.Logger.Errf(logger.Green, "task: [%s] copying %d artifact(s) to %s", e.redact(t.Name()), len(files), dir)
+	}
+	if e.Dry {
+		return nil
+	}
+
+	for _, file := range files {
+		target := filepath.Join(dir, file)
+		if filepath.IsAbs(file) {
+			target = filepath.Join(dir, filepath.Base(file))
+		}
+		if err := copyFile(filepathext.SmartJoin(t.Dir, file), target); err != nil {
+			return fmt.Errorf("task: Failed to copy artifact %q: %w", file, err)
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(filepathext.LongPath(src))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepathext.LongPath(filepath.Dir(dst)), 0o755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(filepathext.LongPath(dst), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// runArtifactCmds runs the command returned by cmdFor for each file, in the
+// directory of the task
+func (e *Executor) runArtifactCmds(ctx context.Context, t *taskfile.Task, files []string, cmdFor func(file string) (string, error)) error {
+	for _, file := range files {
+		cmd, err := cmdFor(file)
+		if err != nil {
+			return err
+		}
+		if !t.Silent && !e.Silent || e.Verbose {
+			e.Logger.Errf(logger.Green, "task: [%s] %s", e.redact(t.Name()), e.redact(cmd))
+		}
+		if e.Dry {
+			continue
+		}
+		if err := e.checkPolicy(ctx, t, &PolicyCommand{Kind: "artifact", Cmd: cmd}); err != nil {
+			return err
+		}
+
+		stdout, stderr, flush := e.redactWriters(e.Stdout, e.Stderr)
+		err = execext.RunCommand(ctx, &execext.RunCommandOptions{
+			Command: cmd,
+			Dir:     t.Dir,
+			Env:     e.getEnviron(t),
+			Stdin:   e.Stdin,
+			Stdout:  stdout,
+			Stderr:  stderr,
+		})
+		flush()
+		if err != nil {
+			return fmt.Errorf("task: Failed to publish artifact %q: %w", file, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/joho/godotenv"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+var (
+	templateActionRegexp = regexp.MustCompile(`{{.*?}}`)
+	templateVarRegexp    = regexp.MustCompile(`\.([A-Z_][A-Z0-9_]*)\b`)
+	shellVarRegexp       = regexp.MustCompile(`\$(?:{([A-Z_][A-Z0-9_]*)|([A-Z_][A-Z0-9_]*))`)
+	shellAssignRegexp    = regexp.MustCompile(`(?:^|[\s;&|(])(?:export\s+)?([A-Z_][A-Z0-9_]*)=`)
+)
+
+// specialVars are the variables set by Task itself
+var specialVars = []string{
+	"TASK", "ROOT_DIR", "TASKFILE_DIR", "CLI_ARGS", "CLI_ARGS_LIST",
+	"CHECKSUM", "TIMESTAMP", "TMP_DIR", "MATCH", "MATCHED_NAME", "ARTIFACT",
+	"TASKS", "STATUS", "DURATION", "ERROR",
+}
+
+// CheckEnv compares the variables the Taskfile expects from the environment
+// with the keys of the given example dotenv file, relative to the directory
+// of the Taskfile. It reports the missing keys, the ones set in the dotenv
+// files or referenced by the tasks without being set by the Taskfile, and the
+// stale ones, used nowhere anymore. It fails when any is found, so it can run
+// in CI.
+func (e *Executor) CheckEnv(example string) error {
+	if err := e.checkNotInspecting("check the dotenv files"); err != nil {
+		return err
+	}
+
+	path := filepathext.SmartJoin(e.Dir, example)
+	exampleEnv, err := godotenv.Read(filepathext.LongPath(path))
+	if err != nil {
+		return fmt.Errorf("task: Could not read %s: %w", filepathext.TryAbsToRel(path), err)
+	}
+
+	used, known, err := e.envUsage()
+	if err != nil {
+		return err
+	}
+
+	var missing, stale []string
+	for key, usage := range used {
+		if _, ok := exampleEnv[key]; !ok {
+			missing = append(missing, fmt.Sprintf(`task: %s is %s, but missing from %s`, key, usage, example))
+		}
+	}
+	for key := range exampleEnv {
+		if _, ok := used[key]; !ok && !known[key] {
+			stale = append(stale, fmt.Sprintf(`task: %s of %s is not used anymore`, key, example))
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+
+	for _, warning := range append(missing, stale...) {
+		e.Logger.Errf(logger.Yellow, "%s", warning)
+	}
+	if len(missing) > 0 || len(stale) > 0 {
+		return fmt.Errorf("task: %s is out of sync: %d missing and %d stale keys", example, len(missing), len(stale))
+	}
+	e.Logger.VerboseErrf(logger.Green, "task: %s is in sync", example)
+	return nil
+}
+
+// envUsage returns the variables expected from the environment, with how
+// each one is used: the keys of the dotenv files, and the variables the
+// tasks reference that aren't set by the Taskfile, by Task itself or by the
+// environment of Task. It also returns all the variables the Taskfile sets or
+// references, which may still be given by the environment, like the ones
+// with a default value.
+func (e *Executor) envUsage() (used map[string]string, known map[string]bool, err error) {
+	used = make(map[string]string)
+	known = make(map[string]bool)
+
+	for _, path := range e.dotenvFiles {
+		if err := addDotenvKeys(used, path); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, t := range e.Taskfile.Tasks {
+		for _, path := range t.EnvFile {
+			if err := addDotenvKeys(used, filepathext.SmartJoin(t.Dir, path)); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	defined := make(map[string]bool)
+	for _, name := range specialVars {
+		defined[name] = true
+	}
+	for _, env := range os.Environ() {
+		if name, _, ok := strings.Cut(env, "="); ok {
+			defined[name] = true
+		}
+	}
+	addVars := func(vars *taskfile.Vars) {
+		_ = vars.Range(func(key string, _ taskfile.Var) error {
+			// The dotenv files are merged into the env of the Taskfile
+			if _, ok := used[key]; !ok {
+				defined[key] = true
+				known[key] = true
+			}
+			return nil
+		})
+	}
+	addVars(e.Taskfile.Vars)
+	addVars(e.Taskfile.Env)
+	for _, params := range e.Taskfile.IncludedParams {
+		for _, key := range params.Keys {
+			defined[key] = true
+		}
+	}
+	for _, t := range e.Taskfile.Tasks {
+		addVars(t.Vars)
+		addVars(t.Env)
+		addVars(t.IncludeVars)
+		addVars(t.IncludedTaskfileVars)
+		_ = t.Flags.Range(func(name string, f *taskfile.Flag) error {
+			defined[f.VarName(name)] = true
+			return nil
+		})
+	}
+
+	for _, t := range e.sortedTasks() {
+		for _, s := range taskTexts(t) {
+			for _, name := range referencedVars(s) {
+				known[name] = true
+				if _, ok := used[name]; !ok && !defined[name] {
+					used[name] = fmt.Sprintf(`referenced by task "%s"`, t.Task)
+				}
+			}
+		}
+	}
+	return used, known, nil
+}
+
+func addDotenvKeys(used map[string]string, path string) error {
+	if _, err := os.Stat(filepathext.LongPath(path)); os.IsNotExist(err) {
+		return nil
+	}
+	envs, err := godotenv.Read(filepathext.LongPath(path))
+	if err != nil {
+		return err
+	}
+	for key := range envs {
+		if _, ok := used[key]; !ok {
+			used[key] = "set in " + filepathext.TryAbsToRel(path)
+		}
+	}
+	return nil
+}
+
+// taskTexts returns the values of a task that may reference variables
+func taskTexts(t *taskfile.Task) []string {
+	texts := []string{t.Dir, t.Label}
+	for _, cmd := range t.Cmds {
+		texts = append(texts, cmd.Cmd)
+	}
+	texts = append(texts, t.Status...)
+	texts = append(texts, t.Sources...)
+	texts = append(texts, t.Generates...)
+	for _, p := range t.Preconditions {
+		texts = append(texts, p.Sh)
+	}
+	for _, vars := range []*taskfile.Vars{t.Vars, t.Env} {
+		_ = vars.Range(func(_ string, v taskfile.Var) error {
+			texts = append(texts, v.Static, v.Sh)
+			return nil
+		})
+	}
+	return texts
+}
+
+// referencedVars returns the upper case variables referenced by a template
+// or by a shell command, leaving out the ones the command assigns itself
+func referencedVars(s string) []string {
+	var names []string
+	for _, action := range templateActionRegexp.FindAllString(s, -1) {
+		for _, m := range templateVarRegexp.FindAllStringSubmatch(action, -1) {
+			names = append(names, m[1])
+		}
+	}
+
+	assigned := make(map[string]bool)
+	for _, m := range shellAssignRegexp.FindAllStringSubmatch(s, -1) {
+		assigned[m[1]] = true
+	}
+	for _, m := range shellVarRegexp.FindAllStringSubmatch(templateActionRegexp.ReplaceAllString(s, ""), -1) {
+		name := m[1] + m[2]
+		if !assigned[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,58 @@
+package task
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// branchNameRegexp matches the characters of branch names replaced to make
+// them directory names
+var branchNameRegexp = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// setupChecksumScope sets the directory the checksums of sources are kept in,
+// so the ones of a git worktree or branch aren't used by the others. Outside
+// of a git repository, checksums are kept for the whole project.
+func (e *Executor) setupChecksumScope() error {
+	var args []string
+	switch e.Taskfile.ChecksumScope {
+	case "", "project":
+		return nil
+	case "worktree":
+		args = []string{"rev-parse", "--show-toplevel"}
+	case "branch":
+		args = []string{"symbolic-ref", "--short", "HEAD"}
+	default:
+		err := fmt.Errorf(`task: invalid checksum_scope "%s". Available options: "project", "worktree" and "branch"`, e.Taskfile.ChecksumScope)
+		return taskfile.WithLocation(err, e.Taskfile.Locations["checksum_scope"])
+	}
+	// The git config of an untrusted repository could run commands
+	if e.Inspect {
+		return nil
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = e.Dir
+	out, err := cmd.Output()
+	if err != nil {
+		e.Logger.VerboseErrf(logger.Yellow, `task: Could not get the git %s of "checksum_scope", so checksums are kept for the whole project: %v`, e.Taskfile.ChecksumScope, err)
+		return nil
+	}
+	value := strings.TrimSpace(string(out))
+
+	if e.Taskfile.ChecksumScope == "worktree" {
+		sum := sha256.Sum256([]byte(filepath.Clean(value)))
+		e.checksumScope = fmt.Sprintf("worktree-%s-%s", filepath.Base(value), hex.EncodeToString(sum[:4]))
+	} else {
+		e.checksumScope = "branch-" + branchNameRegexp.ReplaceAllString(value, "-")
+	}
+	e.Logger.Debugf("checksums are kept in %s", filepath.Join(e.TempDir, "checksum", e.checksumScope))
+	return nil
+}
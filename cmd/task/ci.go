@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"strconv"
+)
+
+// ciEnvVars are environment variables set by common CI services, besides the
+// generic "CI"
+var ciEnvVars = []string{
+	"GITHUB_ACTIONS",
+	"GITLAB_CI",
+	"CIRCLECI",
+	"TRAVIS",
+	"BUILDKITE",
+	"JENKINS_URL",
+	"TF_BUILD",
+	"TEAMCITY_VERSION",
+	"BITBUCKET_BUILD_NUMBER",
+	"DRONE",
+	"APPVEYOR",
+	"CODEBUILD_BUILD_ID",
+	"WOODPECKER",
+}
+
+// detectCI reports whether Task seems to be running on a CI service. Setting
+// CI to a false value disables the detection.
+func detectCI() bool {
+	if value, ok := os.LookupEnv("CI"); ok && value != "" {
+		enabled, err := strconv.ParseBool(value)
+		return err != nil || enabled
+	}
+	for _, name := range ciEnvVars {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// forceColor reports whether colors were requested with FORCE_COLOR
+func forceColor() bool {
+	value, ok := os.LookupEnv("FORCE_COLOR")
+	if !ok {
+		return false
+	}
+	if enabled, err := strconv.ParseBool(value); err == nil {
+		return enabled
+	}
+	// FORCE_COLOR may also be a color level, like "2"
+	return value != ""
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-task/task/v3/internal/completion"
+)
+
+// completionValues are the values completed for the flags that only accept
+// some of them
+var completionValues = map[string][]string{
+	"completion": completion.Shells,
+	"output":     {"interleaved", "group", "prefixed", "plain"},
+	"sort":       {"default", "alphanumeric", "definition", "none", "topological"},
+	"export":     {"gha-matrix"},
+}
+
+// completionFiles are the flags whose argument is a file
+var completionFiles = map[string]bool{
+	"taskfile":       true,
+	"debug-file":     true,
+	"package":        true,
+	"provenance":     true,
+	"provenance-key": true,
+}
+
+// printCompletion prints the completion script of the given shell, so the
+// completed flags never drift from the ones actually accepted
+func printCompletion(w io.Writer, shell string) error {
+	var flags []completion.Flag
+	pflag.CommandLine.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		flag := completion.Flag{
+			Name:      f.Name,
+			Shorthand: f.Shorthand,
+			Usage:     f.Usage,
+		}
+		// The argument of the flags with a default when given alone, like
+		// the booleans, can only be set as "--flag=value"
+		if f.NoOptDefVal == "" {
+			switch {
+			case f.Name == "dir":
+				flag.Arg = completion.ArgDir
+			case completionFiles[f.Name]:
+				flag.Arg = completion.ArgFile
+			default:
+				flag.Arg = completion.ArgValue
+				flag.Values = completionValues[f.Name]
+			}
+		}
+		flags = append(flags, flag)
+	})
+	return completion.Script(w, shell, flags)
+}
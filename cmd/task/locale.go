@@ -0,0 +1,23 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// unicodeTerminal reports whether the terminal seems to support Unicode,
+// based on the locale. Windows terminals are assumed to support it.
+func unicodeTerminal() bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	// The first of these variables that is set wins, like in the C library
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			value = strings.ToUpper(value)
+			return strings.Contains(value, "UTF-8") || strings.Contains(value, "UTF8")
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// stopProfiling stops the profiles started by startProfiling. Task exits
+// through exit, fatal and fatalf, which call it, so the profiles are
+// complete whichever way it exits.
+var stopProfiling = func() {}
+
+// exit stops the profiles and exits with the given code
+func exit(code int) {
+	stopProfiling()
+	os.Exit(code)
+}
+
+// fatal stops the profiles and exits like log.Fatal
+func fatal(v ...interface{}) {
+	stopProfiling()
+	log.Fatal(v...)
+}
+
+// fatalf stops the profiles and exits like log.Fatalf
+func fatalf(format string, v ...interface{}) {
+	stopProfiling()
+	log.Fatalf(format, v...)
+}
+
+// startProfiling starts writing a CPU profile and an execution trace to the
+// given files, if any. The returned func stops them and writes the memory
+// profile, and must be called before exiting so the files are complete.
+func startProfiling(cpuFile, memFile, traceFile string) (func(), error) {
+	var stops []func()
+	stop := func() {
+		for i := len(stops) - 1; i >= 0; i-- {
+			stops[i]()
+		}
+		stops = nil
+	}
+
+	if cpuFile != "" {
+		f, err := os.Create(cpuFile)
+		if err != nil {
+			return nil, fmt.Errorf("task: could not create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("task: could not start CPU profile: %w", err)
+		}
+		stops = append(stops, func() {
+			pprof.StopCPUProfile()
+			f.Close()
+		})
+	}
+
+	if traceFile != "" {
+		f, err := os.Create(traceFile)
+		if err != nil {
+			stop()
+			return nil, fmt.Errorf("task: could not create trace: %w", err)
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			stop()
+			return nil, fmt.Errorf("task: could not start trace: %w", err)
+		}
+		stops = append(stops, func() {
+			trace.Stop()
+			f.Close()
+		})
+	}
+
+	if memFile != "" {
+		stops = append(stops, func() {
+			f, err := os.Create(memFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "task: could not create memory profile: %v\n", err)
+				return
+			}
+			defer f.Close()
+			runtime.GC()
+			if err := pprof.WriteHeapProfile(f); err != nil {
+				fmt.Fprintf(os.Stderr, "task: could not write memory profile: %v\n", err)
+			}
+		})
+	}
+
+	return stop, nil
+}
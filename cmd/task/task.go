@@ -3,17 +3,21 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 	"mvdan.cc/sh/v3/syntax"
 
 	"github.com/go-task/task/v3"
 	"github.com/go-task/task/v3/args"
+	"github.com/go-task/task/v3/internal/execext"
 	"github.com/go-task/task/v3/internal/logger"
 	"github.com/go-task/task/v3/taskfile"
 )
@@ -22,7 +26,7 @@ var (
 	version = ""
 )
 
-const usage = `Usage: task [-ilfwvsd] [--init] [--list] [--force] [--watch] [--verbose] [--silent] [--dir] [--taskfile] [--dry] [--summary] [task...]
+const usage = `Usage: task [-ilfwvsd] [--init] [--doctor] [--list] [--force] [--watch] [--verbose] [--silent] [--dir] [--taskfile] [--dry] [--summary] [task...]
 
 Runs the specified task(s). Falls back to the "default" task if no task name
 was specified, or lists all tasks if an unknown task name was specified.
@@ -45,6 +49,13 @@ Options:
 `
 
 func main() {
+	if execext.IsSandboxChild() {
+		os.Exit(execext.RunSandboxChild())
+	}
+	if execext.IsPrivilegedChild() {
+		os.Exit(execext.RunPrivilegedChild())
+	}
+
 	log.SetFlags(0)
 	log.SetOutput(os.Stderr)
 
@@ -57,6 +68,7 @@ func main() {
 		versionFlag bool
 		helpFlag    bool
 		init        bool
+		doctor      bool
 		list        bool
 		listAll     bool
 		status      bool
@@ -66,6 +78,8 @@ func main() {
 		silent      bool
 		dry         bool
 		summary     bool
+		config      bool
+		explain     string
 		exitCode    bool
 		parallel    bool
 		concurrency int
@@ -74,32 +88,122 @@ func main() {
 		output      taskfile.Output
 		color       bool
 		interval    string
+		dashboard   bool
+		debug       bool
+		debugFile   string
+		profileCPU  string
+		profileMem  string
+		traceFile   string
+		exportEnv   string
+		envrc       bool
+		reports     []string
+		provenance  string
+		provKey     string
+		export      string
+		pkg         string
+		tags        []string
+		filter      string
+		sortOrder   string
+		ci          bool
+		prompt      bool
+		logo        bool
+		notify      bool
+		jsonOutput  bool
+		promptInfo  bool
+		lenient     bool
+		inspect     bool
+		validate    bool
+		listIncl    bool
+		shuffle     bool
+		seed        int64
+		pick        bool
+		offline     bool
+		ownedBy     []string
+		checkOwners string
+		checkEnv    string
+		stats       int
+		graph       string
+		completion  string
 	)
 
 	pflag.BoolVar(&versionFlag, "version", false, "show Task version")
 	pflag.BoolVarP(&helpFlag, "help", "h", false, "shows Task usage")
 	pflag.BoolVarP(&init, "init", "i", false, "creates a new Taskfile.yaml in the current folder")
+	pflag.StringVar(&completion, "completion", "", `prints the completion script of the given shell, completing the flags and the task names: [bash|zsh|fish|powershell]`)
+	pflag.BoolVar(&doctor, "doctor", false, "checks the environment and the Taskfile for common problems, suggesting fixes")
+	pflag.BoolVar(&lenient, "lenient", false, "skips the included Taskfiles that can't be read, so the other tasks can still be listed and run, and marks the invalid tasks when listing")
+	pflag.BoolVar(&offline, "offline", false, "only includes the remote Taskfiles already cached, instead of fetching them")
+	pflag.BoolVar(&inspect, "inspect", false, "never runs commands, dynamic variables or dotenv files of the Taskfile, so untrusted ones can still be listed and summarized")
+	pflag.BoolVar(&validate, "validate", false, "checks the structure of the Taskfile and of the ones it includes, reporting unknown keys and values of the wrong type, without running anything")
+	pflag.BoolVar(&listIncl, "list-includes", false, "prints the tree of the included Taskfiles that were read, with their namespace, path, version and where the remote ones came from")
+	pflag.BoolVar(&pick, "pick", false, "picks the task to run by fuzzy searching the tasks. Done by default on a terminal when no task is given and there's no default task")
 	pflag.BoolVarP(&list, "list", "l", false, "lists tasks with description of current Taskfile")
 	pflag.BoolVarP(&listAll, "list-all", "a", false, "lists tasks with or without a description")
 	pflag.BoolVar(&status, "status", false, "exits with non-zero exit code if any of the given tasks is not up-to-date")
+	pflag.BoolVar(&jsonOutput, "json", false, "prints the state of each task given to --status as a line of JSON, or the plan of --dry or the tasks of --list as JSON")
 	pflag.BoolVarP(&force, "force", "f", false, "forces execution even when the task is up-to-date")
+	pflag.BoolVar(&shuffle, "shuffle", false, "runs the given tasks and the ones matched by wildcards in a random order, printing the seed used")
+	pflag.Int64Var(&seed, "seed", 0, "shuffles the order of the tasks with the given seed, to repeat the order of a previous run")
 	pflag.BoolVarP(&watch, "watch", "w", false, "enables watch of the given task")
 	pflag.BoolVarP(&verbose, "verbose", "v", false, "enables verbose mode")
 	pflag.BoolVarP(&silent, "silent", "s", false, "disables echoing")
 	pflag.BoolVarP(&parallel, "parallel", "p", false, "executes tasks provided on command line in parallel")
 	pflag.BoolVarP(&dry, "dry", "n", false, "compiles and prints tasks in the order that they would be run, without executing them")
 	pflag.BoolVar(&summary, "summary", false, "show summary about a task")
+	pflag.BoolVar(&config, "config", false, "shows the vars of a task with their values and asks for new ones, saved as overrides used whenever the task runs")
+	pflag.StringVar(&explain, "explain", "", "shows the vars an included Taskfile expects and its tasks, given its namespace")
+	pflag.StringVar(&exportEnv, "export-env", "", `prints the environment of the given task, or of the Taskfile, as commands to be evaluated by a shell: [sh|fish|powershell]`)
+	pflag.Lookup("export-env").NoOptDefVal = "sh"
+	pflag.StringVar(&pkg, "package", "", "bundles the Taskfile, the Taskfiles it includes and the files its commands reference into a .tar.gz archive other Taskfiles can include")
+	pflag.StringVar(&export, "export", "", `prints the tasks in a format used by other tools: [gha-matrix]`)
+	pflag.StringSliceVar(&tags, "tags", nil, "only lists or exports the tasks with any of the given comma-separated tags")
+	pflag.StringSliceVar(&ownedBy, "owned-by", nil, "only lists or exports the tasks owned by any of the given comma-separated owners, as set by their metadata or CODEOWNERS")
+	pflag.StringVar(&checkOwners, "check-owners", "", "warns about the tasks without an owner in the Taskfiles changed since the given git revision")
+	pflag.Lookup("check-owners").NoOptDefVal = "HEAD"
+	pflag.StringVar(&checkEnv, "check-env", "", `reports the variables used by the Taskfile and its dotenv files missing from the given example file, and the stale ones. Defaults to ".env.example"`)
+	pflag.Lookup("check-env").NoOptDefVal = ".env.example"
+	pflag.StringVar(&sortOrder, "sort", "", "order of the listed tasks: [default|alphanumeric|definition|none|topological]. Defaults to the one set in the Taskfile")
+	pflag.StringVar(&filter, "filter", "", "only lists or exports the tasks whose name, aliases, description or tags match the given case-insensitive regular expression")
+	pflag.BoolVar(&envrc, "envrc", false, `prints a direnv ".envrc" block that loads the environment of the Taskfile and reloads it when the Taskfile or dotenv files change`)
+	pflag.StringArrayVar(&reports, "report", nil, `writes a report of the executed tasks to a file, as "format=path". Available formats: "markdown", "manifest" and "provenance"`)
+	pflag.StringVar(&provenance, "provenance", "", "writes an in-toto SLSA provenance of the files generated by the executed tasks to the given file, like the \"provenance\" report")
+	pflag.StringVar(&provKey, "provenance-key", "", "signs the provenance with cosign and the given key, writing the signature bundle next to it")
+	pflag.IntVar(&stats, "stats", 0, "shows the tasks that ran in the given last days, 30 by default, starting with the ones that took the longest in total")
+	pflag.Lookup("stats").NoOptDefVal = "30"
+	pflag.StringVar(&graph, "graph", "", "prints the graph of the dependencies and calls of the given tasks, or of all of them, as [dot|mermaid]. Defaults to dot")
+	pflag.Lookup("graph").NoOptDefVal = "dot"
+	pflag.BoolVar(&notify, "notify", false, "sends a desktop notification when the tasks finish or fail")
 	pflag.BoolVarP(&exitCode, "exit-code", "x", false, "pass-through the exit code of the task command")
 	pflag.StringVarP(&dir, "dir", "d", "", "sets directory of execution")
 	pflag.StringVarP(&entrypoint, "taskfile", "t", "", `choose which Taskfile to run. Defaults to "Taskfile.yml"`)
-	pflag.StringVarP(&output.Name, "output", "o", "", "sets output style: [interleaved|group|prefixed]")
+	pflag.StringVarP(&output.Name, "output", "o", "", "sets output style: [interleaved|group|prefixed|plain]")
 	pflag.StringVar(&output.Group.Begin, "output-group-begin", "", "message template to print before a task's grouped output")
 	pflag.StringVar(&output.Group.End, "output-group-end", "", "message template to print after a task's grouped output")
 	pflag.BoolVarP(&color, "color", "c", true, "colored output. Enabled by default. Set flag to false or use NO_COLOR=1 to disable")
+	pflag.BoolVar(&ci, "ci", false, "enables CI mode: no prompts, no logo, no colors unless FORCE_COLOR is set and the group output style. Enabled by default when running on CI")
+	pflag.BoolVar(&prompt, "prompt", true, "prompts for missing variables. Disabled by default in CI mode")
+	pflag.BoolVar(&promptInfo, "prompt-info", false, "prints a line of JSON about the Taskfile for shell prompts: its path, number of tasks and whether its cache is stale")
+	pflag.BoolVar(&logo, "logo", true, "shows the logo when listing tasks. Disabled by default in CI mode")
 	pflag.IntVarP(&concurrency, "concurrency", "C", 0, "limit number tasks to run concurrently")
 	pflag.StringVarP(&interval, "interval", "I", "5s", "interval to watch for changes")
+	pflag.BoolVar(&dashboard, "dashboard", true, "shows a dashboard of the watched tasks when watching on a terminal, with keys to rerun or stop them")
+	pflag.BoolVar(&debug, "debug", false, "prints a trace of include resolution, variable precedence, fingerprinting and scheduling decisions to STDERR")
+	pflag.StringVar(&debugFile, "debug-file", "", "writes the debug trace to the given file instead of STDERR. Implies --debug")
+	pflag.StringVar(&profileCPU, "profile-cpu", "", "writes a CPU profile of the run to the given file")
+	pflag.StringVar(&profileMem, "profile-mem", "", "writes a memory profile of the run to the given file")
+	pflag.StringVar(&traceFile, "trace", "", "writes an execution trace of the run to the given file")
+	for _, name := range []string{"profile-cpu", "profile-mem", "trace"} {
+		_ = pflag.CommandLine.MarkHidden(name)
+	}
 	pflag.Parse()
 
+	stop, err := startProfiling(profileCPU, profileMem, traceFile)
+	if err != nil {
+		fatal(err)
+	}
+	stopProfiling = stop
+	defer stopProfiling()
+
 	if versionFlag {
 		fmt.Printf("Task version: %s\n", getVersion())
 		return
@@ -110,19 +214,64 @@ func main() {
 		return
 	}
 
+	if completion != "" {
+		if err := printCompletion(os.Stdout, completion); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
 	if init {
 		wd, err := os.Getwd()
 		if err != nil {
-			log.Fatal(err)
+			fatal(err)
 		}
 		if err := task.InitTaskfile(os.Stdout, wd); err != nil {
-			log.Fatal(err)
+			fatal(err)
+		}
+		return
+	}
+
+	if !pflag.CommandLine.Changed("ci") {
+		ci = detectCI()
+	}
+	if ci {
+		// Flags given explicitly always win over the defaults of CI mode
+		if !pflag.CommandLine.Changed("color") {
+			color = forceColor()
+		}
+		if !pflag.CommandLine.Changed("output") {
+			output.Name = "group"
+		}
+		if !pflag.CommandLine.Changed("prompt") {
+			prompt = false
 		}
+		if !pflag.CommandLine.Changed("logo") {
+			logo = false
+		}
+	}
+
+	if jsonOutput && !status && !dry && !list && !listAll {
+		fatal("task: You can't set --json without --status, --dry or --list")
 		return
 	}
 
+	if provKey != "" && provenance == "" {
+		fatal("task: You can't set --provenance-key without --provenance")
+		return
+	}
+	if provenance != "" {
+		reports = append(reports, "provenance="+provenance)
+	}
+
+	if pflag.CommandLine.Changed("seed") {
+		shuffle = true
+	} else if shuffle {
+		seed = time.Now().UnixNano()
+	}
+
 	if dir != "" && entrypoint != "" {
-		log.Fatal("task: You can't set both --dir and --taskfile")
+		fatal("task: You can't set both --dir and --taskfile")
 		return
 	}
 	if entrypoint != "" {
@@ -132,15 +281,27 @@ func main() {
 
 	if output.Name != "group" {
 		if output.Group.Begin != "" {
-			log.Fatal("task: You can't set --output-group-begin without --output=group")
+			fatal("task: You can't set --output-group-begin without --output=group")
 			return
 		}
 		if output.Group.End != "" {
-			log.Fatal("task: You can't set --output-group-end without --output=group")
+			fatal("task: You can't set --output-group-end without --output=group")
 			return
 		}
 	}
 
+	var debugWriter io.Writer
+	if debugFile != "" {
+		f, err := os.Create(debugFile)
+		if err != nil {
+			fatal(err)
+		}
+		defer f.Close()
+		debugWriter = f
+	} else if debug {
+		debugWriter = os.Stderr
+	}
+
 	e := task.Executor{
 		Force:       force,
 		Watch:       watch,
@@ -154,12 +315,41 @@ func main() {
 		Color:       color,
 		Concurrency: concurrency,
 		Interval:    interval,
+		Dashboard:   dashboard,
+		Reports:     reports,
+		NoPrompt:    !prompt,
+		NoLogo:      !logo,
+		Notify:      notify,
+		Lenient:     lenient,
+		Inspect:     inspect || validate,
+		Validate:    validate,
+		Shuffle:     shuffle,
+		Seed:        seed,
+		Offline:     offline,
+		Sort:        sortOrder,
+		ASCII:       !unicodeTerminal(),
 
 		Stdin:  os.Stdin,
 		Stdout: os.Stdout,
 		Stderr: os.Stderr,
+		Debug:  debugWriter,
+
+		OutputStyle:   output,
+		ProvenanceKey: provKey,
+	}
+
+	if promptInfo {
+		if err := e.PromptInfo(); err != nil {
+			fatal(err)
+		}
+		return
+	}
 
-		OutputStyle: output,
+	if doctor {
+		if ok := e.Doctor(); !ok {
+			exit(1)
+		}
+		return
 	}
 
 	if (list || listAll) && silent {
@@ -168,36 +358,106 @@ func main() {
 	}
 
 	if err := e.Setup(); err != nil {
-		log.Fatal(err)
+		fatal(err)
+	}
+	if validate {
+		e.Logger.Outf(logger.Green, "task: Taskfiles are valid")
+		return
+	}
+	if listIncl {
+		if err := e.ListIncludes(); err != nil {
+			fatal(err)
+		}
+		return
 	}
 	v, err := e.Taskfile.ParsedVersion()
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
+		return
+	}
+
+	var filters []task.FilterFunc
+	if len(tags) > 0 {
+		filters = append(filters, task.FilterOutUntagged(tags...))
+	}
+	if filter != "" {
+		if _, err := regexp.Compile(filter); err != nil {
+			fatalf("task: Invalid --filter %q: %v", filter, err)
+		}
+		filters = append(filters, task.FilterOutUnmatched(regexp.MustCompile("(?i)"+filter)))
+	}
+	if len(ownedBy) > 0 {
+		filters = append(filters, e.FilterOutNotOwnedBy(ownedBy...))
+	}
+
+	if pflag.CommandLine.Changed("stats") {
+		if err := e.Stats(stats); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if checkOwners != "" {
+		if err := e.CheckOwners(checkOwners); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if checkEnv != "" {
+		if err := e.CheckEnv(checkEnv); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if (list || listAll) && jsonOutput {
+		listFilters := append(filters, task.FilterOutInternal())
+		if list {
+			listFilters = append(listFilters, task.FilterOutNoDesc())
+		}
+		if err := e.ListTasksJSON(listFilters...); err != nil {
+			fatal(err)
+		}
 		return
 	}
 
 	if list {
-		if ok := e.ListTasks(task.FilterOutInternal(), task.FilterOutNoDesc()); !ok {
+		if ok := e.ListTasks(append(filters, task.FilterOutInternal(), task.FilterOutNoDesc())...); !ok {
 			e.Logger.Outf(logger.Yellow, "task: No tasks with description available. Try --list-all to list all tasks")
 		}
 		return
 	}
 
 	if listAll {
-		if ok := e.ListTasks(task.FilterOutInternal()); !ok {
+		if ok := e.ListTasks(append(filters, task.FilterOutInternal())...); !ok {
 			e.Logger.Outf(logger.Yellow, "task: No tasks available")
 		}
 		return
 	}
 
+	if pkg != "" {
+		if err := e.Package(pkg); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if explain != "" {
+		if err := e.Explain(explain); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
 	var (
 		calls   []taskfile.Call
 		globals *taskfile.Vars
 	)
 
-	tasksAndVars, cliArgs, err := getArgs()
+	tasksAndVars, cliArgsList, cliArgs, err := getArgs()
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
 
 	if v >= 3.0 {
@@ -207,6 +467,7 @@ func main() {
 	}
 
 	globals.Set("CLI_ARGS", taskfile.Var{Static: cliArgs})
+	globals.Set("CLI_ARGS_LIST", taskfile.Var{Live: cliArgsList})
 	e.Taskfile.Vars.Merge(globals)
 
 	if !watch {
@@ -215,9 +476,95 @@ func main() {
 
 	ctx := context.Background()
 
+	if graph != "" {
+		// Without a task, the graph of all the tasks is printed instead of
+		// the one of the default task
+		var graphCalls []taskfile.Call
+		for _, arg := range tasksAndVars {
+			if !strings.Contains(arg, "=") {
+				graphCalls = calls
+				break
+			}
+		}
+		if err := e.GraphTasks(graph, graphCalls...); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if exportEnv != "" {
+		// Without a task, the env of the Taskfile is exported instead of
+		// the one of the default task
+		var exportCalls []taskfile.Call
+		for _, arg := range tasksAndVars {
+			if !strings.Contains(arg, "=") {
+				exportCalls = calls
+				break
+			}
+		}
+		if err := e.ExportEnv(exportEnv, exportCalls...); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if export != "" {
+		if err := e.Export(export, filters...); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if envrc {
+		if err := e.Envrc(); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	// Without a task nor a default task, the task to run is picked on a
+	// terminal instead of failing
+	if _, err := e.GetTask(taskfile.Call{Task: "default"}); err != nil && !pick && !e.NoPrompt && e.CanPickTask() {
+		pick = true
+		for _, arg := range tasksAndVars {
+			if !strings.Contains(arg, "=") {
+				pick = false
+				break
+			}
+		}
+	}
+	if pick {
+		name, err := e.PickTask(filters...)
+		if err != nil {
+			fatal(err)
+		}
+		calls = []taskfile.Call{{Task: name}}
+	}
+
 	if status {
-		if err := e.Status(ctx, calls...); err != nil {
-			log.Fatal(err)
+		statusFunc := e.Status
+		if jsonOutput {
+			statusFunc = e.StatusJSON
+		}
+		if err := statusFunc(ctx, calls...); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if config {
+		if len(calls) != 1 {
+			fatal("task: --config takes exactly one task")
+		}
+		if err := e.Configure(calls[0]); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if dry && jsonOutput {
+		if err := e.PlanJSON(ctx, calls...); err != nil {
+			fatal(err)
 		}
 		return
 	}
@@ -227,32 +574,34 @@ func main() {
 
 		if exitCode {
 			if err, ok := err.(*task.TaskRunError); ok {
-				os.Exit(err.ExitCode())
+				exit(err.ExitCode())
 			}
 		}
-		os.Exit(1)
+		exit(1)
 	}
 }
 
-func getArgs() ([]string, string, error) {
+// getArgs returns the tasks and vars given on the command line, and the
+// arguments given after "--", both as a list and as a quoted string.
+func getArgs() ([]string, []string, string, error) {
 	var (
 		args          = pflag.Args()
 		doubleDashPos = pflag.CommandLine.ArgsLenAtDash()
 	)
 
 	if doubleDashPos == -1 {
-		return args, "", nil
+		return args, []string{}, "", nil
 	}
 
 	var quotedCliArgs []string
 	for _, arg := range args[doubleDashPos:] {
 		quotedCliArg, err := syntax.Quote(arg, syntax.LangBash)
 		if err != nil {
-			return nil, "", err
+			return nil, nil, "", err
 		}
 		quotedCliArgs = append(quotedCliArgs, quotedCliArg)
 	}
-	return args[:doubleDashPos], strings.Join(quotedCliArgs, " "), nil
+	return args[:doubleDashPos], args[doubleDashPos:], strings.Join(quotedCliArgs, " "), nil
 }
 
 func getVersion() string {
@@ -5,7 +5,12 @@ func (e *Executor) acquireConcurrencyLimit() func() {
 		return emptyFunc
 	}
 
-	e.concurrencySemaphore <- struct{}{}
+	select {
+	case e.concurrencySemaphore <- struct{}{}:
+	default:
+		e.Logger.Debugf("waiting for one of the %d concurrency slots to be free", e.Concurrency)
+		e.concurrencySemaphore <- struct{}{}
+	}
 	return func() {
 		<-e.concurrencySemaphore
 	}
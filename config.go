@@ -0,0 +1,144 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/prompt"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// overrides are the values given to the vars of tasks with "--config", by
+// task name
+type overrides map[string]map[string]string
+
+// overridesPath returns where the overrides set with "--config" are kept
+func (e *Executor) overridesPath() string {
+	return filepath.Join(e.TempDir, "overrides.yml")
+}
+
+func readOverrides(path string) (overrides, error) {
+	o := make(overrides)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return o, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("task: Invalid overrides file %s: %w", path, err)
+	}
+	return o, nil
+}
+
+func writeOverrides(path string, o overrides) error {
+	if len(o) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	data, err := yaml.Marshal(o)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// setupOverrides replaces the vars of tasks with the values set with
+// "--config". Overrides of vars tasks no longer declare are ignored.
+func (e *Executor) setupOverrides() error {
+	o, err := readOverrides(e.overridesPath())
+	if err != nil {
+		return err
+	}
+	for name, vars := range o {
+		t, ok := e.Taskfile.Tasks[name]
+		if !ok || t.Vars == nil {
+			continue
+		}
+		for key, value := range vars {
+			if v, ok := t.Vars.Mapping[key]; ok && !v.Secret {
+				t.Vars.Set(key, taskfile.Var{Static: value})
+			}
+		}
+	}
+	return nil
+}
+
+// Configure shows the vars of the task of the given call with their current
+// values, and asks for new ones. They're saved as overrides used whenever
+// the task runs. An empty answer keeps the current value, while "-" removes
+// the override. Secret vars are left out.
+func (e *Executor) Configure(call taskfile.Call) error {
+	if err := e.checkNotInspecting("configure tasks"); err != nil {
+		return err
+	}
+	t, err := e.GetTask(call)
+	if err != nil {
+		return err
+	}
+	if t.Vars.Len() == 0 {
+		return fmt.Errorf(`task: Task "%s" has no vars to configure`, t.Task)
+	}
+
+	path := e.overridesPath()
+	o, err := readOverrides(path)
+	if err != nil {
+		return err
+	}
+	current, err := e.Compiler.FastGetVariables(t, call)
+	if err != nil {
+		return err
+	}
+
+	values := o[t.Task]
+	if values == nil {
+		values = make(map[string]string)
+	}
+	prompter := &prompt.Prompter{Stdin: e.Stdin, Stderr: e.Stderr}
+	err = t.Vars.Range(func(name string, v taskfile.Var) error {
+		if v.Secret {
+			return nil
+		}
+		msg := fmt.Sprintf("%s [%s]", name, current.Mapping[name].Static)
+		if _, ok := values[name]; ok {
+			msg += " (overridden)"
+		}
+		answer, err := prompter.Text(msg + ": ")
+		switch {
+		case errors.Is(err, prompt.ErrNoValue):
+			return nil
+		case err != nil:
+			return err
+		case answer == "-":
+			delete(values, name)
+		default:
+			values[name] = answer
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(values) == 0 {
+		delete(o, t.Task)
+	} else {
+		o[t.Task] = values
+	}
+	if err := writeOverrides(path, o); err != nil {
+		return err
+	}
+	e.Logger.Errf(logger.Green, "task: Saved %d overrides of task \"%s\" to %s", len(values), t.Task, path)
+	return nil
+}
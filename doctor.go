@@ -0,0 +1,222 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/logger"
+)
+
+const (
+	// inotifyWatchesFile holds the maximum number of inotify watches per user
+	// on Linux.
+	inotifyWatchesFile = "/proc/sys/fs/inotify/max_user_watches"
+	// minInotifyWatches is the limit under which watchers of big projects
+	// start failing with "no space left on device".
+	minInotifyWatches = 65536
+)
+
+type doctorStatus int
+
+const (
+	doctorOK doctorStatus = iota
+	doctorWarn
+	doctorFail
+)
+
+// doctorCheck is the result of a single diagnostic done by Doctor.
+type doctorCheck struct {
+	status  doctorStatus
+	name    string
+	message string
+	fix     string
+}
+
+// Doctor checks the environment Task is running on: shell availability,
+// watcher limits, terminal capabilities, PATH, the Taskfile and the cache
+// directory. It prints one line per check, followed by a suggested fix for
+// the problems found, and returns false if any check failed.
+func (e *Executor) Doctor() bool {
+	e.setupStdFiles()
+	e.setupLogger()
+
+	checks := []doctorCheck{
+		checkShell(),
+		checkInotifyWatches(inotifyWatchesFile),
+		e.checkTerminal(),
+		checkPath(os.Getenv("PATH")),
+	}
+	taskfileCheck := e.checkTaskfile()
+	checks = append(checks, taskfileCheck)
+	if taskfileCheck.status == doctorOK {
+		checks = append(checks, e.checkTempDir())
+	}
+
+	ok := true
+	for _, c := range checks {
+		switch c.status {
+		case doctorOK:
+			e.Logger.Outf(logger.Green, "[ok]   %s: %s", c.name, c.message)
+		case doctorWarn:
+			e.Logger.Outf(logger.Yellow, "[warn] %s: %s", c.name, c.message)
+		case doctorFail:
+			ok = false
+			e.Logger.Outf(logger.Red, "[fail] %s: %s", c.name, c.message)
+		}
+		if c.fix != "" {
+			e.Logger.Outf(logger.Default, "       fix: %s", c.fix)
+		}
+	}
+	return ok
+}
+
+func checkShell() doctorCheck {
+	c := doctorCheck{name: "Shell"}
+	path, err := exec.LookPath("sh")
+	if err != nil {
+		c.status = doctorWarn
+		c.message = `"sh" was not found in PATH. Commands run with Task's built-in interpreter, but scripts calling "sh" or "bash" directly will fail`
+		if runtime.GOOS == "windows" {
+			c.fix = "install Git for Windows and add its usr/bin directory to PATH"
+		} else {
+			c.fix = "install a POSIX shell and make sure it is in PATH"
+		}
+		return c
+	}
+	c.message = fmt.Sprintf(`"sh" found at %s`, path)
+	return c
+}
+
+func checkInotifyWatches(file string) doctorCheck {
+	c := doctorCheck{name: "Watcher limits"}
+	if runtime.GOOS != "linux" {
+		c.message = "no limits to check on " + runtime.GOOS
+		return c
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		c.status = doctorWarn
+		c.message = fmt.Sprintf("could not read %s: %v", file, err)
+		return c
+	}
+	watches, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		c.status = doctorWarn
+		c.message = fmt.Sprintf("unexpected content in %s: %q", file, strings.TrimSpace(string(data)))
+		return c
+	}
+	if watches < minInotifyWatches {
+		c.status = doctorWarn
+		c.message = fmt.Sprintf("fs.inotify.max_user_watches is %d, which is too low for file watchers of big projects", watches)
+		c.fix = `run "sudo sysctl fs.inotify.max_user_watches=524288" and add the setting to /etc/sysctl.conf to persist it`
+		return c
+	}
+	c.message = fmt.Sprintf("fs.inotify.max_user_watches is %d", watches)
+	return c
+}
+
+func (e *Executor) checkTerminal() doctorCheck {
+	c := doctorCheck{name: "Terminal"}
+
+	var notes []string
+	if f, ok := e.Stdout.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		notes = append(notes, "stdout is a terminal")
+	} else {
+		notes = append(notes, "stdout is not a terminal")
+	}
+	if f, ok := e.Stdin.(*os.File); !ok || !term.IsTerminal(int(f.Fd())) {
+		c.status = doctorWarn
+		notes = append(notes, "stdin is not a terminal, so prompts and interactive tasks won't work")
+		c.fix = "run Task from an interactive terminal or pass the prompted variables in the command line"
+	}
+	switch {
+	case os.Getenv("NO_COLOR") != "":
+		notes = append(notes, "colors disabled by NO_COLOR")
+	case os.Getenv("TERM") == "dumb":
+		notes = append(notes, "TERM is dumb")
+	}
+
+	c.message = strings.Join(notes, ", ")
+	return c
+}
+
+func checkPath(path string) doctorCheck {
+	c := doctorCheck{name: "PATH"}
+	if path == "" {
+		c.status = doctorFail
+		c.message = "PATH is empty, so no commands can be found"
+		c.fix = "set the PATH environment variable in your shell profile"
+		return c
+	}
+
+	var missing []string
+	entries := filepath.SplitList(path)
+	for _, dir := range entries {
+		if dir == "" {
+			continue
+		}
+		if _, err := os.Stat(filepathext.LongPath(dir)); err != nil {
+			missing = append(missing, dir)
+		}
+	}
+	if len(missing) > 0 {
+		c.status = doctorWarn
+		c.message = fmt.Sprintf("%d of %d entries don't exist: %s", len(missing), len(entries), strings.Join(missing, ", "))
+		c.fix = "remove the missing directories from PATH in your shell profile"
+		return c
+	}
+	c.message = fmt.Sprintf("%d entries", len(entries))
+	return c
+}
+
+func (e *Executor) checkTaskfile() doctorCheck {
+	c := doctorCheck{name: "Taskfile"}
+	if err := e.Setup(); err != nil {
+		c.status = doctorFail
+		c.message = err.Error()
+		c.fix = "fix the error above and check the Taskfile against https://taskfile.dev/schema.json"
+		return c
+	}
+	v, _ := e.Taskfile.ParsedVersion()
+	c.message = fmt.Sprintf("valid, version %v, %d tasks", v, len(e.Taskfile.Tasks))
+	return c
+}
+
+func (e *Executor) checkTempDir() doctorCheck {
+	c := doctorCheck{name: "Cache directory"}
+
+	// The directory is only created when needed, so check the closest
+	// existing parent instead.
+	dir := e.TempDir
+	for {
+		if _, err := os.Stat(filepathext.LongPath(dir)); err == nil || filepath.Dir(dir) == dir {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+
+	f, err := os.CreateTemp(filepathext.LongPath(dir), "doctor")
+	if err != nil {
+		c.status = doctorFail
+		c.message = fmt.Sprintf("%s is not writable: %v", dir, err)
+		c.fix = "check the permissions of the directory or set TASK_TEMP_DIR to a writable directory"
+		return c
+	}
+	f.Close()
+	_ = os.Remove(f.Name())
+
+	if dir != e.TempDir {
+		c.message = fmt.Sprintf("%s doesn't exist yet, but can be created", e.TempDir)
+		return c
+	}
+	c.message = fmt.Sprintf("%s is writable", e.TempDir)
+	return c
+}
@@ -0,0 +1,137 @@
+package task
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// sensitiveEnvName matches the names of environment variables whose values
+// are redacted even if they're not declared as secret
+var sensitiveEnvName = regexp.MustCompile(`(?i)token|secret|passw(or)?d|api_?key|private_?key|credential`)
+
+// logEnviron prints the differences between the environment a command of the
+// task runs with and the one of Task itself, in verbose mode and to the
+// debug trace. A nil environ means the one of Task is used as is.
+func (e *Executor) logEnviron(t *taskfile.Task, environ []string) {
+	if !e.Verbose && !e.Logger.IsDebug() {
+		return
+	}
+
+	name := e.redact(t.Name())
+	lines := environDiff(os.Environ(), environ)
+	if len(lines) == 0 {
+		e.Logger.VerboseErrf(logger.Yellow, "task: [%s] environment: same as Task's", name)
+		e.Logger.Debugf("[%s] environment: same as Task's", name)
+		return
+	}
+
+	e.Logger.VerboseErrf(logger.Yellow, "task: [%s] environment, compared to Task's:", name)
+	e.Logger.Debugf("[%s] environment, compared to Task's:", name)
+	for _, line := range lines {
+		line = e.redactEnv(line)
+		e.Logger.VerboseErrf(logger.Yellow, "task: [%s]   %s", name, line)
+		e.Logger.Debugf("[%s]   %s", name, line)
+	}
+}
+
+// environDiff returns a line for each variable added ("+ KEY=value"),
+// changed ("~ KEY=value (was old)") or removed ("- KEY=old") by environ,
+// sorted by name
+func environDiff(parent, environ []string) []string {
+	if environ == nil {
+		return nil
+	}
+
+	before := environMap(parent)
+	after := environMap(environ)
+	keys := make([]string, 0, len(after))
+	for k := range after {
+		keys = append(keys, k)
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, k := range keys {
+		old, existed := before[k]
+		value, exists := after[k]
+		switch {
+		case !exists:
+			lines = append(lines, "- "+k+"="+old)
+		case !existed:
+			lines = append(lines, "+ "+k+"="+value)
+		case old != value:
+			lines = append(lines, "~ "+k+"="+value+" (was "+old+")")
+		}
+	}
+	return lines
+}
+
+// filterEnviron returns the variables of the given environment passed to
+// the commands of the task, following its "env_passthrough" and "env_block"
+func filterEnviron(t *taskfile.Task, environ []string) []string {
+	if t.EnvPassthrough == nil && t.EnvBlock == nil {
+		return environ
+	}
+
+	filtered := make([]string, 0, len(environ))
+	for _, kv := range environ {
+		k, _, _ := strings.Cut(kv, "=")
+		if envPassedThrough(t, k) {
+			filtered = append(filtered, kv)
+		}
+	}
+	return filtered
+}
+
+// envPassedThrough reports whether the variable of the environment of Task
+// with the given name is passed to the commands of the task. Variables must
+// match a pattern of "env_passthrough", when set, and none of "env_block".
+func envPassedThrough(t *taskfile.Task, name string) bool {
+	if t.EnvPassthrough != nil && !matchEnvName(t.EnvPassthrough, name) {
+		return false
+	}
+	return !matchEnvName(t.EnvBlock, name)
+}
+
+func matchEnvName(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// environMap parses the given "KEY=value" variables. As for processes, the
+// last value of a variable set more than once wins.
+func environMap(environ []string) map[string]string {
+	m := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// redactEnv redacts the values of secret variables from a line of
+// environDiff, as well as the whole value of variables with sensitive names
+func (e *Executor) redactEnv(line string) string {
+	line = e.redact(line)
+	k, _, _ := strings.Cut(line[2:], "=")
+	if sensitiveEnvName.MatchString(k) {
+		return line[:2] + k + "=" + taskfile.RedactedValue
+	}
+	return line
+}
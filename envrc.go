@@ -0,0 +1,83 @@
+package task
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Envrc prints a block to be used as a direnv ".envrc" file. It loads the
+// environment of the Taskfile with "task --export-env" and watches the
+// Taskfiles and dotenv files, so direnv reloads it when any of them change.
+func (e *Executor) Envrc() error {
+	var b strings.Builder
+	b.WriteString("# Generated by \"task --envrc\". Keep it in sync by running it again after\n")
+	b.WriteString("# adding Taskfiles or dotenv files.\n")
+	for _, path := range e.envrcWatchedFiles() {
+		fmt.Fprintf(&b, "watch_file %s\n", shellQuote(path))
+	}
+
+	cmd := "task"
+	if e.Entrypoint != "" {
+		cmd += " --taskfile " + shellQuote(filepath.ToSlash(e.Entrypoint))
+	}
+	fmt.Fprintf(&b, "eval \"$(%s --export-env)\"\n", cmd)
+
+	_, err := fmt.Fprint(e.Stdout, b.String())
+	return err
+}
+
+// envrcWatchedFiles returns the Taskfiles and dotenv files, relative to the
+// directory of the Taskfile when possible. Missing dotenv files are included,
+// so creating them also triggers a reload.
+func (e *Executor) envrcWatchedFiles() []string {
+	files := e.taskfileFiles()
+	for i, path := range files {
+		if rel, err := filepath.Rel(e.Dir, path); err == nil {
+			path = rel
+		}
+		files[i] = filepath.ToSlash(path)
+	}
+	return files
+}
+
+// taskfileFiles returns the paths of the Taskfiles read, sorted, followed by
+// the dotenv files in their order of precedence
+func (e *Executor) taskfileFiles() []string {
+	seen := make(map[string]bool)
+	var taskfiles []string
+	add := func(files *[]string, path string) {
+		if path == "" || seen[path] {
+			return
+		}
+		seen[path] = true
+		*files = append(*files, path)
+	}
+
+	for _, l := range e.Taskfile.Locations {
+		add(&taskfiles, l.Taskfile)
+	}
+	for _, t := range e.Taskfile.Tasks {
+		add(&taskfiles, t.Taskfile)
+	}
+	sort.Strings(taskfiles)
+
+	var dotenvFiles []string
+	for _, path := range e.dotenvFiles {
+		add(&dotenvFiles, path)
+	}
+	return append(taskfiles, dotenvFiles...)
+}
+
+var shellSafeRegexp = regexp.MustCompile(`^[A-Za-z0-9_./-]+$`)
+
+// shellQuote quotes the given string to be used as a single word by a POSIX
+// shell, leaving it unquoted when that's not needed
+func shellQuote(s string) string {
+	if shellSafeRegexp.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, `'`, `'\''`) + "'"
+}
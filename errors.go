@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/go-task/task/v3/taskfile"
+
 	"mvdan.cc/sh/v3/interp"
 )
 
@@ -34,6 +36,38 @@ func (err *taskNotFoundError) Error() string {
 	return fmt.Sprintf(`task: Task %q does not exist`, err.taskName)
 }
 
+type brokenIncludeError struct {
+	taskName string
+	include  *taskfile.BrokenInclude
+}
+
+func (err *brokenIncludeError) Error() string {
+	return fmt.Sprintf(
+		`task: Task %q can't be run because the included Taskfile %q is invalid: %s`,
+		err.taskName,
+		err.include.Namespace,
+		strings.TrimPrefix(err.include.Err, "task: "),
+	)
+}
+
+// withCallLocation annotates the error of calling a task that doesn't exist
+// with the location of the call, like a dep or a cmd
+func withCallLocation(err error, location taskfile.Location) error {
+	if _, ok := err.(*taskNotFoundError); ok {
+		return taskfile.WithLocation(err, location)
+	}
+	return err
+}
+
+type invalidTaskPatternError struct {
+	pattern string
+	err     error
+}
+
+func (err *invalidTaskPatternError) Error() string {
+	return fmt.Sprintf(`task: Invalid task pattern %q: %v`, err.pattern, err.err)
+}
+
 type multipleTasksWithAliasError struct {
 	aliasName string
 	taskNames []string
@@ -0,0 +1,30 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/summary"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// Explain prints the vars the Taskfile included under the given namespace
+// expects, as declared by its params, and its tasks
+func (e *Executor) Explain(namespace string) error {
+	namespace = strings.TrimSuffix(namespace, taskfile.NamespaceSeparator)
+	prefix := namespace + taskfile.NamespaceSeparator
+
+	var tasks []*taskfile.Task
+	for _, t := range e.sortedTasks() {
+		if strings.HasPrefix(t.Task, prefix) && !t.Internal {
+			tasks = append(tasks, t)
+		}
+	}
+	params, ok := e.Taskfile.IncludedParams[namespace]
+	if !ok && len(tasks) == 0 {
+		return fmt.Errorf(`task: Namespace "%s" does not exist`, namespace)
+	}
+
+	summary.PrintNamespace(e.Logger, namespace, params, tasks)
+	return nil
+}
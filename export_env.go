@@ -0,0 +1,100 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+var envNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// exportFormats are the syntaxes used to set an environment variable, by shell
+var exportFormats = map[string]func(key, value string) string{
+	"sh": func(key, value string) string {
+		return fmt.Sprintf("export %s='%s'", key, strings.ReplaceAll(value, `'`, `'\''`))
+	},
+	"fish": func(key, value string) string {
+		value = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+		return fmt.Sprintf("set -gx %s '%s'", key, value)
+	},
+	"powershell": func(key, value string) string {
+		return fmt.Sprintf("$env:%s = '%s'", key, strings.ReplaceAll(value, `'`, `''`))
+	},
+}
+
+// ExportEnv prints the environment variables the commands of the given task
+// would get, or the ones of the Taskfile when no task is given, in the syntax
+// of the given shell, so they can be evaluated by it. Variables already set
+// in the environment are not printed, unless overridden by a dotenv file.
+func (e *Executor) ExportEnv(shell string, calls ...taskfile.Call) error {
+	if err := e.checkNotInspecting("export the environment"); err != nil {
+		return err
+	}
+	format, ok := exportFormats[shell]
+	if !ok {
+		return fmt.Errorf(`task: Unsupported shell %q to export the environment. Available options: "sh", "fish" and "powershell"`, shell)
+	}
+	if len(calls) > 1 {
+		return fmt.Errorf("task: Only one task can have its environment exported at a time")
+	}
+
+	var env *taskfile.Vars
+	if len(calls) == 1 {
+		t, err := e.CompiledTask(calls[0])
+		if err != nil {
+			return err
+		}
+		env = t.Env
+	} else {
+		var err error
+		if env, err = e.taskfileEnv(); err != nil {
+			return err
+		}
+	}
+
+	return env.Range(func(key string, value taskfile.Var) error {
+		if _, alreadySet := os.LookupEnv(key); alreadySet && !e.envOverrides[key] {
+			return nil
+		}
+		if value.Live != nil {
+			return nil
+		}
+		if !envNameRegexp.MatchString(key) {
+			return fmt.Errorf("task: %q is not a valid environment variable name", key)
+		}
+		fmt.Fprintln(e.Stdout, format(key, value.Static))
+		return nil
+	})
+}
+
+// taskfileEnv returns the env of the Taskfile, with its templates and
+// dynamic variables resolved
+func (e *Executor) taskfileEnv() (*taskfile.Vars, error) {
+	vars, err := e.Compiler.GetTaskfileVariables()
+	if err != nil {
+		return nil, err
+	}
+
+	r := templater.Templater{Vars: vars, RemoveNoValue: true}
+	env := &taskfile.Vars{}
+	env.Merge(r.ReplaceVars(e.Taskfile.Env))
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	err = env.Range(func(k string, v taskfile.Var) error {
+		if v.Live != nil {
+			return nil
+		}
+		static, err := e.Compiler.HandleDynamicVar(v, e.Dir)
+		if err != nil {
+			return err
+		}
+		env.Set(k, taskfile.Var{Static: static})
+		return nil
+	})
+	return env, err
+}
@@ -0,0 +1,73 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// matrixEntry is a job of a CI matrix, running a single task
+type matrixEntry struct {
+	Task string            `json:"task"`
+	Vars map[string]string `json:"vars"`
+}
+
+// Export prints the tasks not removed by the given filters in the given
+// format. The only format available is "gha-matrix": a GitHub Actions
+// "strategy.matrix", with one job per task, including the task name and the
+// static values of its vars.
+func (e *Executor) Export(format string, filters ...FilterFunc) error {
+	if format != "gha-matrix" {
+		return fmt.Errorf(`task: Unsupported export format %q. Available options: "gha-matrix"`, format)
+	}
+
+	entries := make([]matrixEntry, 0)
+	filters = append([]FilterFunc{FilterOutInternal()}, filters...)
+	err := e.RangeTaskList(func(t *taskfile.Task) error {
+		vars, err := e.matrixVars(t)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, matrixEntry{Task: t.Task, Vars: vars})
+		return nil
+	}, filters...)
+	if err != nil {
+		return err
+	}
+
+	// A single line, so it can be written to $GITHUB_OUTPUT as is
+	out, err := json.Marshal(map[string][]matrixEntry{"include": entries})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(e.Stdout, string(out))
+	return err
+}
+
+// matrixVars returns the vars declared by the task, templated, except the
+// dynamic ones, which would need to run commands
+func (e *Executor) matrixVars(t *taskfile.Task) (map[string]string, error) {
+	vars := make(map[string]string, t.Vars.Len())
+	if t.Vars.Len() == 0 {
+		return vars, nil
+	}
+
+	all, err := e.Compiler.FastGetVariables(t, taskfile.Call{Task: t.Task})
+	if err != nil {
+		return nil, err
+	}
+	r := templater.Templater{Vars: all, RemoveNoValue: true}
+	err = t.Vars.Range(func(k string, v taskfile.Var) error {
+		if v.Sh != "" || v.Live != nil {
+			return nil
+		}
+		vars[k] = r.Replace(v.Static)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vars, r.Err()
+}
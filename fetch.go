@@ -0,0 +1,41 @@
+package task
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/go-task/task/v3/internal/fetch"
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// runFetch downloads the file of a "fetch" command. Files with a checksum
+// are kept in the temp dir, so they're downloaded only once.
+func (e *Executor) runFetch(ctx context.Context, t *taskfile.Task, cmd *taskfile.Cmd) error {
+	dest := filepathext.SmartJoin(t.Dir, cmd.Fetch.Dest)
+	if e.Verbose || (!cmd.Silent && !t.Silent && !e.Taskfile.Silent && !e.Silent) {
+		e.Logger.Errf(logger.Green, "task: [%s] fetch %s -> %s", e.redact(t.Name()), e.redact(cmd.Fetch.URL), dest)
+	}
+	if e.Dry {
+		return nil
+	}
+	if err := e.checkPolicy(ctx, t, &PolicyCommand{Kind: "fetch", URL: cmd.Fetch.URL}); err != nil {
+		return err
+	}
+
+	downloaded, err := fetch.Download(ctx, &fetch.Options{
+		URL:        cmd.Fetch.URL,
+		Dest:       dest,
+		Checksum:   cmd.Fetch.Checksum,
+		Executable: cmd.Fetch.Executable,
+		CacheDir:   filepath.Join(e.TempDir, "fetch"),
+	})
+	if err != nil {
+		return taskfile.WithLocation(err, cmd.Location)
+	}
+	if !downloaded {
+		e.Logger.VerboseErrf(logger.Magenta, "task: [%s] %s is up to date", t.Name(), dest)
+	}
+	return nil
+}
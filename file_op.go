@@ -0,0 +1,66 @@
+package task
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-task/task/v3/internal/fileops"
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// runFileOp runs a "copy", "mkdir", "rm" or "template" command. Paths are
+// relative to the task directory.
+func (e *Executor) runFileOp(t *taskfile.Task, call taskfile.Call, cmd *taskfile.Cmd) error {
+	op := cmd.FileOp
+	path := filepathext.SmartJoin(t.Dir, op.Path)
+	var dest string
+	if op.Dest != "" {
+		dest = filepathext.SmartJoin(t.Dir, op.Dest)
+	}
+
+	if e.Verbose || (!cmd.Silent && !t.Silent && !e.Taskfile.Silent && !e.Silent) {
+		if dest != "" {
+			e.Logger.Errf(logger.Green, "task: [%s] %s %s %s", e.redact(t.Name()), op.Op, path, dest)
+		} else {
+			e.Logger.Errf(logger.Green, "task: [%s] %s %s", e.redact(t.Name()), op.Op, path)
+		}
+	}
+	if e.Dry {
+		return nil
+	}
+
+	var err error
+	switch op.Op {
+	case taskfile.FileOpCopy:
+		err = fileops.Copy(path, dest)
+	case taskfile.FileOpMkdir:
+		err = os.MkdirAll(path, 0o755)
+	case taskfile.FileOpRm:
+		err = os.RemoveAll(path)
+	case taskfile.FileOpTemplate:
+		err = e.renderTemplate(t, call, path, dest)
+	}
+	return taskfile.WithLocation(err, cmd.Location)
+}
+
+// renderTemplate writes the file at src to dest, with the variables of the
+// task replaced
+func (e *Executor) renderTemplate(t *taskfile.Task, call taskfile.Call, src, dest string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	vars, err := e.Compiler.GetVariables(t, call)
+	if err != nil {
+		return fmt.Errorf("task: failed to get variables: %w", err)
+	}
+	r := &templater.Templater{Vars: vars, RemoveNoValue: true}
+	rendered := r.Replace(string(data))
+	if err := r.Err(); err != nil {
+		return fmt.Errorf("task: failed to render %s: %w", src, err)
+	}
+	return fileops.WriteFile(dest, []byte(rendered))
+}
@@ -0,0 +1,44 @@
+package task
+
+import (
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// parseCliFlags parses the arguments given after "--" for each of the given
+// calls whose task declares flags, setting the resulting variables on them.
+func (e *Executor) parseCliFlags(calls []taskfile.Call) error {
+	var args []string
+	if v, ok := e.Taskfile.Vars.Mapping["CLI_ARGS_LIST"]; ok {
+		args, _ = v.Live.([]string)
+	}
+
+	for i, call := range calls {
+		if isTaskPattern(call.Task) {
+			continue
+		}
+		t, err := e.GetTask(call)
+		if err != nil {
+			return err
+		}
+		if calls[i], err = withFlagVars(call, t, args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withFlagVars returns a copy of the given call with the variables of the
+// flags declared by the task set, parsed from the given arguments. Variables
+// already set on the call take precedence over flag defaults.
+func withFlagVars(call taskfile.Call, t *taskfile.Task, args []string) (taskfile.Call, error) {
+	if t.Flags.Len() == 0 {
+		return call, nil
+	}
+
+	vars, err := taskfile.ParseFlags(t.Flags, args, call.Vars)
+	if err != nil {
+		return call, err
+	}
+	vars.Merge(call.Vars)
+	return taskfile.Call{Task: call.Task, Vars: vars}, nil
+}
@@ -0,0 +1,188 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// graphWriters are the supported formats of the dependency graph
+var graphWriters = map[string]func(w io.Writer, g *taskGraph) error{
+	"dot":     writeDotGraph,
+	"mermaid": writeMermaidGraph,
+}
+
+// taskGraph is the graph of the tasks and of the tasks they depend on or
+// call
+type taskGraph struct {
+	tasks []*taskfile.Task
+	edges []graphEdge
+}
+
+type graphEdge struct {
+	from, to *taskfile.Task
+	// call is set when the task is called by a command, instead of being a
+	// dependency
+	call bool
+}
+
+// GraphTasks prints the graph of the dependencies of the given tasks, and of
+// the tasks their commands call, in the DOT or Mermaid format. The tasks of
+// included Taskfiles are grouped by namespace. Without tasks, the graph of all
+// the tasks of the Taskfile is printed.
+func (e *Executor) GraphTasks(format string, calls ...taskfile.Call) error {
+	write, ok := graphWriters[format]
+	if !ok {
+		return fmt.Errorf(`task: Unsupported graph format %q. Available options: "dot" and "mermaid"`, format)
+	}
+
+	var roots []*taskfile.Task
+	if len(calls) == 0 {
+		roots = e.sortedTasks()
+	}
+	for _, call := range calls {
+		t, err := e.GetTask(call)
+		if err != nil {
+			return err
+		}
+		roots = append(roots, t)
+	}
+
+	g := &taskGraph{}
+	visited := make(map[*taskfile.Task]bool)
+	seenEdges := make(map[graphEdge]bool)
+	var visit func(t *taskfile.Task)
+	addEdge := func(from *taskfile.Task, name string, call bool) {
+		// Names given by variables are only known when the task is
+		// compiled, so they're left out
+		to, err := e.GetTask(taskfile.Call{Task: taskfile.ResolveReference(name, from.Namespace)})
+		if err != nil {
+			return
+		}
+		if edge := (graphEdge{from: from, to: to, call: call}); !seenEdges[edge] {
+			seenEdges[edge] = true
+			g.edges = append(g.edges, edge)
+		}
+		visit(to)
+	}
+	visit = func(t *taskfile.Task) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		g.tasks = append(g.tasks, t)
+
+		for _, dep := range taskfile.AllDeps(t.Deps) {
+			addEdge(t, dep.Task, false)
+		}
+		for _, cmd := range t.Cmds {
+			if cmd != nil && cmd.Task != "" {
+				addEdge(t, cmd.Task, true)
+			}
+		}
+	}
+	for _, t := range roots {
+		visit(t)
+	}
+
+	return write(e.Stdout, g)
+}
+
+// namespaces returns the tasks of the graph grouped by namespace, with the
+// namespaces sorted and the tasks of the root Taskfile first
+func (g *taskGraph) namespaces() ([]string, map[string][]*taskfile.Task) {
+	byNamespace := make(map[string][]*taskfile.Task)
+	for _, t := range g.tasks {
+		byNamespace[t.Namespace] = append(byNamespace[t.Namespace], t)
+	}
+	namespaces := make([]string, 0, len(byNamespace))
+	for namespace := range byNamespace {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces, byNamespace
+}
+
+// writeDotGraph writes the graph in the DOT language of Graphviz, with the
+// tasks of each namespace in a cluster and the calls as dashed edges
+func writeDotGraph(w io.Writer, g *taskGraph) error {
+	var b strings.Builder
+	b.WriteString("digraph tasks {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	namespaces, byNamespace := g.namespaces()
+	for i, namespace := range namespaces {
+		indent := "  "
+		if namespace != "" {
+			fmt.Fprintf(&b, "  subgraph cluster_%d {\n", i)
+			fmt.Fprintf(&b, "    label=%s;\n", dotQuote(namespace))
+			indent = "    "
+		}
+		for _, t := range byNamespace[namespace] {
+			fmt.Fprintf(&b, "%s%s;\n", indent, dotQuote(t.Task))
+		}
+		if namespace != "" {
+			b.WriteString("  }\n")
+		}
+	}
+
+	for _, edge := range g.edges {
+		fmt.Fprintf(&b, "  %s -> %s", dotQuote(edge.from.Task), dotQuote(edge.to.Task))
+		if edge.call {
+			b.WriteString(" [style=dashed]")
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// writeMermaidGraph writes the graph as a Mermaid flowchart, with the tasks
+// of each namespace in a subgraph and the calls as dotted edges
+func writeMermaidGraph(w io.Writer, g *taskGraph) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	// Task names may have characters Mermaid doesn't allow in ids
+	ids := make(map[*taskfile.Task]string, len(g.tasks))
+	namespaces, byNamespace := g.namespaces()
+	for i, namespace := range namespaces {
+		indent := "  "
+		if namespace != "" {
+			fmt.Fprintf(&b, "  subgraph ns%d [%s]\n", i, mermaidQuote(namespace))
+			indent = "    "
+		}
+		for _, t := range byNamespace[namespace] {
+			ids[t] = fmt.Sprintf("t%d", len(ids))
+			fmt.Fprintf(&b, "%s%s[%s]\n", indent, ids[t], mermaidQuote(t.Task))
+		}
+		if namespace != "" {
+			b.WriteString("  end\n")
+		}
+	}
+
+	for _, edge := range g.edges {
+		arrow := "-->"
+		if edge.call {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", ids[edge.from], arrow, ids[edge.to])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
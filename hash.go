@@ -8,9 +8,9 @@ import (
 )
 
 func (e *Executor) GetHash(t *taskfile.Task) (string, error) {
-	r := t.Run
+	r, location := t.Run, t.Locations["run"]
 	if r == "" {
-		r = e.Taskfile.Run
+		r, location = e.Taskfile.Run, e.Taskfile.Locations["run"]
 	}
 
 	var h hash.HashFunc
@@ -22,7 +22,11 @@ func (e *Executor) GetHash(t *taskfile.Task) (string, error) {
 	case "when_changed":
 		h = hash.Hash
 	default:
-		return "", fmt.Errorf(`task: invalid run "%s"`, r)
+		return "", taskfile.WithLocation(fmt.Errorf(`task: invalid run "%s"`, r), location)
+	}
+	// The instances of a matrix task are told apart by their label
+	if t.Matrix != nil && r == "once" {
+		return t.Name(), nil
 	}
 	return h(t)
 }
@@ -1,6 +1,7 @@
 package task
 
 import (
+	"bufio"
 	_ "embed"
 	"encoding/base64"
 	"fmt"
@@ -12,60 +13,88 @@ import (
 	"text/tabwriter"
 
 	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile"
 )
 
 //go:embed logo.png
 var logo []byte
 
-func displaylogo() error {
-
+func displaylogo(w io.Writer) {
 	// width, height := widthAndHeight()
 	width, height := "", ""
 
-	fmt.Print("\033]1337;")
-	fmt.Printf("File=inline=1")
+	fmt.Fprint(w, "\033]1337;")
+	fmt.Fprintf(w, "File=inline=1")
 	if width != "" || height != "" {
 		if width != "" {
-			fmt.Printf(";width=%s", width)
+			fmt.Fprintf(w, ";width=%s", width)
 		}
 		if height != "" {
-			fmt.Printf(";height=%s", height)
+			fmt.Fprintf(w, ";height=%s", height)
 		}
 	}
-	// fmt.Print("preserveAspectRatio=1")
-	fmt.Print(":")
-	fmt.Printf("%s", base64.StdEncoding.EncodeToString(logo))
-	fmt.Print("\a\n")
-
-	return nil
+	// fmt.Fprint(w, "preserveAspectRatio=1")
+	fmt.Fprint(w, ":")
+	fmt.Fprintf(w, "%s", base64.StdEncoding.EncodeToString(logo))
+	fmt.Fprint(w, "\a\n")
 }
 
 // ListTasks prints a list of tasks.
 // Tasks that match the given filters will be excluded from the list.
 // The function returns a boolean indicating whether or not tasks were found.
 func (e *Executor) ListTasks(filters ...FilterFunc) bool {
-	tasks := e.GetTaskList(filters...)
-	if len(tasks) == 0 {
-		return false
+	// Format in tab-separated columns with a tab stop of 8, unless the output
+	// should be plain, in which case tasks are simply printed one per line
+	var w io.Writer = e.Stdout
+	tw := tabwriter.NewWriter(e.Stdout, 0, 8, 6, ' ', 0)
+	sep := " "
+	if e.OutputStyle.Name != "plain" {
+		w, sep = tw, "\t"
 	}
+	found := false
+	header := func() {
+		if !found {
+			found = true
+			if !e.NoLogo {
+				displaylogo(e.Stdout)
+			}
+			if e.OutputStyle.Name != "plain" {
+				e.Logger.Outf(logger.Default, "")
+			}
+			e.Logger.Outf(logger.Default, "Available tasks:")
+		}
+	}
+	_ = e.RangeTaskList(func(task *taskfile.Task) error {
+		header()
 
-	displaylogo()
-	e.Logger.Outf(logger.Default, "")
-	e.Logger.Outf(logger.Default, "Available tasks:")
-
-	// Format in tab-separated columns with a tab stop of 8.
-	w := tabwriter.NewWriter(e.Stdout, 0, 8, 6, ' ', 0)
-	for _, task := range tasks {
 		e.Logger.FOutf(w, logger.Yellow, "* ")
+		if icon := task.Icon.String(e.ASCII); icon != "" {
+			e.Logger.FOutf(w, logger.Default, "%s ", icon)
+		}
 		e.Logger.FOutf(w, logger.Green, task.Task)
-		e.Logger.FOutf(w, logger.Default, ": \t%s", task.Desc)
+		e.Logger.FOutf(w, logger.Default, ":%s%s", sep, task.Desc)
 		if len(task.Aliases) > 0 {
-			e.Logger.FOutf(w, logger.Cyan, "\t(aliases: %s)", strings.Join(task.Aliases, ", "))
+			e.Logger.FOutf(w, logger.Cyan, "%s(aliases: %s)", sep, strings.Join(task.Aliases, ", "))
+		}
+		// With Lenient, the tasks that fail to compile are marked, since
+		// they'd fail to run
+		if e.Lenient {
+			if _, err := e.FastCompiledTask(taskfile.Call{Task: task.Task}); err != nil {
+				e.Logger.FOutf(w, logger.Red, "%s(invalid: %s)", sep, e.redact(invalidReason(err.Error())))
+			}
 		}
 		fmt.Fprint(w, "\n")
+		return nil
+	}, filters...)
+	for _, broken := range e.Taskfile.BrokenIncludes {
+		header()
+		e.Logger.FOutf(w, logger.Yellow, "* ")
+		e.Logger.FOutf(w, logger.Red, "%s%s*", broken.Namespace, taskfile.NamespaceSeparator)
+		e.Logger.FOutf(w, logger.Red, ":%s(invalid: %s)", sep, e.redact(invalidReason(broken.Err)))
+		fmt.Fprint(w, "\n")
 	}
-	w.Flush()
-	return true
+	tw.Flush()
+	return found
 }
 
 // ListTaskNames prints only the task names in a Taskfile.
@@ -80,13 +109,17 @@ func (e *Executor) ListTaskNames(allTasks bool) {
 		}
 	}
 	// use stdout if no output defined
-	var w io.Writer = os.Stdout
+	var out io.Writer = os.Stdout
 	if e.Stdout != nil {
-		w = e.Stdout
+		out = e.Stdout
 	}
+	// buffer the output, since there may be thousands of names
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+
 	// create a string slice from all map values (*taskfile.Task)
 	s := make([]string, 0, len(e.Taskfile.Tasks))
-	for _, t := range e.Taskfile.Tasks {
+	for _, t := range e.sortedTasks() {
 		if (allTasks || t.Desc != "") && !t.Internal {
 			s = append(s, strings.TrimRight(t.Task, ":"))
 			for _, alias := range t.Aliases {
@@ -94,9 +127,13 @@ func (e *Executor) ListTaskNames(allTasks bool) {
 			}
 		}
 	}
-	// sort and print all task names
-	sort.Strings(s)
+	// sort and print all task names, unless they should keep the order of
+	// the tasks
+	if mode := e.taskSort(); mode == "default" || mode == "alphanumeric" {
+		sort.Strings(s)
+	}
 	for _, t := range s {
-		fmt.Fprintln(w, t)
+		w.WriteString(t)
+		w.WriteByte('\n')
 	}
 }
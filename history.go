@@ -0,0 +1,190 @@
+package task
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// historyEntry is a run of a task, as a line of the history of the project
+type historyEntry struct {
+	Task       string    `json:"task"`
+	Start      time.Time `json:"start"`
+	DurationMs int64     `json:"duration_ms"`
+	Failed     bool      `json:"failed,omitempty"`
+}
+
+// historyMutex keeps concurrent tasks from interleaving their history lines
+var historyMutex sync.Mutex
+
+// historyFile returns the file the runs of the tasks of the project are
+// recorded to, or an empty string if the history was disabled with
+// TASK_HISTORY=false
+func (e *Executor) historyFile() string {
+	if enabled, err := strconv.ParseBool(os.Getenv("TASK_HISTORY")); err == nil && !enabled {
+		return ""
+	}
+
+	dir := os.Getenv("TASK_HISTORY_DIR")
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(cacheDir, "task", "history")
+	}
+	sum := sha256.Sum256([]byte(filepath.Clean(e.Dir)))
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.jsonl", filepath.Base(e.Dir), hex.EncodeToString(sum[:4])))
+}
+
+// recordHistory adds the run of a task to the history of the project. Up to
+// date tasks aren't recorded, since they took no time to run. Failing to
+// record it is only a warning.
+func (e *Executor) recordHistory(t *taskfile.Task, status reportStatus, start time.Time, err error) {
+	if e.Dry || status != reportRan {
+		return
+	}
+	path := e.historyFile()
+	if path == "" {
+		return
+	}
+
+	line, jsonErr := json.Marshal(historyEntry{
+		Task:       t.Task,
+		Start:      start.UTC().Truncate(time.Millisecond),
+		DurationMs: time.Since(start).Milliseconds(),
+		Failed:     err != nil,
+	})
+	if jsonErr != nil {
+		return
+	}
+
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	if err := appendHistory(path, append(line, '\n')); err != nil {
+		e.Logger.VerboseErrf(logger.Yellow, "task: Could not record the run of %q to the history: %v", t.Task, err)
+	}
+}
+
+func appendHistory(path string, line []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// taskStats are the runs of a task added up
+type taskStats struct {
+	task   string
+	runs   int
+	failed int
+	total  time.Duration
+}
+
+// Stats prints the tasks that ran in the given last days, starting with the
+// ones that took the longest in total, according to the history of the
+// project
+func (e *Executor) Stats(days int) error {
+	if days <= 0 {
+		return fmt.Errorf("task: The days of --stats must be positive, got %d", days)
+	}
+	path := e.historyFile()
+	if path == "" {
+		return fmt.Errorf("task: The history is disabled by TASK_HISTORY")
+	}
+
+	stats, err := readStats(path, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return err
+	}
+	if len(stats) == 0 {
+		e.Logger.Outf(logger.Yellow, "task: No tasks ran in the last %d days", days)
+		return nil
+	}
+
+	e.Logger.Outf(logger.Default, "Tasks that ran in the last %d days:", days)
+	w := tabwriter.NewWriter(e.Stdout, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "TASK\tRUNS\tFAILED\tTOTAL\tAVERAGE")
+	for _, s := range stats {
+		average := s.total / time.Duration(s.runs)
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n", e.redact(s.task), s.runs, s.failed, roundDuration(s.total), roundDuration(average))
+	}
+	return w.Flush()
+}
+
+// readStats adds up the runs of each task of the history since the given
+// time, sorted by their total duration
+func readStats(path string, since time.Time) ([]*taskStats, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	byTask := make(map[string]*taskStats)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry historyEntry
+		// Lines cut by a crash are skipped
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Start.Before(since) {
+			continue
+		}
+		s, ok := byTask[entry.Task]
+		if !ok {
+			s = &taskStats{task: entry.Task}
+			byTask[entry.Task] = s
+		}
+		s.runs++
+		if entry.Failed {
+			s.failed++
+		}
+		s.total += time.Duration(entry.DurationMs) * time.Millisecond
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	stats := make([]*taskStats, 0, len(byTask))
+	for _, s := range byTask {
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].total != stats[j].total {
+			return stats[i].total > stats[j].total
+		}
+		return stats[i].task < stats[j].task
+	})
+	return stats, nil
+}
+
+// roundDuration rounds a duration to be shown in a table
+func roundDuration(d time.Duration) time.Duration {
+	if d >= time.Minute {
+		return d.Round(time.Second)
+	}
+	return d.Round(10 * time.Millisecond)
+}
@@ -0,0 +1,12 @@
+package task
+
+import "fmt"
+
+// checkNotInspecting fails when Inspect is set, for the actions that would
+// run commands of the Taskfile
+func (e *Executor) checkNotInspecting(action string) error {
+	if e.Inspect {
+		return fmt.Errorf("task: Can't %s in inspect mode, which never runs commands of the Taskfile", action)
+	}
+	return nil
+}
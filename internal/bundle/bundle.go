@@ -0,0 +1,424 @@
+// Package bundle packages a Taskfile, along with the Taskfiles it includes
+// and the files its commands reference, into an archive other Taskfiles can
+// include.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/remote"
+	"github.com/go-task/task/v3/internal/status"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// ManifestName is the name of the manifest at the root of an archive
+const ManifestName = "manifest.json"
+
+var defaultTaskfiles = []string{
+	"Taskfile.yml",
+	"Taskfile.yaml",
+	"Taskfile.dist.yml",
+	"Taskfile.dist.yaml",
+}
+
+// Manifest describes the contents of an archive
+type Manifest struct {
+	Name     string `json:"name"`
+	Version  string `json:"version,omitempty"`
+	Taskfile string `json:"taskfile"`
+	Files    []File `json:"files"`
+}
+
+// File is a file of an archive, with its path relative to the root of the
+// archive
+type File struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// IsArchive returns whether the file at path is an archive, judging by its
+// extension
+func IsArchive(path string) bool {
+	return strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz")
+}
+
+// Create writes an archive of the Taskfile at entrypoint, relative to dir, to
+// dest. The default Taskfile of dir is used when entrypoint is empty.
+// Only files within dir are bundled.
+func Create(dir, entrypoint, dest string) (*Manifest, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if entrypoint == "" {
+		if entrypoint, err = findTaskfile(root); err != nil {
+			return nil, err
+		}
+	}
+	entrypoint = filepathext.SmartJoin(root, entrypoint)
+
+	c := &collector{root: root, files: make(map[string]bool)}
+	tf, err := c.addTaskfile(entrypoint)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{Name: filepath.Base(root)}
+	if m.Taskfile, err = c.rel(entrypoint); err != nil {
+		return nil, err
+	}
+	if tf.Package != nil {
+		if tf.Package.Name != "" {
+			m.Name = tf.Package.Name
+		}
+		m.Version = tf.Package.Version
+		for _, g := range tf.Package.Files {
+			files, err := status.Glob(root, g)
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range files {
+				if err := c.add(f); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// An archive written into the directory must not bundle itself
+	if abs, err := filepath.Abs(dest); err == nil {
+		if rel, err := c.rel(abs); err == nil {
+			delete(c.files, rel)
+		}
+	}
+
+	paths := make([]string, 0, len(c.files))
+	for p := range c.files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		sum, err := sumFile(filepath.Join(root, filepath.FromSlash(p)))
+		if err != nil {
+			return nil, err
+		}
+		m.Files = append(m.Files, File{Path: p, SHA256: sum})
+	}
+
+	return m, write(dest, root, m)
+}
+
+// Open extracts the archive at path into a directory of cacheDir, unless it
+// was already, and returns the directory and the manifest of the archive.
+// Each content is extracted once, so a changed archive is extracted again.
+func Open(path, cacheDir string) (string, *Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	sum := sha256.Sum256(data)
+	dir := filepath.Join(cacheDir, hex.EncodeToString(sum[:]))
+	if m, err := readManifest(dir); err == nil {
+		return dir, m, nil
+	}
+
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return "", nil, err
+	}
+	tmp, err := os.MkdirTemp(cacheDir, ".extract-")
+	if err != nil {
+		return "", nil, err
+	}
+	m, err := extract(bytes.NewReader(data), tmp)
+	if err != nil {
+		os.RemoveAll(tmp)
+		return "", nil, fmt.Errorf("bundle: invalid archive %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		os.RemoveAll(tmp)
+		// It may have been extracted by another process in the meantime
+		if m, err := readManifest(dir); err == nil {
+			return dir, m, nil
+		}
+		return "", nil, err
+	}
+	return dir, m, nil
+}
+
+// collector collects the files of an archive, as paths relative to its root
+type collector struct {
+	root  string
+	files map[string]bool
+}
+
+func (c *collector) rel(path string) (string, error) {
+	rel, err := filepath.Rel(c.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("bundle: %s is outside of %s", path, c.root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func (c *collector) add(path string) error {
+	rel, err := c.rel(path)
+	if err != nil {
+		return err
+	}
+	c.files[rel] = true
+	return nil
+}
+
+// addTaskfile adds the Taskfile at path, the Taskfiles it includes and the
+// files its commands reference
+func (c *collector) addTaskfile(path string) (*taskfile.Taskfile, error) {
+	rel, err := c.rel(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tf taskfile.Taskfile
+	if err := yaml.Unmarshal(data, &tf); err != nil {
+		return nil, fmt.Errorf("bundle: %s: %w", path, err)
+	}
+	if c.files[rel] {
+		return &tf, nil
+	}
+	c.files[rel] = true
+
+	dir := filepath.Dir(path)
+	err = tf.Includes.Range(func(namespace string, include taskfile.IncludedTaskfile) error {
+		// Templated paths are only known when the Taskfile is read, and
+		// remote Taskfiles are fetched by the including one
+		if strings.Contains(include.Taskfile, "{{") || remote.IsRemote(include.Taskfile) {
+			return nil
+		}
+		path := filepathext.SmartJoin(dir, include.Taskfile)
+		info, err := os.Stat(path)
+		if err != nil {
+			if include.Optional {
+				return nil
+			}
+			return err
+		}
+		switch {
+		case info.IsDir():
+			name, err := findTaskfile(path)
+			if err != nil {
+				return err
+			}
+			path = filepath.Join(path, name)
+		case IsArchive(path):
+			return c.add(path)
+		}
+		_, err = c.addTaskfile(path)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tf.Tasks {
+		if t != nil && !strings.Contains(t.Dir, "{{") {
+			c.addReferenced(filepathext.SmartJoin(dir, t.Dir), t.Cmds)
+		}
+	}
+	return &tf, nil
+}
+
+// addReferenced adds the files named by the given commands, like the scripts
+// they run, as long as they're within the root
+func (c *collector) addReferenced(dir string, cmds []*taskfile.Cmd) {
+	for _, cmd := range cmds {
+		if cmd == nil {
+			continue
+		}
+		for _, field := range strings.Fields(cmd.Cmd) {
+			field = strings.Trim(field, `"'`)
+			if field == "" || strings.Contains(field, "{{") || filepath.IsAbs(field) {
+				continue
+			}
+			path := filepath.Join(dir, field)
+			if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+				_ = c.add(path)
+			}
+		}
+	}
+}
+
+func findTaskfile(dir string) (string, error) {
+	for _, name := range defaultTaskfiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf(`bundle: no Taskfile found in "%s"`, dir)
+}
+
+func sumFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func write(dest, root string, m *Manifest) (err error) {
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	if err := tw.WriteHeader(&tar.Header{Name: ManifestName, Mode: 0o644, Size: int64(len(manifest))}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifest); err != nil {
+		return err
+	}
+	for _, file := range m.Files {
+		if err := writeFile(tw, root, file.Path); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func writeFile(tw *tar.Writer, root, name string) error {
+	f, err := os.Open(filepath.Join(root, filepath.FromSlash(name)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{Name: name, Mode: int64(info.Mode().Perm()), Size: info.Size()}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// extract extracts an archive into dir, checking its files against the
+// manifest
+func extract(r io.Reader, dir string) (*Manifest, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gr)
+
+	sums := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("%s is not a regular file", header.Name)
+		}
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("%s is outside of the archive", header.Name)
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return nil, err
+		}
+		out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fs.FileMode(header.Mode).Perm())
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		_, err = io.Copy(io.MultiWriter(out, h), tr)
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+		sums[name] = hex.EncodeToString(h.Sum(nil))
+	}
+
+	m, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	delete(sums, ManifestName)
+	for _, file := range m.Files {
+		sum, ok := sums[file.Path]
+		if !ok {
+			return nil, fmt.Errorf("%s is missing", file.Path)
+		}
+		if sum != file.SHA256 {
+			return nil, fmt.Errorf("checksum of %s doesn't match the manifest", file.Path)
+		}
+		delete(sums, file.Path)
+	}
+	for name := range sums {
+		return nil, fmt.Errorf("%s is not listed in the manifest", name)
+	}
+	if m.Taskfile == "" {
+		return nil, errors.New("the manifest has no Taskfile")
+	}
+	return m, nil
+}
+
+func readManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
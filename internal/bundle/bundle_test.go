@@ -0,0 +1,139 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const libTaskfile = `version: '3'
+
+package:
+  name: images
+  version: 1.2.0
+  files: [assets/*.txt]
+
+includes:
+  sub: ./sub
+
+tasks:
+  build:
+    cmds:
+      - ./scripts/build.sh "{{.TAG}}"
+      - echo done
+`
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o755))
+	}
+}
+
+func TestCreateAndOpen(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"Taskfile.yml":     libTaskfile,
+		"sub/Taskfile.yml": "version: '3'\n\ntasks:\n  lint: sh lint.sh\n",
+		"sub/lint.sh":      "echo lint",
+		"scripts/build.sh": "echo build",
+		"assets/a.txt":     "a",
+		"unrelated.txt":    "not bundled",
+	})
+
+	dest := filepath.Join(dir, "images.tar.gz")
+	m, err := Create(dir, "", dest)
+	assert.NoError(t, err)
+	assert.Equal(t, "images", m.Name)
+	assert.Equal(t, "1.2.0", m.Version)
+	assert.Equal(t, "Taskfile.yml", m.Taskfile)
+
+	var paths []string
+	for _, f := range m.Files {
+		paths = append(paths, f.Path)
+	}
+	assert.Equal(t, []string{"Taskfile.yml", "assets/a.txt", "scripts/build.sh", "sub/Taskfile.yml", "sub/lint.sh"}, paths)
+
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	extracted, opened, err := Open(dest, cacheDir)
+	assert.NoError(t, err)
+	assert.Equal(t, m, opened)
+	data, err := os.ReadFile(filepath.Join(extracted, "scripts", "build.sh"))
+	assert.NoError(t, err)
+	assert.Equal(t, "echo build", string(data))
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(extracted, "scripts", "build.sh"))
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+	}
+	assert.NoFileExists(t, filepath.Join(extracted, "unrelated.txt"))
+
+	// Opening it again reuses the extracted files
+	again, _, err := Open(dest, cacheDir)
+	assert.NoError(t, err)
+	assert.Equal(t, extracted, again)
+}
+
+func TestOpenInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected string
+	}{
+		{
+			name: "tampered",
+			files: map[string]string{
+				ManifestName:   `{"name":"lib","taskfile":"Taskfile.yml","files":[{"path":"Taskfile.yml","sha256":"00"}]}`,
+				"Taskfile.yml": "version: '3'\n",
+			},
+			expected: "checksum of Taskfile.yml doesn't match the manifest",
+		},
+		{
+			name: "unlisted",
+			files: map[string]string{
+				ManifestName: `{"name":"lib","taskfile":"Taskfile.yml","files":[]}`,
+				"extra.sh":   "echo",
+			},
+			expected: "extra.sh is not listed in the manifest",
+		},
+		{
+			name: "outside",
+			files: map[string]string{
+				"../evil.sh": "echo",
+			},
+			expected: "../evil.sh is outside of the archive",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dest := filepath.Join(t.TempDir(), "lib.tar.gz")
+			f, err := os.Create(dest)
+			assert.NoError(t, err)
+			gw := gzip.NewWriter(f)
+			tw := tar.NewWriter(gw)
+			for name, content := range test.files {
+				assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}))
+				_, err := tw.Write([]byte(content))
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, tw.Close())
+			assert.NoError(t, gw.Close())
+			assert.NoError(t, f.Close())
+
+			cacheDir := t.TempDir()
+			_, _, err = Open(dest, cacheDir)
+			assert.ErrorContains(t, err, test.expected)
+			entries, err := os.ReadDir(cacheDir)
+			assert.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
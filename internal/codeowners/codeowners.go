@@ -0,0 +1,149 @@
+// Package codeowners reads CODEOWNERS files, which assign owners to the files
+// of a repository.
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Locations are where CODEOWNERS files are looked for, relative to the root
+// of the repository, in order
+var Locations = []string{
+	"CODEOWNERS",
+	filepath.Join(".github", "CODEOWNERS"),
+	filepath.Join("docs", "CODEOWNERS"),
+}
+
+// File is a parsed CODEOWNERS file
+type File struct {
+	Rules []*Rule
+}
+
+// Rule assigns owners to the files matching a pattern. A rule without owners
+// leaves the files it matches without an owner.
+type Rule struct {
+	Pattern string
+	Owners  []string
+
+	re *regexp.Regexp
+}
+
+// Find parses the first CODEOWNERS file found in the given root directory of
+// a repository. It returns nil when there's none.
+func Find(root string) (*File, error) {
+	for _, location := range Locations {
+		f, err := os.Open(filepath.Join(root, location))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		file, err := Parse(f)
+		if err != nil {
+			return nil, fmt.Errorf("codeowners: %s: %w", location, err)
+		}
+		return file, nil
+	}
+	return nil, nil
+}
+
+// Parse parses a CODEOWNERS file
+func Parse(r io.Reader) (*File, error) {
+	var file File
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		rule := &Rule{Pattern: fields[0]}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			rule.Owners = append(rule.Owners, owner)
+		}
+		re, err := compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid pattern %q: %w", line, rule.Pattern, err)
+		}
+		rule.re = re
+		file.Rules = append(file.Rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// Owners returns the owners of the file at the given path, relative to the
+// root of the repository. Like on GitHub, the last matching rule wins.
+func (f *File) Owners(path string) []string {
+	if f == nil {
+		return nil
+	}
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if f.Rules[i].re.MatchString(path) {
+			return f.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// compile turns a pattern, which follows the rules of .gitignore files, into
+// a regular expression matching the paths of the files it applies to
+func compile(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	// Patterns with a slash other than a trailing one are relative to the
+	// root, the others match at any depth
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		// Only the files inside of the directory
+		b.WriteString("/.*$")
+	case strings.HasSuffix(pattern, "/*"):
+		// The files directly inside of the directory, but not the ones of
+		// its subdirectories
+		b.WriteString("$")
+	default:
+		// The file itself, or the files inside of it if it's a directory
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,60 @@
+package codeowners
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const example = `# Default owners
+*       @org/everyone
+
+*.yml   @org/config   # YAML files
+/build/ @org/build
+docs/*  @org/docs
+**/deploy/** @org/platform ops@example.com
+/vendor/
+`
+
+func TestOwners(t *testing.T) {
+	f, err := Parse(strings.NewReader(example))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"main.go", []string{"@org/everyone"}},
+		{"Taskfile.yml", []string{"@org/config"}},
+		{"sub/Taskfile.yml", []string{"@org/config"}},
+		{"build/Taskfile.yml", []string{"@org/build"}},
+		{"build/scripts/run.sh", []string{"@org/build"}},
+		{"tools/build/run.sh", []string{"@org/everyone"}},
+		{"docs/index.md", []string{"@org/docs"}},
+		{"docs/guides/index.md", []string{"@org/everyone"}},
+		{"apps/deploy/Taskfile.yml", []string{"@org/platform", "ops@example.com"}},
+		{"deploy/run.sh", []string{"@org/platform", "ops@example.com"}},
+		{"vendor/lib/Taskfile.yml", nil},
+		{"/main.go", []string{"@org/everyone"}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, f.Owners(test.path), test.path)
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	f, err := Find(dir)
+	assert.NoError(t, err)
+	assert.Nil(t, f)
+	assert.Nil(t, f.Owners("Taskfile.yml"))
+
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, ".github"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".github", "CODEOWNERS"), []byte("* @org/team\n"), 0o644))
+	f, err = Find(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"@org/team"}, f.Owners("Taskfile.yml"))
+}
@@ -26,6 +26,13 @@ type CompilerV2 struct {
 
 	Logger *logger.Logger
 
+	// Inspect leaves dynamic variables empty instead of running their
+	// commands, for Taskfiles that aren't trusted
+	Inspect bool
+	// Policy approves the commands of dynamic variables before they run,
+	// when set
+	Policy func(cmd, dir string) error
+
 	dynamicCache   map[string]string
 	muDynamicCache sync.Mutex
 }
@@ -72,6 +79,10 @@ func (vr *varResolver) merge(vars *taskfile.Vars) {
 	}
 	tr := templater.Templater{Vars: vr.vars}
 	_ = vars.Range(func(k string, v taskfile.Var) error {
+		if v.Live != nil {
+			vr.vars.Set(k, v)
+			return nil
+		}
 		v = taskfile.Var{
 			Static: tr.Replace(v.Static),
 			Sh:     tr.Replace(v.Sh),
@@ -88,7 +99,7 @@ func (vr *varResolver) merge(vars *taskfile.Vars) {
 }
 
 func (c *CompilerV2) HandleDynamicVar(v taskfile.Var, _ string) (string, error) {
-	if v.Static != "" || v.Sh == "" {
+	if v.Static != "" || v.Sh == "" || c.Inspect {
 		return v.Static, nil
 	}
 
@@ -102,6 +113,12 @@ func (c *CompilerV2) HandleDynamicVar(v taskfile.Var, _ string) (string, error)
 		return result, nil
 	}
 
+	if c.Policy != nil {
+		if err := c.Policy(v.Sh, c.Dir); err != nil {
+			return "", err
+		}
+	}
+
 	var stdout bytes.Buffer
 	opts := &execext.RunCommandOptions{
 		Command: v.Sh,
@@ -3,6 +3,7 @@ package v3
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -11,8 +12,11 @@ import (
 	"github.com/go-task/task/v3/internal/execext"
 	"github.com/go-task/task/v3/internal/filepathext"
 	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/prompt"
 	"github.com/go-task/task/v3/internal/templater"
 	"github.com/go-task/task/v3/taskfile"
+
+	"golang.org/x/exp/slices"
 )
 
 var _ compiler.Compiler = &CompilerV3{}
@@ -23,10 +27,21 @@ type CompilerV3 struct {
 	TaskfileEnv  *taskfile.Vars
 	TaskfileVars *taskfile.Vars
 
-	Logger *logger.Logger
+	Logger   *logger.Logger
+	Prompter *prompt.Prompter
+
+	// Inspect leaves dynamic variables empty instead of running their
+	// commands, for Taskfiles that aren't trusted
+	Inspect bool
+	// Policy approves the commands of dynamic variables before they run,
+	// when set
+	Policy func(cmd, dir string) error
 
 	dynamicCache   map[string]string
 	muDynamicCache sync.Mutex
+
+	promptCache   map[string]string
+	muPromptCache sync.Mutex
 }
 
 func (c *CompilerV3) GetTaskfileVariables() (*taskfile.Vars, error) {
@@ -43,6 +58,18 @@ func (c *CompilerV3) FastGetVariables(t *taskfile.Task, call taskfile.Call) (*ta
 
 func (c *CompilerV3) getVariables(t *taskfile.Task, call *taskfile.Call, evaluateShVars bool) (*taskfile.Vars, error) {
 	result := compiler.GetEnviron()
+
+	// sources keeps where each variable came from, to trace precedence
+	// decisions when debugging
+	var sources map[string]string
+	debug := t != nil && evaluateShVars && c.Logger.IsDebug()
+	if debug {
+		sources = make(map[string]string, len(result.Keys))
+		for _, k := range result.Keys {
+			sources[k] = "environment"
+		}
+	}
+
 	if t != nil {
 		specialVars, err := c.getSpecialVars(t)
 		if err != nil {
@@ -50,15 +77,74 @@ func (c *CompilerV3) getVariables(t *taskfile.Task, call *taskfile.Call, evaluat
 		}
 		for k, v := range specialVars {
 			result.Set(k, taskfile.Var{Static: v})
+			if debug {
+				sources[k] = "special variables"
+			}
 		}
 	}
 
-	getRangeFunc := func(dir string) func(k string, v taskfile.Var) error {
+	promptVars := &taskfile.Vars{}
+
+	// Dynamic variables the task can't reference are not evaluated, since
+	// running their commands could make every run slow
+	var needed map[string]bool
+	if t != nil && call != nil && evaluateShVars {
+		needed = c.neededVars(t, call)
+	}
+
+	getRangeFunc := func(source, dir string) func(k string, v taskfile.Var) error {
+		set := func(k string, v taskfile.Var) {
+			if debug {
+				if previous, ok := sources[k]; ok {
+					c.Logger.Debugf("[%s] variable %s set by %s, overriding %s", t.Task, k, source, previous)
+				} else {
+					c.Logger.Debugf("[%s] variable %s set by %s", t.Task, k, source)
+				}
+				sources[k] = source
+			}
+			result.Set(k, v)
+		}
+
 		return func(k string, v taskfile.Var) error {
 			tr := templater.Templater{Vars: result, RemoveNoValue: true}
 
+			// Live variables (e.g. lists) are not templated
+			if v.Live != nil {
+				set(k, v)
+				return nil
+			}
+
+			// Variables without a value are only asked to the user if
+			// not set elsewhere, like in the environment, the CLI or the
+			// call. They're validated once all variables are resolved.
+			if v.NeedsPrompt() {
+				promptVars.Set(k, v)
+				if _, ok := result.Mapping[k]; ok {
+					return nil
+				}
+				if call != nil && call.Vars != nil {
+					if _, ok := call.Vars.Mapping[k]; ok {
+						return nil
+					}
+				}
+				if !evaluateShVars || t == nil {
+					return nil
+				}
+				value, err := c.promptVar(k, v)
+				if err != nil {
+					return err
+				}
+				set(k, taskfile.Var{Static: value, Secret: v.Secret})
+				return nil
+			}
+
 			if !evaluateShVars {
-				result.Set(k, taskfile.Var{Static: tr.Replace(v.Static)})
+				set(k, taskfile.Var{Static: tr.Replace(v.Static), Secret: v.Secret})
+				return nil
+			}
+
+			if needed != nil && v.Static == "" && v.Sh != "" && !needed[k] {
+				c.Logger.Debugf("[%s] variable %s from %s not evaluated, since the task doesn't use it", t.Task, k, source)
 				return nil
 			}
 
@@ -66,6 +152,7 @@ func (c *CompilerV3) getVariables(t *taskfile.Task, call *taskfile.Call, evaluat
 				Static: tr.Replace(v.Static),
 				Sh:     tr.Replace(v.Sh),
 				Dir:    v.Dir,
+				Secret: v.Secret,
 			}
 			if err := tr.Err(); err != nil {
 				return err
@@ -74,56 +161,74 @@ func (c *CompilerV3) getVariables(t *taskfile.Task, call *taskfile.Call, evaluat
 			if err != nil {
 				return err
 			}
-			result.Set(k, taskfile.Var{Static: static})
+			set(k, taskfile.Var{Static: static, Secret: v.Secret})
 			return nil
 		}
 	}
-	rangeFunc := getRangeFunc(c.Dir)
 
-	var taskRangeFunc func(k string, v taskfile.Var) error
+	var taskDir string
 	if t != nil {
 		// NOTE(@andreynering): We're manually joining these paths here because
 		// this is the raw task, not the compiled one.
 		tr := templater.Templater{Vars: result, RemoveNoValue: true}
-		dir := tr.Replace(t.Dir)
+		taskDir = tr.Replace(t.Dir)
 		if err := tr.Err(); err != nil {
 			return nil, err
 		}
-		dir = filepathext.SmartJoin(c.Dir, dir)
-		taskRangeFunc = getRangeFunc(dir)
+		taskDir = filepathext.SmartJoin(c.Dir, taskDir)
 	}
 
-	if err := c.TaskfileEnv.Range(rangeFunc); err != nil {
+	if err := c.TaskfileEnv.Range(getRangeFunc("Taskfile env", c.Dir)); err != nil {
 		return nil, err
 	}
-	if err := c.TaskfileVars.Range(rangeFunc); err != nil {
+	if err := c.TaskfileVars.Range(getRangeFunc("Taskfile vars", c.Dir)); err != nil {
 		return nil, err
 	}
 	if t != nil {
-		if err := t.IncludedTaskfileVars.Range(taskRangeFunc); err != nil {
+		if err := t.IncludedTaskfileVars.Range(getRangeFunc("included Taskfile vars", taskDir)); err != nil {
 			return nil, err
 		}
-		if err := t.IncludeVars.Range(rangeFunc); err != nil {
+		if err := t.IncludeVars.Range(getRangeFunc("include vars", c.Dir)); err != nil {
 			return nil, err
 		}
 	}
 
 	if t == nil || call == nil {
-		return result, nil
+		return result, checkPromptVars(result, promptVars)
 	}
 
-	if err := call.Vars.Range(rangeFunc); err != nil {
+	if err := call.Vars.Range(getRangeFunc("call vars", c.Dir)); err != nil {
 		return nil, err
 	}
-	if err := t.Vars.Range(taskRangeFunc); err != nil {
+	if err := t.Vars.Range(getRangeFunc("task vars", taskDir)); err != nil {
 		return nil, err
 	}
 
-	return result, nil
+	return result, checkPromptVars(result, promptVars)
+}
+
+// checkPromptVars checks the values of the variables that would be asked to
+// the user were set elsewhere to allowed values, and marks them as secret
+// when needed.
+func checkPromptVars(result, promptVars *taskfile.Vars) error {
+	return promptVars.Range(func(k string, v taskfile.Var) error {
+		existing, ok := result.Mapping[k]
+		if !ok {
+			return nil
+		}
+		if len(v.Enum) > 0 && !slices.Contains(v.Enum, existing.Static) {
+			return fmt.Errorf(`task: Invalid value %q for variable %q. Allowed values: %s`, existing.Static, k, strings.Join(v.Enum, ", "))
+		}
+		if v.Secret && !existing.Secret {
+			existing.Secret = true
+			result.Set(k, existing)
+		}
+		return nil
+	})
 }
 
 func (c *CompilerV3) HandleDynamicVar(v taskfile.Var, dir string) (string, error) {
-	if v.Static != "" || v.Sh == "" {
+	if v.Static != "" || v.Sh == "" || c.Inspect {
 		return v.Static, nil
 	}
 
@@ -142,6 +247,12 @@ func (c *CompilerV3) HandleDynamicVar(v taskfile.Var, dir string) (string, error
 		dir = v.Dir
 	}
 
+	if c.Policy != nil {
+		if err := c.Policy(v.Sh, dir); err != nil {
+			return "", err
+		}
+	}
+
 	var stdout bytes.Buffer
 	opts := &execext.RunCommandOptions{
 		Command: v.Sh,
@@ -159,8 +270,65 @@ func (c *CompilerV3) HandleDynamicVar(v taskfile.Var, dir string) (string, error
 	result = strings.TrimSuffix(result, "\n")
 
 	c.dynamicCache[v.Sh] = result
-	c.Logger.VerboseErrf(logger.Magenta, `task: dynamic variable: '%s' result: '%s'`, v.Sh, result)
+	if v.Secret {
+		c.Logger.VerboseErrf(logger.Magenta, `task: dynamic variable: '%s' result: %s`, v.Sh, taskfile.RedactedValue)
+	} else {
+		c.Logger.VerboseErrf(logger.Magenta, `task: dynamic variable: '%s' result: '%s'`, v.Sh, result)
+	}
+
+	return result, nil
+}
+
+// promptVar asks the user for the value of a variable. Secret variables are
+// asked without echoing what's typed. Answers are cached, so each variable is
+// asked at most once.
+func (c *CompilerV3) promptVar(name string, v taskfile.Var) (string, error) {
+	c.muPromptCache.Lock()
+	defer c.muPromptCache.Unlock()
+
+	if c.promptCache == nil {
+		c.promptCache = make(map[string]string, 1)
+	}
+	if result, ok := c.promptCache[name]; ok {
+		return result, nil
+	}
+
+	if c.Prompter == nil {
+		if len(v.Enum) > 0 {
+			return "", fmt.Errorf(`task: Variable %q is required. Allowed values: %s`, name, strings.Join(v.Enum, ", "))
+		}
+		return "", fmt.Errorf(`task: Variable %q is required`, name)
+	}
+
+	msg := v.Prompt
+	if msg == "" {
+		msg = name
+	}
+	msg = strings.TrimRight(msg, ": ") + ": "
+
+	var (
+		result string
+		err    error
+	)
+	switch {
+	case len(v.Enum) > 0:
+		result, err = c.Prompter.Select(msg, v.Enum)
+	case v.Secret:
+		result, err = c.Prompter.Password(msg)
+	default:
+		result, err = c.Prompter.Text(msg)
+	}
+	if errors.Is(err, prompt.ErrNotTerminal) {
+		return "", fmt.Errorf(`task: Variable %q is required. Allowed values: %s`, name, strings.Join(v.Enum, ", "))
+	}
+	if errors.Is(err, prompt.ErrNoValue) {
+		return "", fmt.Errorf(`task: Variable %q is required`, name)
+	}
+	if err != nil {
+		return "", fmt.Errorf(`task: Failed to prompt for variable %q: %w`, name, err)
+	}
 
+	c.promptCache[name] = result
 	return result, nil
 }
 
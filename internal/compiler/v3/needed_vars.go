@@ -0,0 +1,151 @@
+package v3
+
+import (
+	"regexp"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+var (
+	identifierRegexp     = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+	templateActionRegexp = regexp.MustCompile(`(?s)\{\{.*?\}\}`)
+	// wholeDotRegexp matches usages of the whole variables map, like "{{.}}"
+	// or "{{index . $name}}", which can't be followed statically
+	wholeDotRegexp = regexp.MustCompile(`(^|[^\w\])])\.($|[^\w])`)
+)
+
+// neededVars returns the names of the variables that may be used when
+// compiling the given task, so dynamic variables nobody uses don't need to
+// be evaluated. It's conservative: any word in the task templates counts as
+// a usage. It returns nil if it can't be known, like when the whole map of
+// variables is used, in which case all variables should be evaluated.
+func (c *CompilerV3) neededVars(t *taskfile.Task, call *taskfile.Call) map[string]bool {
+	needed := make(map[string]bool)
+
+	// scan marks the variables referenced by s as needed, and returns false
+	// if s uses the whole map of variables
+	scan := func(s string) bool {
+		for _, action := range templateActionRegexp.FindAllString(s, -1) {
+			if wholeDotRegexp.MatchString(action[2 : len(action)-2]) {
+				return false
+			}
+		}
+		for _, name := range identifierRegexp.FindAllString(s, -1) {
+			needed[name] = true
+		}
+		return true
+	}
+	scanVars := func(vars *taskfile.Vars) bool {
+		ok := true
+		_ = vars.Range(func(_ string, v taskfile.Var) error {
+			ok = ok && scan(v.Static) && scan(v.Sh) && scan(v.Dir)
+			return nil
+		})
+		return ok
+	}
+
+	templates := []string{t.Label, t.Desc, t.Summary, t.Dir, t.Method, t.Prefix, t.Run, t.MsgSuccess, t.MsgFailure, t.Timeout}
+	templates = append(templates, t.Sources...)
+	templates = append(templates, t.Generates...)
+	templates = append(templates, t.Status...)
+	templates = append(templates, t.EnvFile...)
+	if t.Retry != nil {
+		templates = append(templates, t.Retry.Delay)
+	}
+	if t.Stdin != nil {
+		templates = append(templates, t.Stdin.File)
+	}
+	if t.Nix != nil {
+		templates = append(templates, t.Nix.Shell)
+	}
+	for _, output := range t.Outputs {
+		if output != nil {
+			templates = append(templates, output.Value, output.File)
+		}
+	}
+	for _, artifact := range t.Artifacts {
+		if artifact != nil {
+			templates = append(templates, artifact.Dir, artifact.S3, artifact.Cmd)
+		}
+	}
+	for _, cmd := range t.Cmds {
+		if cmd == nil {
+			continue
+		}
+		// The commands of snippets are not known here
+		if cmd.Use != "" {
+			return nil
+		}
+		templates = append(templates, cmd.Cmd, cmd.Task)
+		if cmd.Fetch != nil {
+			templates = append(templates, cmd.Fetch.URL, cmd.Fetch.Dest, cmd.Fetch.Checksum)
+		}
+		if cmd.FileOp != nil {
+			// Templates may use any variable
+			if cmd.FileOp.Op == taskfile.FileOpTemplate {
+				return nil
+			}
+			templates = append(templates, cmd.FileOp.Path, cmd.FileOp.Dest)
+		}
+		if v := cmd.Verify; v != nil {
+			templates = append(templates, v.File, v.Checksum, v.ChecksumsFile, v.Signature, v.Key)
+		}
+		if w := cmd.WaitFor; w != nil {
+			templates = append(templates, w.TCP, w.HTTP, w.File, w.Cmd, w.Timeout, w.Interval)
+		}
+		if !scanVars(cmd.Vars) {
+			return nil
+		}
+	}
+	for _, dep := range taskfile.AllDeps(t.Deps) {
+		templates = append(templates, dep.Task)
+		if !scanVars(dep.Vars) {
+			return nil
+		}
+	}
+	for _, p := range t.Preconditions {
+		if p == nil {
+			continue
+		}
+		templates = append(templates, p.Sh, p.Msg)
+	}
+	for _, s := range templates {
+		if !scan(s) {
+			return nil
+		}
+	}
+	// Environment variables are always exported to commands
+	if !scanVars(t.Env) || !scanVars(c.TaskfileEnv) {
+		return nil
+	}
+	_ = c.TaskfileEnv.Range(func(k string, _ taskfile.Var) error {
+		needed[k] = true
+		return nil
+	})
+
+	// Variables needed by needed variables are needed as well
+	layers := []*taskfile.Vars{c.TaskfileVars, t.IncludedTaskfileVars, t.IncludeVars, call.Vars, t.Vars}
+	for scanned := make(map[*taskfile.Vars]map[string]bool); ; {
+		before := len(needed)
+		for _, vars := range layers {
+			if scanned[vars] == nil {
+				scanned[vars] = make(map[string]bool)
+			}
+			ok := true
+			_ = vars.Range(func(k string, v taskfile.Var) error {
+				if !needed[k] || scanned[vars][k] {
+					return nil
+				}
+				scanned[vars][k] = true
+				ok = ok && scan(v.Static) && scan(v.Sh) && scan(v.Dir)
+				return nil
+			})
+			if !ok {
+				return nil
+			}
+		}
+		if len(needed) == before {
+			return needed
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// Package completion generates the shell completion scripts of Task, which
+// complete its flags and ask Task itself for the names of the tasks.
+package completion
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// Shells are the shells completion scripts can be generated for
+var Shells = []string{"bash", "zsh", "fish", "powershell"}
+
+// The kinds of arguments of flags
+const (
+	ArgNone  = ""
+	ArgValue = "value"
+	ArgFile  = "file"
+	ArgDir   = "dir"
+)
+
+// Flag is a flag of Task
+type Flag struct {
+	Name      string
+	Shorthand string
+	Usage     string
+	// Arg is the kind of argument the flag takes, if any
+	Arg string
+	// Values are the values the argument can have, when known
+	Values []string
+}
+
+// Script writes the completion script of the given shell, completing the
+// given flags
+func Script(w io.Writer, shell string, flags []Flag) error {
+	tmpl, ok := templates[shell]
+	if !ok {
+		return fmt.Errorf(`task: Unsupported shell %q for completion. Available options: "%s"`, shell, strings.Join(Shells, `", "`))
+	}
+	return tmpl.Execute(w, flags)
+}
+
+var templates = map[string]*template.Template{
+	"bash":       newTemplate("bash", bashTemplate),
+	"zsh":        newTemplate("zsh", zshTemplate),
+	"fish":       newTemplate("fish", fishTemplate),
+	"powershell": newTemplate("powershell", powershellTemplate),
+}
+
+func newTemplate(name, text string) *template.Template {
+	return template.Must(template.New(name).Funcs(template.FuncMap{
+		"join": strings.Join,
+		"names": func(flags []Flag) string {
+			var names []string
+			for _, f := range flags {
+				if f.Shorthand != "" {
+					names = append(names, "-"+f.Shorthand)
+				}
+				names = append(names, "--"+f.Name)
+			}
+			return strings.Join(names, " ")
+		},
+		"zshSpec":   zshSpec,
+		"fishQuote": fishQuote,
+		"psQuote":   psQuote,
+		"summary":   firstSentence,
+	}).Parse(text))
+}
+
+// firstSentence returns the first sentence of the usage of a flag, so
+// descriptions fit in the completion menus
+func firstSentence(usage string) string {
+	if i := strings.Index(usage, ". "); i > 0 {
+		usage = usage[:i]
+	}
+	return strings.TrimSpace(usage)
+}
+
+func zshSpec(f Flag) string {
+	r := strings.NewReplacer(`'`, `'\''`, `[`, `\[`, `]`, `\]`, `:`, `\:`)
+	desc := "[" + r.Replace(firstSentence(f.Usage)) + "]"
+
+	var action string
+	switch {
+	case len(f.Values) > 0:
+		action = ":" + f.Name + ":(" + strings.Join(f.Values, " ") + ")"
+	case f.Arg == ArgFile:
+		action = ":" + f.Name + ":_files"
+	case f.Arg == ArgDir:
+		action = ":" + f.Name + ":_files -/"
+	case f.Arg == ArgValue:
+		action = ":" + f.Name + ": "
+	}
+
+	if f.Shorthand == "" {
+		return fmt.Sprintf(`'--%s%s%s'`, f.Name, desc, action)
+	}
+	return fmt.Sprintf(`'(-%[1]s --%[2]s)'{-%[1]s,--%[2]s}'%[3]s%[4]s'`, f.Shorthand, f.Name, desc, action)
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+const bashTemplate = `# bash completion for task, generated by "task --completion bash"
+
+_task()
+{
+  local cur prev words cword
+  _init_completion -n = -n : || return
+
+  # Words after "--" are given to CLI_ARGS, and aren't given to the list of
+  # tasks below
+  local i
+  for i in "${!words[@]}"; do
+    if [ "${words[$i]}" == "--" ]; then
+      [ $cword -gt $i ] && return
+      words=( "${words[@]:0:$i}" )
+      break
+    fi
+  done
+
+  case "$prev" in
+{{- range .}}{{if .Arg}}
+    {{if .Shorthand}}-{{.Shorthand}}|{{end}}--{{.Name}})
+{{- if .Values}}
+      COMPREPLY=( $( compgen -W "{{join .Values " "}}" -- "$cur" ) )
+{{- else if eq .Arg "file"}}
+      _filedir
+{{- else if eq .Arg "dir"}}
+      _filedir -d
+{{- end}}
+      return 0
+      ;;
+{{- end}}{{end}}
+  esac
+
+  case "$cur" in
+    -*)
+      COMPREPLY=( $( compgen -W "{{names .}}" -- "$cur" ) )
+      return 0
+      ;;
+  esac
+
+  # The tasks are listed with the same --dir and --taskfile, leaving out the
+  # other flags, which could do something else than listing them
+  local cmd=( "${words[0]}" )
+  for (( i=1; i < cword; i++ )); do
+    case "${words[$i]}" in
+      -d|--dir|-t|--taskfile)
+        cmd+=( "${words[$i]}" "${words[$i+1]}" )
+        ;;
+      --dir=*|--taskfile=*)
+        cmd+=( "${words[$i]}" )
+        ;;
+    esac
+  done
+  local tasks=( $( "${cmd[@]}" --silent ${GO_TASK_COMPLETION_LIST_OPTION:---list-all} 2> /dev/null ) )
+  COMPREPLY=( $( compgen -W "${tasks[*]}" -- "$cur" ) )
+
+  # Task names may contain colons
+  __ltrim_colon_completions "$cur"
+}
+
+complete -F _task task
+`
+
+const zshTemplate = `#compdef task
+# zsh completion for task, generated by "task --completion zsh"
+
+function __task_list() {
+    local -a cmd tasks
+    local dir="${opt_args[-d]:-${opt_args[--dir]}}"
+    local taskfile="${opt_args[-t]:-${opt_args[--taskfile]}}"
+
+    cmd=("${words[1]}")
+    [[ -n "$dir" ]] && cmd+=(--dir "$dir")
+    [[ -n "$taskfile" ]] && cmd+=(--taskfile "$taskfile")
+
+    tasks=("${(@f)$("${cmd[@]}" --silent ${GO_TASK_COMPLETION_LIST_OPTION:---list-all} 2> /dev/null)}")
+    tasks=("${(@)tasks//:/\\:}")
+    _describe 'Task to run' tasks
+}
+
+_arguments -s \
+{{- range .}}
+    {{zshSpec .}} \
+{{- end}}
+    '*: :__task_list'
+`
+
+const fishTemplate = `# fish completion for task, generated by "task --completion fish"
+
+function __task_list
+    set -l list_option --list-all
+    set -q GO_TASK_COMPLETION_LIST_OPTION; and set list_option $GO_TASK_COMPLETION_LIST_OPTION
+    # The tasks are listed with the same --dir and --taskfile, leaving out
+    # the other flags, which could do something else than listing them
+    set -l words (commandline -opc)
+    set -l cmd $words[1]
+    set -l i 2
+    while test $i -le (count $words)
+        switch $words[$i]
+            case -d --dir -t --taskfile
+                set cmd $cmd $words[$i..(math $i + 1)]
+                set i (math $i + 1)
+            case '--dir=*' '--taskfile=*'
+                set cmd $cmd $words[$i]
+        end
+        set i (math $i + 1)
+    end
+    $cmd --silent $list_option 2> /dev/null
+end
+
+complete -c task -f -a '(__task_list)'
+{{- range .}}
+complete -c task{{if .Shorthand}} -s {{.Shorthand}}{{end}} -l {{.Name}} -d {{fishQuote (summary .Usage)}}
+{{- if .Values}} -x -a {{fishQuote (join .Values " ")}}
+{{- else if eq .Arg "file"}} -r -F
+{{- else if eq .Arg "dir"}} -x -a '(__fish_complete_directories)'
+{{- else if .Arg}} -x
+{{- end}}
+{{- end}}
+`
+
+const powershellTemplate = `# PowerShell completion for task, generated by "task --completion powershell"
+
+Register-ArgumentCompleter -Native -CommandName task -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    if ($wordToComplete -like '-*') {
+        $flags = @(
+{{- range $i, $f := .}}{{if $i}},{{end}}
+            @({{psQuote (print "--" $f.Name)}}, {{psQuote (summary $f.Usage)}})
+{{- if $f.Shorthand}},
+            @({{psQuote (print "-" $f.Shorthand)}}, {{psQuote (summary $f.Usage)}})
+{{- end}}
+{{- end}}
+        )
+        foreach ($flag in $flags) {
+            if ($flag[0] -like "$wordToComplete*") {
+                [System.Management.Automation.CompletionResult]::new($flag[0], $flag[0], 'ParameterName', $flag[1])
+            }
+        }
+        return
+    }
+
+    $listOption = if ($env:GO_TASK_COMPLETION_LIST_OPTION) { $env:GO_TASK_COMPLETION_LIST_OPTION } else { '--list-all' }
+    task --silent $listOption 2> $null | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`
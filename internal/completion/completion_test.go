@@ -0,0 +1,65 @@
+package completion
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testFlags = []Flag{
+	{Name: "dir", Shorthand: "d", Usage: "sets directory of execution", Arg: ArgDir},
+	{Name: "output", Shorthand: "o", Usage: "sets output style: [interleaved|group]", Arg: ArgValue, Values: []string{"interleaved", "group"}},
+	{Name: "force", Shorthand: "f", Usage: "forces execution. Even when it's up-to-date"},
+}
+
+func TestScript(t *testing.T) {
+	for _, shell := range Shells {
+		t.Run(shell, func(t *testing.T) {
+			var buff bytes.Buffer
+			assert.NoError(t, Script(&buff, shell, testFlags))
+			assert.Contains(t, buff.String(), "--list-all")
+			assert.Contains(t, buff.String(), "interleaved")
+		})
+	}
+}
+
+func TestScriptBash(t *testing.T) {
+	var buff bytes.Buffer
+	assert.NoError(t, Script(&buff, "bash", testFlags))
+	assert.Contains(t, buff.String(), "    -d|--dir)\n      _filedir -d\n")
+	assert.Contains(t, buff.String(), `compgen -W "-d --dir -o --output -f --force"`)
+
+	if _, err := exec.LookPath("bash"); err == nil {
+		cmd := exec.Command("bash", "-n")
+		cmd.Stdin = &buff
+		assert.NoError(t, cmd.Run())
+	}
+}
+
+func TestScriptZsh(t *testing.T) {
+	var buff bytes.Buffer
+	assert.NoError(t, Script(&buff, "zsh", testFlags))
+	assert.Contains(t, buff.String(), `'(-o --output)'{-o,--output}'[sets output style\: \[interleaved|group\]]:output:(interleaved group)'`)
+	assert.Contains(t, buff.String(), `'(-f --force)'{-f,--force}'[forces execution]'`)
+}
+
+func TestScriptFish(t *testing.T) {
+	var buff bytes.Buffer
+	assert.NoError(t, Script(&buff, "fish", testFlags))
+	assert.Contains(t, buff.String(), `complete -c task -s f -l force -d 'forces execution'`+"\n")
+	assert.Contains(t, buff.String(), `complete -c task -s o -l output -d 'sets output style: [interleaved|group]' -x -a 'interleaved group'`)
+}
+
+func TestScriptPowerShell(t *testing.T) {
+	var buff bytes.Buffer
+	assert.NoError(t, Script(&buff, "powershell", []Flag{{Name: "force", Usage: "runs tasks even when they're up-to-date"}}))
+	assert.Contains(t, buff.String(), `@('--force', 'runs tasks even when they''re up-to-date')`)
+}
+
+func TestScriptUnsupportedShell(t *testing.T) {
+	var buff bytes.Buffer
+	err := Script(&buff, "tcsh", testFlags)
+	assert.EqualError(t, err, `task: Unsupported shell "tcsh" for completion. Available options: "bash", "zsh", "fish", "powershell"`)
+}
@@ -0,0 +1,252 @@
+// Package dashboard renders the status and recent output of watched tasks
+// on a terminal.
+package dashboard
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the status of a watched task
+type Status int
+
+const (
+	Idle Status = iota
+	Running
+	Succeeded
+	Failed
+	Stopped
+)
+
+func (s Status) String() string {
+	switch s {
+	case Running:
+		return "running"
+	case Succeeded:
+		return "ok"
+	case Failed:
+		return "failed"
+	case Stopped:
+		return "stopped"
+	default:
+		return "idle"
+	}
+}
+
+// scrollback is the number of lines of output kept for each task
+const scrollback = 500
+
+var (
+	ansiRegexp = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+	// prefixRegexp matches the prefixes of the output of tasks, like
+	// "[build] ", and the messages about tasks, like `task: Task "build" is
+	// up to date`
+	prefixRegexp = regexp.MustCompile(`^(?:task: )?\[([^\]]+)\] |^task: .*?[Tt]ask "([^"]+)"`)
+)
+
+type task struct {
+	name     string
+	status   Status
+	started  time.Time
+	duration time.Duration
+	lines    []string
+}
+
+// Dashboard keeps the status and output of the watched tasks. It's written
+// to as the STDOUT and STDERR of the tasks: lines prefixed with the name of a
+// watched task, like "[build] ok", belong to it, and the other lines belong
+// to the tasks running when they're written.
+type Dashboard struct {
+	mutex    sync.Mutex
+	tasks    []*task
+	selected int
+	partial  []byte
+	dirty    bool
+}
+
+// New returns a dashboard of the tasks with the given names
+func New(names []string) *Dashboard {
+	d := &Dashboard{dirty: true}
+	for _, name := range names {
+		d.tasks = append(d.tasks, &task{name: name})
+	}
+	return d
+}
+
+// Start marks the task at index i as running
+func (d *Dashboard) Start(i int) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.tasks[i].status = Running
+	d.tasks[i].started = time.Now()
+	d.dirty = true
+}
+
+// Finish marks the task at index i as finished with the given status
+func (d *Dashboard) Finish(i int, status Status) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	t := d.tasks[i]
+	t.status = status
+	t.duration = time.Since(t.started)
+	d.dirty = true
+}
+
+// Select moves the selection by delta tasks, wrapping around
+func (d *Dashboard) Select(delta int) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	n := len(d.tasks)
+	d.selected = ((d.selected+delta)%n + n) % n
+	d.dirty = true
+}
+
+// Selected returns the index of the selected task
+func (d *Dashboard) Selected() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.selected
+}
+
+// Dirty reports whether the dashboard changed since it was last rendered
+func (d *Dashboard) Dirty() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.dirty
+}
+
+// Write implements io.Writer, adding the complete lines written to the
+// output of the tasks they belong to
+func (d *Dashboard) Write(p []byte) (int, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.partial = append(d.partial, p...)
+	for {
+		i := bytes.IndexByte(d.partial, '\n')
+		if i < 0 {
+			break
+		}
+		d.addLine(string(d.partial[:i]))
+		d.partial = d.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+func (d *Dashboard) addLine(line string) {
+	line = strings.TrimRight(ansiRegexp.ReplaceAllString(line, ""), "\r")
+	line = strings.ReplaceAll(line, "\t", "    ")
+
+	var owners []*task
+	if m := prefixRegexp.FindStringSubmatch(line); m != nil {
+		for _, t := range d.tasks {
+			if t.name == m[1] || t.name == m[2] {
+				owners = append(owners, t)
+			}
+		}
+	}
+	if owners == nil {
+		for _, t := range d.tasks {
+			if t.status == Running {
+				owners = append(owners, t)
+			}
+		}
+	}
+	if owners == nil {
+		owners = []*task{d.tasks[d.selected]}
+	}
+
+	for _, t := range owners {
+		t.lines = append(t.lines, line)
+		if len(t.lines) > scrollback {
+			t.lines = t.lines[len(t.lines)-scrollback:]
+		}
+	}
+	d.dirty = true
+}
+
+// Render returns the dashboard drawn in a terminal of the given size, as
+// lines
+func (d *Dashboard) Render(width, height int) []string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.dirty = false
+
+	lines := []string{"Watching tasks  (up/down: select, r: rerun, s: stop, q: quit)", ""}
+
+	nameWidth := 0
+	for _, t := range d.tasks {
+		if len(t.name) > nameWidth {
+			nameWidth = len(t.name)
+		}
+	}
+	for i, t := range d.tasks {
+		cursor := " "
+		if i == d.selected {
+			cursor = ">"
+		}
+		var duration string
+		switch t.status {
+		case Running:
+			duration = formatDuration(time.Since(t.started))
+		case Idle:
+		default:
+			duration = formatDuration(t.duration)
+		}
+		lines = append(lines, fmt.Sprintf("%s %-*s  %-7s  %s", cursor, nameWidth, t.name, t.status, duration))
+	}
+
+	selected := d.tasks[d.selected]
+	lines = append(lines, "", strings.Repeat("-", 3)+" "+selected.name+" "+strings.Repeat("-", max(0, width-len(selected.name)-5)))
+
+	if room := height - len(lines); room > 0 {
+		output := selected.lines
+		if len(output) > room {
+			output = output[len(output)-room:]
+		}
+		lines = append(lines, output...)
+	}
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for i, line := range lines {
+		lines[i] = truncate(line, width)
+	}
+	return lines
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width])
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,51 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	d := New([]string{"build", "test"})
+	d.Start(0)
+
+	fmt.Fprint(d, "[build] compiling\n[test] ok\n\x1b[32mtask: [test] go test\x1b[0m\n")
+	fmt.Fprint(d, "task: Task \"test\" is up to date\n")
+	fmt.Fprint(d, "no prefix")
+	fmt.Fprint(d, " at all\n")
+
+	assert.Equal(t, []string{"[build] compiling", "no prefix at all"}, d.tasks[0].lines)
+	assert.Equal(t, []string{"[test] ok", "task: [test] go test", `task: Task "test" is up to date`}, d.tasks[1].lines)
+
+	// Without running tasks, unknown lines belong to the selected one
+	d.Finish(0, Succeeded)
+	d.Select(1)
+	fmt.Fprint(d, "done\n")
+	assert.Equal(t, "done", d.tasks[1].lines[3])
+}
+
+func TestRender(t *testing.T) {
+	d := New([]string{"build", "lint"})
+	d.Finish(1, Failed)
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(d, "[build] line %d\n", i)
+	}
+	assert.True(t, d.Dirty())
+
+	lines := d.Render(40, 9)
+	assert.False(t, d.Dirty())
+	assert.Len(t, lines, 9)
+	assert.Equal(t, "> build  idle", strings.TrimSpace(lines[2]))
+	assert.True(t, strings.HasPrefix(lines[3], "  lint   failed"))
+	assert.Equal(t, "--- build "+strings.Repeat("-", 30), lines[5])
+	assert.Equal(t, []string{"[build] line 7", "[build] line 8", "[build] line 9"}, lines[6:])
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), 40)
+	}
+
+	d.Select(-1)
+	assert.Equal(t, 1, d.Selected())
+}
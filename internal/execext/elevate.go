@@ -0,0 +1,168 @@
+package execext
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"time"
+
+	"mvdan.cc/sh/v3/interp"
+)
+
+// Elevate asks for the credentials needed to run privileged commands, so
+// they're asked once instead of for every command. It does nothing when
+// Task already runs as root.
+func Elevate(ctx context.Context, stdin io.Reader, stderr io.Writer) error {
+	name, args := elevateArgs(runtime.GOOS, isRoot())
+	if name == "" {
+		return nil
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stderr
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return errors.New("execext: " + name + " is needed to run privileged commands, but it's not installed")
+		}
+		return err
+	}
+	return nil
+}
+
+// elevateArgs returns the program and the arguments caching the credentials
+// of the elevation helper of the given OS
+func elevateArgs(goos string, root bool) (string, []string) {
+	switch {
+	case goos == "windows":
+		return "gsudo", []string{"cache", "on"}
+	case root:
+		return "", nil
+	default:
+		return "sudo", []string{"--validate"}
+	}
+}
+
+// privilegedArg is the first argument of the Task process started elevated
+// to run a privileged command, followed by the command as JSON. Unlike the
+// environment, the arguments are kept by the elevation helpers.
+const privilegedArg = "__task_privileged"
+
+// privilegedRequest is the command an elevated Task process runs
+type privilegedRequest struct {
+	Command string   `json:"command"`
+	Dir     string   `json:"dir"`
+	Env     []string `json:"env"`
+}
+
+// runPrivilegedCommand runs the command with the shell of Task, in a new Task
+// process run as root with sudo, or elevated with gsudo on Windows. Only the
+// variables the environment adds to the one of Task are passed, since
+// elevation helpers reset the environment.
+func runPrivilegedCommand(ctx context.Context, opts *RunCommandOptions) error {
+	if isRoot() {
+		return runShell(ctx, opts, execHandler(15*time.Second), opts.Stdin, opts.Stdout, opts.Stderr)
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(&privilegedRequest{
+		Command: opts.Command,
+		Dir:     opts.Dir,
+		Env:     addedEnv(os.Environ(), opts.Env),
+	})
+	if err != nil {
+		return err
+	}
+
+	name, args := privilegedArgs(runtime.GOOS, self, string(data))
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = opts.Dir
+	cmd.Env = opts.Env
+	cmd.Stdin = opts.Stdin
+	cmd.Stdout = opts.Stdout
+	cmd.Stderr = opts.Stderr
+
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return interp.NewExitStatus(uint8(exitErr.ExitCode()))
+	}
+	return err
+}
+
+// privilegedArgs returns the program and the arguments running the given
+// Task executable elevated, to run the request
+func privilegedArgs(goos, self, request string) (string, []string) {
+	name := "sudo"
+	if goos == "windows" {
+		name = "gsudo"
+	}
+	return name, []string{"--", self, privilegedArg, request}
+}
+
+// IsPrivilegedChild returns true if the process was started by Task to run a
+// privileged command, in which case RunPrivilegedChild must be called before
+// anything else.
+func IsPrivilegedChild() bool {
+	return len(os.Args) == 3 && os.Args[1] == privilegedArg
+}
+
+// RunPrivilegedChild runs the privileged command given by the parent Task
+// process, returning the exit code of the process
+func RunPrivilegedChild() int {
+	var req privilegedRequest
+	if err := json.Unmarshal([]byte(os.Args[2]), &req); err != nil {
+		fmt.Fprintf(os.Stderr, "execext: invalid privileged command: %v\n", err)
+		return 1
+	}
+
+	err := RunCommand(context.Background(), &RunCommandOptions{
+		Command: req.Command,
+		Dir:     req.Dir,
+		Env:     append(os.Environ(), req.Env...),
+		Stdin:   os.Stdin,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+	})
+	if status, ok := interp.IsExitStatus(err); ok {
+		return int(status)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// addedEnv returns the variables of environ that are not in parent, or that
+// have a different value there, sorted
+func addedEnv(parent, environ []string) []string {
+	if environ == nil {
+		return nil
+	}
+
+	set := make(map[string]bool, len(parent))
+	for _, kv := range parent {
+		set[kv] = true
+	}
+	var added []string
+	for _, kv := range environ {
+		if !set[kv] {
+			added = append(added, kv)
+		}
+	}
+	sort.Strings(added)
+	return added
+}
+
+func isRoot() bool {
+	return runtime.GOOS != "windows" && os.Geteuid() == 0
+}
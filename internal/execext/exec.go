@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"time"
 
@@ -20,9 +21,25 @@ type RunCommandOptions struct {
 	Command string
 	Dir     string
 	Env     []string
-	Stdin   io.Reader
-	Stdout  io.Writer
-	Stderr  io.Writer
+	WSL     bool
+	// Nix runs the command inside of the Nix development shell NixShell
+	Nix      bool
+	NixShell string
+	Stdin    io.Reader
+	Stdout   io.Writer
+	Stderr   io.Writer
+	// Privileged runs the command as root, or elevated on Windows
+	Privileged bool
+	// Sandbox restricts what the command may change, when set
+	Sandbox *Sandbox
+	// TTY runs the command in a pseudo-terminal, forwarding Stdin to it and
+	// its output to Stdout, for programs that require a terminal
+	TTY bool
+	// KillTree kills the processes started by the programs of the command
+	// too when it's canceled, not only the programs. Elsewhere than on
+	// Windows, they run in a process group of their own for this, so they
+	// can't read from the terminal.
+	KillTree bool
 }
 
 var (
@@ -35,23 +52,47 @@ func RunCommand(ctx context.Context, opts *RunCommandOptions) error {
 	if opts == nil {
 		return ErrNilOptions
 	}
+	if opts.Sandbox != nil {
+		return runSandboxedCommand(ctx, opts)
+	}
+	if opts.WSL && runtime.GOOS == "windows" {
+		return runWSLCommand(ctx, opts)
+	}
+	if opts.Privileged {
+		return runPrivilegedCommand(ctx, opts)
+	}
+	if opts.Nix {
+		return runNixCommand(ctx, opts)
+	}
+	if opts.TTY {
+		return runTTYCommand(ctx, opts)
+	}
+	handler := execHandler(15 * time.Second)
+	if opts.KillTree {
+		handler = treeExecHandler(15 * time.Second)
+	}
+	return runShell(ctx, opts, handler, opts.Stdin, opts.Stdout, opts.Stderr)
+}
 
+// runShell runs the command in the shell interpreter, with the given
+// handler running the programs it calls
+func runShell(ctx context.Context, opts *RunCommandOptions, handler interp.ExecHandlerFunc, stdin io.Reader, stdout, stderr io.Writer) error {
 	p, err := syntax.NewParser().Parse(strings.NewReader(opts.Command), "")
 	if err != nil {
 		return err
 	}
 
 	environ := opts.Env
-	if len(environ) == 0 {
+	if environ == nil {
 		environ = os.Environ()
 	}
 
 	r, err := interp.New(
 		interp.Params("-e"),
 		interp.Env(expand.ListEnviron(environ...)),
-		interp.ExecHandler(interp.DefaultExecHandler(15*time.Second)),
+		interp.ExecHandler(handler),
 		interp.OpenHandler(openHandler),
-		interp.StdIO(opts.Stdin, opts.Stdout, opts.Stderr),
+		interp.StdIO(stdin, stdout, stderr),
 		dirOption(opts.Dir),
 	)
 	if err != nil {
@@ -111,3 +152,23 @@ func dirOption(path string) interp.RunnerOption {
 		return err
 	}
 }
+
+// execEnv returns the exported variables of the environment of the
+// interpreter, like the default handler passes them to programs
+func execEnv(env expand.Environ) []string {
+	var list []string
+	env.Each(func(name string, vr expand.Variable) bool {
+		if !vr.IsSet() {
+			for i, kv := range list {
+				if strings.HasPrefix(kv, name+"=") {
+					list[i] = ""
+				}
+			}
+		}
+		if vr.Exported && vr.Kind == expand.String {
+			list = append(list, name+"="+vr.String())
+		}
+		return true
+	})
+	return list
+}
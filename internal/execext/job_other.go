@@ -0,0 +1,16 @@
+//go:build !windows
+
+package execext
+
+import (
+	"time"
+
+	"mvdan.cc/sh/v3/interp"
+)
+
+// execHandler runs the programs called by commands. Elsewhere than on
+// Windows, it's the default handler of the interpreter, which interrupts
+// them when the command is canceled, before killing them.
+func execHandler(killTimeout time.Duration) interp.ExecHandlerFunc {
+	return interp.DefaultExecHandler(killTimeout)
+}
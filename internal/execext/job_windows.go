@@ -0,0 +1,118 @@
+//go:build windows
+
+package execext
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"mvdan.cc/sh/v3/interp"
+)
+
+// execHandler runs the programs called by commands like the default handler
+// of the interpreter does, but in a job object, so the whole tree of
+// processes they start is killed when the command is canceled or ends.
+// Otherwise, killing a program like "npm" would leave the "node" processes
+// it started running.
+func execHandler(killTimeout time.Duration) interp.ExecHandlerFunc {
+	return func(ctx context.Context, args []string) error {
+		hc := interp.HandlerCtx(ctx)
+		path, err := interp.LookPathDir(hc.Dir, hc.Env, args[0])
+		if err != nil {
+			fmt.Fprintln(hc.Stderr, err)
+			return interp.NewExitStatus(127)
+		}
+
+		// Without a job object, like when Task itself runs in a job that
+		// doesn't allow nested ones, only the program is killed
+		job, err := newKillOnCloseJob()
+		if err != nil {
+			return interp.DefaultExecHandler(killTimeout)(ctx, args)
+		}
+		defer windows.CloseHandle(job)
+
+		cmd := exec.Cmd{
+			Path:   path,
+			Args:   args,
+			Env:    execEnv(hc.Env),
+			Dir:    hc.Dir,
+			Stdin:  hc.Stdin,
+			Stdout: hc.Stdout,
+			Stderr: hc.Stderr,
+		}
+		if err := cmd.Start(); err != nil {
+			fmt.Fprintf(hc.Stderr, "%v\n", err)
+			return interp.NewExitStatus(127)
+		}
+		// The processes started before the program is assigned to the job
+		// escape it. It's a short window, since os/exec can't create the
+		// process suspended and resume it afterwards.
+		assigned := assignToJob(job, cmd.Process.Pid) == nil
+
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-ctx.Done():
+				if assigned {
+					_ = windows.TerminateJobObject(job, 1)
+				} else {
+					_ = cmd.Process.Kill()
+				}
+			case <-done:
+			}
+		}()
+
+		err = cmd.Wait()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return interp.NewExitStatus(uint8(exitErr.ExitCode()))
+		}
+		return err
+	}
+}
+
+// treeExecHandler is execHandler, since it already kills the whole tree of
+// processes
+func treeExecHandler(killTimeout time.Duration) interp.ExecHandlerFunc {
+	return execHandler(killTimeout)
+}
+
+// newKillOnCloseJob creates a job object whose processes are killed once it's
+// closed, including when Task exits
+func newKillOnCloseJob() (windows.Handle, error) {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+		BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{
+			LimitFlags: windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+		},
+	}
+	if _, err := windows.SetInformationJobObject(
+		job,
+		windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)),
+		uint32(unsafe.Sizeof(info)),
+	); err != nil {
+		windows.CloseHandle(job)
+		return 0, err
+	}
+	return job, nil
+}
+
+func assignToJob(job windows.Handle, pid int) error {
+	process, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(pid))
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(process)
+	return windows.AssignProcessToJobObject(job, process)
+}
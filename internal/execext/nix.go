@@ -0,0 +1,48 @@
+package execext
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+
+	"mvdan.cc/sh/v3/interp"
+)
+
+// runNixCommand runs the command inside of a Nix development shell: with
+// "nix-shell" when the shell is a ".nix" file, and with "nix develop"
+// otherwise, in which case it may be any flake output, like ".#devshell".
+func runNixCommand(ctx context.Context, opts *RunCommandOptions) error {
+	name, args := nixArgs(opts.NixShell, opts.Command)
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = opts.Dir
+	cmd.Env = opts.Env
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Stdin = opts.Stdin
+	cmd.Stdout = opts.Stdout
+	cmd.Stderr = opts.Stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return interp.NewExitStatus(uint8(exitErr.ExitCode()))
+	}
+	return err
+}
+
+// nixArgs returns the program and the arguments to run the command inside
+// of the given shell
+func nixArgs(shell, command string) (string, []string) {
+	if strings.HasSuffix(shell, ".nix") {
+		return "nix-shell", []string{shell, "--run", command}
+	}
+
+	args := []string{"develop"}
+	if shell != "" {
+		args = append(args, shell)
+	}
+	return "nix", append(args, "--command", "sh", "-c", command)
+}
@@ -0,0 +1,144 @@
+package execext
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"mvdan.cc/sh/v3/interp"
+)
+
+// Sandbox restricts what a command may change: it may only write to the
+// given paths and to a temporary directory of its own, set in TMPDIR, and may
+// be denied the network
+type Sandbox struct {
+	// Writable are the files and directories the command may write to,
+	// including what's inside of them
+	Writable []string
+	// Network allows the command to connect to other hosts
+	Network bool
+}
+
+// sandboxEnv passes the command to run to the sandboxed Task process
+const sandboxEnv = "TASK_SANDBOX"
+
+// sandboxRequest is the command a sandboxed Task process runs
+type sandboxRequest struct {
+	Command  string   `json:"command"`
+	Dir      string   `json:"dir"`
+	Writable []string `json:"writable"`
+	Network  bool     `json:"network"`
+	// Restricted is set once the sandbox is entered
+	Restricted bool `json:"restricted"`
+}
+
+// runSandboxedCommand runs the command with the shell of Task, in a new Task
+// process restricted by the sandbox of the operating system: Landlock and
+// seccomp on Linux, and sandbox-exec on macOS.
+func runSandboxedCommand(ctx context.Context, opts *RunCommandOptions) error {
+	if opts.Privileged || opts.Nix || (opts.WSL && runtime.GOOS == "windows") {
+		return errors.New("execext: sandboxed commands can't be privileged or run inside of WSL or Nix")
+	}
+	if err := checkSandbox(); err != nil {
+		return err
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	// The temporary directory may contain the project, so the command gets
+	// its own instead
+	tmpDir, err := os.MkdirTemp("", "task-sandbox-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	req := &sandboxRequest{
+		Command:  opts.Command,
+		Dir:      opts.Dir,
+		Writable: append(append([]string{}, opts.Sandbox.Writable...), tmpDir),
+		Network:  opts.Sandbox.Network,
+	}
+	name, args := sandboxArgs(self, req)
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = opts.Dir
+	cmd.Env = opts.Env
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(withoutSandboxEnv(cmd.Env), "TMPDIR="+tmpDir, sandboxEnv+"="+string(data))
+	cmd.Stdin = opts.Stdin
+	cmd.Stdout = opts.Stdout
+	cmd.Stderr = opts.Stderr
+
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return interp.NewExitStatus(uint8(exitErr.ExitCode()))
+	}
+	return err
+}
+
+// IsSandboxChild returns true if the process was started by Task to run a
+// sandboxed command, in which case RunSandboxChild must be called before
+// anything else.
+func IsSandboxChild() bool {
+	return os.Getenv(sandboxEnv) != ""
+}
+
+// RunSandboxChild enters the sandbox and runs the command given by the
+// parent Task process, returning the exit code of the process
+func RunSandboxChild() int {
+	var req sandboxRequest
+	if err := json.Unmarshal([]byte(os.Getenv(sandboxEnv)), &req); err != nil {
+		fmt.Fprintf(os.Stderr, "execext: invalid sandboxed command: %v\n", err)
+		return 1
+	}
+	if !req.Restricted {
+		// Only returns if the sandbox couldn't be entered
+		err := enterSandbox(&req)
+		fmt.Fprintf(os.Stderr, "execext: could not enter the sandbox: %v\n", err)
+		return 1
+	}
+
+	os.Unsetenv(sandboxEnv)
+	err := RunCommand(context.Background(), &RunCommandOptions{
+		Command: req.Command,
+		Dir:     req.Dir,
+		Stdin:   os.Stdin,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+	})
+	if status, ok := interp.IsExitStatus(err); ok {
+		return int(status)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// withoutSandboxEnv removes the sandboxed command from the environment, so
+// the ones started by it aren't sandboxed commands themselves
+func withoutSandboxEnv(environ []string) []string {
+	result := make([]string, 0, len(environ))
+	for _, v := range environ {
+		if !strings.HasPrefix(v, sandboxEnv+"=") {
+			result = append(result, v)
+		}
+	}
+	return result
+}
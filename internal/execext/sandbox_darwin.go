@@ -0,0 +1,40 @@
+package execext
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+func checkSandbox() error {
+	if _, err := exec.LookPath("sandbox-exec"); err != nil {
+		return errors.New("execext: sandbox-exec is needed to run sandboxed commands, but it's not installed")
+	}
+	return nil
+}
+
+// sandboxArgs runs Task with sandbox-exec, which restricts the whole process
+func sandboxArgs(self string, req *sandboxRequest) (string, []string) {
+	req.Restricted = true
+	return "sandbox-exec", []string{"-p", sandboxProfile(req), self}
+}
+
+func enterSandbox(*sandboxRequest) error {
+	return errors.New("sandboxed commands must be run with sandbox-exec")
+}
+
+// sandboxProfile returns the profile of sandbox-exec denying writes outside
+// of the writable paths, and the network unless it's allowed
+func sandboxProfile(req *sandboxRequest) string {
+	var b strings.Builder
+	b.WriteString("(version 1)\n(allow default)\n(deny file-write*)\n")
+	for _, path := range req.Writable {
+		fmt.Fprintf(&b, "(allow file-write* (subpath %s))\n", strconv.Quote(path))
+	}
+	if !req.Network {
+		b.WriteString("(deny network-outbound (remote ip))\n(deny network-bind (local ip))\n")
+	}
+	return b.String()
+}
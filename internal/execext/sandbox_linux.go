@@ -0,0 +1,195 @@
+package execext
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// landlockAccessFsTruncate is only handled by Landlock since ABI 3
+	landlockAccessFsTruncate = 1 << 14
+
+	// landlockWriteAccess are the rights to change files handled by the
+	// first version of Landlock
+	landlockWriteAccess = unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+
+	// landlockFileAccess are the rights that may be given to files, instead
+	// of directories
+	landlockFileAccess = unix.LANDLOCK_ACCESS_FS_WRITE_FILE | landlockAccessFsTruncate
+
+	seccompRetAllow = 0x7fff0000
+	seccompRetErrno = 0x00050000
+
+	// x32SyscallBit is set in the number of the syscalls of the x32 ABI of
+	// x86_64, which would otherwise bypass the filter of sockets
+	x32SyscallBit = 0x40000000
+)
+
+// auditArchs are the architectures the network can be denied on, since the
+// arguments of their syscalls are known to the seccomp filter
+var auditArchs = map[string]uint32{
+	"amd64":   unix.AUDIT_ARCH_X86_64,
+	"arm64":   unix.AUDIT_ARCH_AARCH64,
+	"riscv64": unix.AUDIT_ARCH_RISCV64,
+}
+
+func checkSandbox() error {
+	if _, err := landlockABI(); err != nil {
+		return fmt.Errorf("execext: sandboxed commands need Landlock, which isn't available: %w", err)
+	}
+	return nil
+}
+
+func sandboxArgs(self string, _ *sandboxRequest) (string, []string) {
+	return self, nil
+}
+
+// enterSandbox restricts the current thread, then executes Task again to run
+// the command. Landlock and seccomp only restrict the thread they're applied
+// to, and executing a program only keeps that thread.
+func enterSandbox(req *sandboxRequest) error {
+	runtime.LockOSThread()
+
+	abi, err := landlockABI()
+	if err != nil {
+		return err
+	}
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return err
+	}
+	if err := restrictWrites(abi, req.Writable); err != nil {
+		return err
+	}
+	if !req.Network {
+		if err := denyNetwork(); err != nil {
+			return err
+		}
+	}
+
+	req.Restricted = true
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	env := append(withoutSandboxEnv(os.Environ()), sandboxEnv+"="+string(data))
+	return unix.Exec(self, []string{self}, env)
+}
+
+func landlockABI() (int, error) {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(abi), nil
+}
+
+// restrictWrites denies changing any file outside of the given paths. The
+// paths that don't exist are ignored.
+func restrictWrites(abi int, writable []string) error {
+	access := uint64(landlockWriteAccess)
+	if abi >= 2 {
+		access |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		access |= landlockAccessFsTruncate
+	}
+
+	attr := unix.LandlockRulesetAttr{Access_fs: access}
+	ruleset, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return errno
+	}
+	defer unix.Close(int(ruleset))
+
+	for _, path := range writable {
+		if err := allowWrites(int(ruleset), access, path); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, ruleset, 0, 0); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func allowWrites(ruleset int, access uint64, path string) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if errors.Is(err, unix.ENOENT) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+
+	var stat unix.Stat_t
+	if err := unix.Fstat(fd, &stat); err != nil {
+		return err
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= landlockFileAccess
+	}
+
+	rule := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(ruleset), unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&rule)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// denyNetwork makes creating IPv4 and IPv6 sockets fail with a permission
+// error. Other sockets, like Unix ones, are still allowed.
+func denyNetwork() error {
+	arch, ok := auditArchs[runtime.GOARCH]
+	if !ok {
+		return fmt.Errorf("the network can't be denied on %s", runtime.GOARCH)
+	}
+	deny := uint32(seccompRetErrno | unix.EACCES)
+
+	filter := []unix.SockFilter{
+		// Syscalls of other architectures are denied
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, 4),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, arch, 1, 0),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, deny),
+		// socket(AF_INET or AF_INET6, ...) is denied
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, 0),
+		bpfJump(unix.BPF_JMP|unix.BPF_JGE|unix.BPF_K, x32SyscallBit, 4, 0),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, unix.SYS_SOCKET, 0, 4),
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, 16),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, unix.AF_INET, 1, 0),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, unix.AF_INET6, 0, 1),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, deny),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, seccompRetAllow),
+	}
+	prog := unix.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]}
+	return unix.Prctl(unix.PR_SET_SECCOMP, unix.SECCOMP_MODE_FILTER, uintptr(unsafe.Pointer(&prog)), 0, 0)
+}
+
+func bpfStmt(code uint16, k uint32) unix.SockFilter {
+	return unix.SockFilter{Code: code, K: k}
+}
+
+func bpfJump(code uint16, k uint32, jt, jf uint8) unix.SockFilter {
+	return unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+}
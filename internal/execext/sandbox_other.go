@@ -0,0 +1,20 @@
+//go:build !linux && !darwin
+
+package execext
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func checkSandbox() error {
+	return fmt.Errorf("execext: sandboxed commands aren't supported on %s", runtime.GOOS)
+}
+
+func sandboxArgs(self string, _ *sandboxRequest) (string, []string) {
+	return self, nil
+}
+
+func enterSandbox(*sandboxRequest) error {
+	return checkSandbox()
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !windows
+
+package execext
+
+import (
+	"time"
+
+	"mvdan.cc/sh/v3/interp"
+)
+
+// treeExecHandler is the default handler of the interpreter, which only
+// interrupts the programs themselves when the command is canceled
+func treeExecHandler(killTimeout time.Duration) interp.ExecHandlerFunc {
+	return interp.DefaultExecHandler(killTimeout)
+}
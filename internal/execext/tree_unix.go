@@ -0,0 +1,100 @@
+//go:build linux || darwin
+
+package execext
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"mvdan.cc/sh/v3/interp"
+)
+
+// treeExecHandler runs the programs called by commands in a process group
+// of their own, so the processes they start are interrupted with them when
+// the command is canceled, before being killed. Being out of the foreground
+// group, they can't read from the terminal.
+func treeExecHandler(killTimeout time.Duration) interp.ExecHandlerFunc {
+	return func(ctx context.Context, args []string) error {
+		hc := interp.HandlerCtx(ctx)
+		path, err := interp.LookPathDir(hc.Dir, hc.Env, args[0])
+		if err != nil {
+			fmt.Fprintln(hc.Stderr, err)
+			return interp.NewExitStatus(127)
+		}
+
+		cmd := &exec.Cmd{
+			Path:        path,
+			Args:        args,
+			Env:         execEnv(hc.Env),
+			Dir:         hc.Dir,
+			Stdin:       hc.Stdin,
+			Stdout:      hc.Stdout,
+			Stderr:      hc.Stderr,
+			SysProcAttr: &syscall.SysProcAttr{Setpgid: true},
+		}
+		return runGroup(ctx, cmd, killTimeout)
+	}
+}
+
+// runGroup runs a program that leads a process group, interrupting the
+// group when the context is canceled, and killing it once the program ends,
+// or after killTimeout
+func runGroup(ctx context.Context, cmd *exec.Cmd, killTimeout time.Duration) error {
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(cmd.Stderr, "%v\n", err)
+		return interp.NewExitStatus(127)
+	}
+
+	pid := cmd.Process.Pid
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+			return
+		}
+		_ = unix.Kill(-pid, unix.SIGINT)
+
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		timeout := time.After(killTimeout)
+		for {
+			select {
+			case <-timeout:
+				_ = unix.Kill(-pid, unix.SIGKILL)
+				return
+			case <-ticker.C:
+				// The processes of the group ignoring the interrupt, like
+				// the ones started in the background by a shell, are killed
+				// once the program ended, since they may keep its outputs
+				// open, which it's waited for
+				if unix.Kill(pid, 0) == unix.ESRCH {
+					_ = unix.Kill(-pid, unix.SIGKILL)
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err := cmd.Wait()
+	if ctx.Err() != nil {
+		_ = unix.Kill(-pid, unix.SIGKILL)
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return interp.NewExitStatus(uint8(128 + status.Signal()))
+		}
+		return interp.NewExitStatus(uint8(exitErr.ExitCode()))
+	}
+	return err
+}
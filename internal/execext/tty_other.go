@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+package execext
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+func runTTYCommand(context.Context, *RunCommandOptions) error {
+	return fmt.Errorf("execext: commands with a pseudo-terminal aren't supported on %s", runtime.GOOS)
+}
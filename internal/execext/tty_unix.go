@@ -0,0 +1,106 @@
+//go:build linux || darwin
+
+package execext
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+	"mvdan.cc/sh/v3/interp"
+)
+
+// runTTYCommand runs the command with a pseudo-terminal as its standard
+// input and outputs. When Task's own input is a terminal, it's put in raw
+// mode meanwhile, so keys like Ctrl+C reach the programs instead of Task.
+func runTTYCommand(ctx context.Context, opts *RunCommandOptions) error {
+	master, slave, err := openPTY()
+	if err != nil {
+		return fmt.Errorf("execext: could not allocate a pseudo-terminal: %w", err)
+	}
+	defer master.Close()
+
+	if f, ok := opts.Stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fd := int(f.Fd())
+		resizePTY(fd, master)
+		winch := make(chan os.Signal, 1)
+		signal.Notify(winch, syscall.SIGWINCH)
+		defer func() {
+			signal.Stop(winch)
+			close(winch)
+		}()
+		go func() {
+			for range winch {
+				resizePTY(fd, master)
+			}
+		}()
+
+		if state, err := term.MakeRaw(fd); err == nil {
+			defer func() { _ = term.Restore(fd, state) }()
+		}
+	}
+
+	// The copy of the input only stops on the next read after the command
+	// ends, since reads can't be interrupted
+	if opts.Stdin != nil {
+		go func() { _, _ = io.Copy(master, opts.Stdin) }()
+	}
+	copied := make(chan struct{})
+	go func() {
+		// Reading fails with EIO once the command closed the terminal
+		_, _ = io.Copy(opts.Stdout, master)
+		close(copied)
+	}()
+
+	err = runShell(ctx, opts, ttyExecHandler(slave, 15*time.Second), slave, slave, slave)
+	slave.Close()
+	<-copied
+	return err
+}
+
+// ttyExecHandler runs the programs called by commands in a session of their
+// own, with the given terminal as their controlling terminal when it's their
+// input. They're interrupted when the command is canceled, with the other
+// processes of their group, before being killed.
+func ttyExecHandler(tty *os.File, killTimeout time.Duration) interp.ExecHandlerFunc {
+	return func(ctx context.Context, args []string) error {
+		hc := interp.HandlerCtx(ctx)
+		path, err := interp.LookPathDir(hc.Dir, hc.Env, args[0])
+		if err != nil {
+			fmt.Fprintln(hc.Stderr, err)
+			return interp.NewExitStatus(127)
+		}
+
+		cmd := &exec.Cmd{
+			Path:        path,
+			Args:        args,
+			Env:         execEnv(hc.Env),
+			Dir:         hc.Dir,
+			Stdin:       hc.Stdin,
+			Stdout:      hc.Stdout,
+			Stderr:      hc.Stderr,
+			SysProcAttr: &syscall.SysProcAttr{Setsid: true},
+		}
+		if hc.Stdin == io.Reader(tty) {
+			cmd.SysProcAttr.Setctty = true
+			cmd.SysProcAttr.Ctty = 0
+		}
+		return runGroup(ctx, cmd, killTimeout)
+	}
+}
+
+// resizePTY gives the pseudo-terminal the size of the terminal of Task
+func resizePTY(fd int, pty *os.File) {
+	ws, err := unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ)
+	if err != nil {
+		return
+	}
+	_ = unix.IoctlSetWinsize(int(pty.Fd()), unix.TIOCSWINSZ, ws)
+}
@@ -0,0 +1,241 @@
+//go:build windows
+
+package execext
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"mvdan.cc/sh/v3/interp"
+)
+
+// procThreadAttributePseudoConsole attaches a process to a pseudo console
+const procThreadAttributePseudoConsole = 0x20016
+
+var (
+	kernel32                      = windows.NewLazySystemDLL("kernel32.dll")
+	procCreatePseudoConsole       = kernel32.NewProc("CreatePseudoConsole")
+	procClosePseudoConsole        = kernel32.NewProc("ClosePseudoConsole")
+	procUpdateProcThreadAttribute = kernel32.NewProc("UpdateProcThreadAttribute")
+)
+
+// runTTYCommand runs the command with the programs it calls attached to a
+// pseudo console (ConPTY), available since Windows 10 1809. When Task's own
+// input is a console, it's put in raw mode meanwhile, so keys like Ctrl+C
+// reach the programs instead of Task.
+func runTTYCommand(ctx context.Context, opts *RunCommandOptions) error {
+	if err := procCreatePseudoConsole.Find(); err != nil {
+		return fmt.Errorf("execext: pseudo consoles aren't supported by this version of Windows: %w", err)
+	}
+
+	inRead, inWrite, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	outRead, outWrite, err := os.Pipe()
+	if err != nil {
+		inRead.Close()
+		inWrite.Close()
+		return err
+	}
+	defer inWrite.Close()
+	defer outRead.Close()
+
+	if f, ok := opts.Stdin.(*os.File); ok {
+		h := windows.Handle(f.Fd())
+		var mode uint32
+		if windows.GetConsoleMode(h, &mode) == nil {
+			raw := mode &^ (windows.ENABLE_ECHO_INPUT | windows.ENABLE_PROCESSED_INPUT | windows.ENABLE_LINE_INPUT)
+			if windows.SetConsoleMode(h, raw|windows.ENABLE_VIRTUAL_TERMINAL_INPUT) == nil {
+				defer func() { _ = windows.SetConsoleMode(h, mode) }()
+			}
+		}
+	}
+	size := windows.Coord{X: 80, Y: 25}
+	var info windows.ConsoleScreenBufferInfo
+	if windows.GetConsoleScreenBufferInfo(windows.Stdout, &info) == nil {
+		size.X = info.Window.Right - info.Window.Left + 1
+		size.Y = info.Window.Bottom - info.Window.Top + 1
+	}
+
+	var console windows.Handle
+	r, _, _ := procCreatePseudoConsole.Call(
+		uintptr(*(*uint32)(unsafe.Pointer(&size))),
+		inRead.Fd(),
+		outWrite.Fd(),
+		0,
+		uintptr(unsafe.Pointer(&console)),
+	)
+	// The pseudo console keeps its own copies of its ends of the pipes
+	inRead.Close()
+	outWrite.Close()
+	if r != 0 {
+		return fmt.Errorf("execext: could not create a pseudo console: %w", windows.Errno(r))
+	}
+
+	// The copy of the input only stops on the next read after the command
+	// ends, since reads can't be interrupted
+	if opts.Stdin != nil {
+		go func() { _, _ = io.Copy(inWrite, opts.Stdin) }()
+	}
+	copied := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(opts.Stdout, outRead)
+		close(copied)
+	}()
+
+	err = runShell(ctx, opts, ttyExecHandler(console, opts, 15*time.Second), opts.Stdin, opts.Stdout, opts.Stderr)
+	// Closing the pseudo console ends its output, once it has been read
+	_, _, _ = procClosePseudoConsole.Call(uintptr(console))
+	<-copied
+	return err
+}
+
+// ttyExecHandler runs the programs called by commands attached to the given
+// pseudo console, and in a job object, like execHandler. Programs whose
+// input or outputs are redirected by the command run as usual instead.
+func ttyExecHandler(console windows.Handle, opts *RunCommandOptions, killTimeout time.Duration) interp.ExecHandlerFunc {
+	return func(ctx context.Context, args []string) error {
+		hc := interp.HandlerCtx(ctx)
+		if hc.Stdin != opts.Stdin || hc.Stdout != opts.Stdout || hc.Stderr != opts.Stderr {
+			return execHandler(killTimeout)(ctx, args)
+		}
+
+		path, err := interp.LookPathDir(hc.Dir, hc.Env, args[0])
+		if err != nil {
+			fmt.Fprintln(hc.Stderr, err)
+			return interp.NewExitStatus(127)
+		}
+
+		process, err := startInPseudoConsole(console, path, args, execEnv(hc.Env), hc.Dir)
+		if err != nil {
+			fmt.Fprintf(hc.Stderr, "%v\n", err)
+			return interp.NewExitStatus(127)
+		}
+		defer windows.CloseHandle(process)
+
+		// Without a job object, only the program is killed
+		job, err := newKillOnCloseJob()
+		if err == nil {
+			defer windows.CloseHandle(job)
+		}
+		assigned := err == nil && windows.AssignProcessToJobObject(job, process) == nil
+
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-ctx.Done():
+				if assigned {
+					_ = windows.TerminateJobObject(job, 1)
+				} else {
+					_ = windows.TerminateProcess(process, 1)
+				}
+			case <-done:
+			}
+		}()
+
+		if _, err := windows.WaitForSingleObject(process, windows.INFINITE); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var code uint32
+		if err := windows.GetExitCodeProcess(process, &code); err != nil {
+			return err
+		}
+		if code != 0 {
+			return interp.NewExitStatus(uint8(code))
+		}
+		return nil
+	}
+}
+
+// startInPseudoConsole starts the program attached to the given pseudo
+// console, returning a handle to its process
+func startInPseudoConsole(console windows.Handle, path string, args, env []string, dir string) (windows.Handle, error) {
+	attrs, err := windows.NewProcThreadAttributeList(1)
+	if err != nil {
+		return 0, err
+	}
+	defer attrs.Delete()
+	if r, _, err := procUpdateProcThreadAttribute.Call(
+		uintptr(unsafe.Pointer(attrs.List())),
+		0,
+		procThreadAttributePseudoConsole,
+		uintptr(console),
+		unsafe.Sizeof(console),
+		0,
+		0,
+	); r == 0 {
+		return 0, err
+	}
+
+	si := &windows.StartupInfoEx{
+		StartupInfo:             windows.StartupInfo{Cb: uint32(unsafe.Sizeof(windows.StartupInfoEx{}))},
+		ProcThreadAttributeList: attrs.List(),
+	}
+	pathp, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	cmdline, err := windows.UTF16PtrFromString(windows.ComposeCommandLine(args))
+	if err != nil {
+		return 0, err
+	}
+	dirp, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+	block, err := envBlock(env)
+	if err != nil {
+		return 0, err
+	}
+
+	var pi windows.ProcessInformation
+	if err := windows.CreateProcess(
+		pathp,
+		cmdline,
+		nil,
+		nil,
+		false,
+		windows.EXTENDED_STARTUPINFO_PRESENT|windows.CREATE_UNICODE_ENVIRONMENT,
+		block,
+		dirp,
+		&si.StartupInfo,
+		&pi,
+	); err != nil {
+		return 0, err
+	}
+	windows.CloseHandle(pi.Thread)
+	return pi.Process, nil
+}
+
+// envBlock returns the variables in the format of the environment block of
+// CreateProcess
+func envBlock(env []string) (*uint16, error) {
+	var block []uint16
+	for _, kv := range env {
+		if kv == "" {
+			continue
+		}
+		if strings.IndexByte(kv, 0) != -1 {
+			return nil, windows.ERROR_INVALID_PARAMETER
+		}
+		block = append(block, utf16.Encode([]rune(kv))...)
+		block = append(block, 0)
+	}
+	if len(block) == 0 {
+		block = append(block, 0)
+	}
+	block = append(block, 0)
+	return &block[0], nil
+}
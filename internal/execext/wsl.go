@@ -0,0 +1,61 @@
+package execext
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"mvdan.cc/sh/v3/interp"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+)
+
+// runWSLCommand runs the command with "sh" inside of the default WSL
+// distribution. The working directory is translated to its WSL equivalent
+// and only the variables not inherited from the current process (i.e. the
+// ones declared by the task) are forwarded, since the Windows ones like PATH
+// make no sense on Linux.
+func runWSLCommand(ctx context.Context, opts *RunCommandOptions) error {
+	args := []string{"--exec", "env"}
+	args = append(args, wslEnviron(opts.Env)...)
+	args = append(args, "sh", "-c", opts.Command)
+	if opts.Dir != "" {
+		dir := opts.Dir
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		args = append([]string{"--cd", filepathext.ToWSL(dir)}, args...)
+	}
+
+	cmd := exec.CommandContext(ctx, "wsl.exe", args...)
+	cmd.Stdin = opts.Stdin
+	cmd.Stdout = opts.Stdout
+	cmd.Stderr = opts.Stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return interp.NewExitStatus(uint8(exitErr.ExitCode()))
+	}
+	return err
+}
+
+// wslEnviron returns the variables of environ that aren't set to the same
+// value in the current process.
+func wslEnviron(environ []string) []string {
+	var result []string
+	for _, kv := range environ {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		if current, set := os.LookupEnv(k); set && current == v {
+			continue
+		}
+		result = append(result, kv)
+	}
+	return result
+}
@@ -0,0 +1,170 @@
+// Package fetch downloads files over HTTP, verifying their checksums,
+// resuming interrupted downloads and keeping verified files in a cache.
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/go-task/task/v3/internal/verify"
+)
+
+// Options are the options of Download
+type Options struct {
+	URL  string
+	Dest string
+	// Checksum is the expected checksum of the file. Without it, the file
+	// is always downloaded again.
+	Checksum string
+	// Executable makes the file executable
+	Executable bool
+	// CacheDir keeps the verified files, so they're not downloaded again by
+	// other tasks or after being deleted. Only files with a checksum are
+	// cached.
+	CacheDir string
+	// Client is the HTTP client used. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Download puts the file at the given URL in Dest. It returns false if
+// nothing was done, because Dest already had the expected checksum.
+func Download(ctx context.Context, opts *Options) (bool, error) {
+	var checksum *verify.Checksum
+	if opts.Checksum != "" {
+		var err error
+		if checksum, err = verify.ParseChecksum(opts.Checksum); err != nil {
+			return false, err
+		}
+		if ok, _ := checksum.Matches(opts.Dest); ok {
+			return false, chmod(opts.Dest, opts.Executable)
+		}
+	}
+
+	var cached string
+	if opts.CacheDir != "" && checksum != nil {
+		cached = filepath.Join(opts.CacheDir, checksum.Algorithm+"-"+checksum.Digest)
+		if ok, _ := checksum.Matches(cached); ok {
+			if err := copyFile(cached, opts.Dest); err != nil {
+				return false, err
+			}
+			return true, chmod(opts.Dest, opts.Executable)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(opts.Dest), 0o755); err != nil {
+		return false, err
+	}
+	part := opts.Dest + ".part"
+	if err := download(ctx, opts.Client, opts.URL, part); err != nil {
+		return false, err
+	}
+
+	if checksum != nil {
+		ok, err := checksum.Matches(part)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			// The partial file can't be resumed, so it's started over next time
+			_ = os.Remove(part)
+			return false, fmt.Errorf("fetch: checksum of %s doesn't match %s", opts.URL, opts.Checksum)
+		}
+	}
+	if err := os.Rename(part, opts.Dest); err != nil {
+		return false, err
+	}
+	if cached != "" {
+		// A missing cache is not an error, so the result is ignored
+		if err := os.MkdirAll(opts.CacheDir, 0o755); err == nil {
+			_ = copyFile(opts.Dest, cached)
+		}
+	}
+	return true, chmod(opts.Dest, opts.Executable)
+}
+
+// download writes the file at url to path. If path already has the beginning
+// of the file, from an interrupted download, only the rest is requested.
+func download(ctx context.Context, client *http.Client, url, path string) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The partial file is already complete
+		return nil
+	case resp.StatusCode == http.StatusOK:
+		flags |= os.O_TRUNC
+	default:
+		return fmt.Errorf("fetch: %s returned %s", url, resp.Status)
+	}
+
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func chmod(path string, executable bool) error {
+	if !executable {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	// Everyone who can read the file can execute it
+	mode := info.Mode().Perm()
+	return os.Chmod(path, mode|(mode&0o444)>>2)
+}
@@ -0,0 +1,98 @@
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var content = []byte("#!/bin/sh\necho hello\n")
+
+func checksum() string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func server(t *testing.T, requests *int32, ranges *int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if r.Header.Get("Range") != "" {
+			atomic.AddInt32(ranges, 1)
+		}
+		http.ServeContent(w, r, "tool", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDownload(t *testing.T) {
+	var requests, ranges int32
+	srv := server(t, &requests, &ranges)
+	dir := t.TempDir()
+	opts := &Options{
+		URL:        srv.URL,
+		Dest:       filepath.Join(dir, "bin", "tool"),
+		Checksum:   checksum(),
+		Executable: true,
+		CacheDir:   filepath.Join(dir, "cache"),
+	}
+
+	downloaded, err := Download(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.True(t, downloaded)
+	data, err := os.ReadFile(opts.Dest)
+	assert.NoError(t, err)
+	assert.Equal(t, content, data)
+	info, err := os.Stat(opts.Dest)
+	assert.NoError(t, err)
+	assert.NotZero(t, info.Mode().Perm()&0o100)
+
+	// Up to date
+	downloaded, err = Download(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.False(t, downloaded)
+
+	// Restored from the cache
+	assert.NoError(t, os.Remove(opts.Dest))
+	downloaded, err = Download(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.True(t, downloaded)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestDownloadResume(t *testing.T) {
+	var requests, ranges int32
+	srv := server(t, &requests, &ranges)
+	dest := filepath.Join(t.TempDir(), "tool")
+	assert.NoError(t, os.WriteFile(dest+".part", content[:5], 0o644))
+
+	_, err := Download(context.Background(), &Options{URL: srv.URL, Dest: dest, Checksum: checksum()})
+	assert.NoError(t, err)
+	data, err := os.ReadFile(dest)
+	assert.NoError(t, err)
+	assert.Equal(t, content, data)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&ranges))
+	assert.NoFileExists(t, dest+".part")
+}
+
+func TestDownloadChecksumMismatch(t *testing.T) {
+	var requests, ranges int32
+	srv := server(t, &requests, &ranges)
+	dest := filepath.Join(t.TempDir(), "tool")
+	sum := sha256.Sum256([]byte("something else"))
+
+	_, err := Download(context.Background(), &Options{URL: srv.URL, Dest: dest, Checksum: hex.EncodeToString(sum[:])})
+	assert.ErrorContains(t, err, "fetch: checksum of")
+	assert.NoFileExists(t, dest)
+	assert.NoFileExists(t, dest+".part")
+}
@@ -0,0 +1,36 @@
+package fileops
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopy(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	assert.NoError(t, os.MkdirAll(filepath.Join(src, "sub"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "a.txt"), []byte("a"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "sub", "run.sh"), []byte("b"), 0o755))
+
+	dst := filepath.Join(dir, "dst")
+	assert.NoError(t, Copy(src, dst))
+
+	data, err := os.ReadFile(filepath.Join(dst, "a.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "a", string(data))
+	info, err := os.Stat(filepath.Join(dst, "sub", "run.sh"))
+	assert.NoError(t, err)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+	}
+
+	// A single file
+	assert.NoError(t, Copy(filepath.Join(src, "a.txt"), filepath.Join(dir, "new", "b.txt")))
+	assert.FileExists(t, filepath.Join(dir, "new", "b.txt"))
+
+	assert.Error(t, Copy(filepath.Join(dir, "missing"), dst))
+}
@@ -3,15 +3,104 @@ package filepathext
 import (
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 )
 
 // SmartJoin joins two paths, but only if the second is not already an
 // absolute path.
 func SmartJoin(a, b string) string {
+	b = Normalize(b)
 	if filepath.IsAbs(b) {
 		return b
 	}
-	return filepath.Join(a, b)
+	return Normalize(filepath.Join(a, b))
+}
+
+// Normalize cleans the given path and converts its separators to the ones of
+// the current OS. On Windows, it also accepts Git Bash style paths like
+// "/c/foo" and makes the drive letter upper case, so the same file always
+// results in the same path regardless of the shell Task was called from.
+func Normalize(p string) string {
+	if p == "" {
+		return p
+	}
+	p = filepath.Clean(filepath.FromSlash(p))
+	if runtime.GOOS == "windows" {
+		p = normalizeWindows(p)
+	}
+	return p
+}
+
+// normalizeWindows expects a path already using backslashes as separators.
+func normalizeWindows(p string) string {
+	// Git Bash (MSYS) style: \c\foo -> C:\foo
+	if len(p) >= 2 && p[0] == '\\' && isDriveLetter(p[1]) && (len(p) == 2 || p[2] == '\\') {
+		p = p[1:2] + ":" + p[2:]
+		if len(p) == 2 {
+			p += `\`
+		}
+	}
+	if len(p) >= 2 && isDriveLetter(p[0]) && p[1] == ':' {
+		p = strings.ToUpper(p[:1]) + p[1:]
+	}
+	return p
+}
+
+func isDriveLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// maxShortPath is the length after which Windows APIs require paths to use
+// the extended-length prefix. Directories are limited to MAX_PATH minus 12
+// characters (for a 8.3 file name), so the lower limit is used for all paths.
+const maxShortPath = 260 - 12
+
+// LongPath returns a path suitable to be passed to file system operations
+// even if it is longer than MAX_PATH (260 characters), which happens often on
+// deep trees like node_modules. On Windows, long paths are made absolute and
+// receive the \\?\ extended-length prefix. On other OSes, and for short paths,
+// the path is returned unchanged. The result should not be shown to users.
+func LongPath(p string) string {
+	if runtime.GOOS != "windows" || p == "" || isExtendedLength(p) {
+		return p
+	}
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return p
+	}
+	return longPathWindows(Normalize(abs))
+}
+
+// longPathWindows expects an absolute and clean path using backslashes as
+// separators.
+func longPathWindows(p string) string {
+	switch {
+	case len(p) < maxShortPath:
+		return p
+	case isExtendedLength(p):
+		return p
+	case strings.HasPrefix(p, `\\`):
+		// UNC path: \\server\share -> \\?\UNC\server\share
+		return `\\?\UNC\` + p[2:]
+	default:
+		return `\\?\` + p
+	}
+}
+
+func isExtendedLength(p string) bool {
+	return strings.HasPrefix(p, `\\?\`) || strings.HasPrefix(p, `\\.\`)
+}
+
+// ToWSL converts a Windows path to the equivalent path inside of the Windows
+// Subsystem for Linux, e.g. C:\foo\bar becomes /mnt/c/foo/bar. Relative
+// paths only get their separators converted.
+func ToWSL(p string) string {
+	p = strings.ReplaceAll(p, `\`, "/")
+	if len(p) >= 2 && isDriveLetter(p[0]) && p[1] == ':' {
+		p = "/mnt/" + strings.ToLower(p[:1]) + strings.TrimSuffix(p[2:], "/")
+	}
+	return p
 }
 
 // TryAbsToRel tries to convert an absolute path to relative based on the
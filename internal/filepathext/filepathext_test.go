@@ -0,0 +1,81 @@
+package filepathext
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		In, Out string
+	}{
+		{"", ""},
+		{"foo", "foo"},
+		{"foo/bar/../baz", filepath.Join("foo", "baz")},
+		{"./foo//bar/", filepath.Join("foo", "bar")},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.Out, Normalize(test.In))
+	}
+}
+
+func TestNormalizeWindows(t *testing.T) {
+	tests := []struct {
+		In, Out string
+	}{
+		{`c:\foo\bar`, `C:\foo\bar`},
+		{`C:\foo\bar`, `C:\foo\bar`},
+		{`\c\foo\bar`, `C:\foo\bar`},
+		{`\c`, `C:\`},
+		{`\cache\foo`, `\cache\foo`},
+		{`foo\bar`, `foo\bar`},
+		{`\\server\share\foo`, `\\server\share\foo`},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.Out, normalizeWindows(test.In))
+	}
+}
+
+func TestLongPathWindows(t *testing.T) {
+	long := `C:\` + strings.Repeat(`node_modules\`, 20) + `index.js`
+	unc := `\\server\share\` + strings.Repeat(`node_modules\`, 20) + `index.js`
+
+	tests := []struct {
+		In, Out string
+	}{
+		{`C:\foo\bar`, `C:\foo\bar`},
+		{long, `\\?\` + long},
+		{`\\?\` + long, `\\?\` + long},
+		{unc, `\\?\UNC\` + unc[2:]},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.Out, longPathWindows(test.In))
+	}
+}
+
+func TestLongPathIsNoopOutsideWindows(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("only relevant outside of Windows")
+	}
+	p := strings.Repeat("node_modules/", 30) + "index.js"
+	assert.Equal(t, p, LongPath(p))
+}
+
+func TestToWSL(t *testing.T) {
+	tests := []struct {
+		In, Out string
+	}{
+		{`C:\Users\foo\project`, "/mnt/c/Users/foo/project"},
+		{`d:/src`, "/mnt/d/src"},
+		{`C:\`, "/mnt/c"},
+		{`foo\bar`, "foo/bar"},
+		{"/home/foo", "/home/foo"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.Out, ToWSL(test.In))
+	}
+}
@@ -1,9 +1,11 @@
 package logger
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"strconv"
+	"sync"
 
 	"github.com/fatih/color"
 )
@@ -49,8 +51,20 @@ type Logger struct {
 	Stderr  io.Writer
 	Verbose bool
 	Color   bool
+
+	// Debug receives the debug trace when set. It's kept separate from
+	// STDOUT and STDERR so it doesn't mix with the output of commands.
+	Debug io.Writer
+
+	// Redact is applied to every message printed when set, to mask the
+	// values of secrets
+	Redact func(string) string
 }
 
+// debugMutex avoids interleaving lines of the debug trace written by tasks
+// running in parallel
+var debugMutex sync.Mutex
+
 // Outf prints stuff to STDOUT.
 func (l *Logger) Outf(color Color, s string, args ...interface{}) {
 	l.FOutf(l.Stdout, color, s+"\n", args...)
@@ -58,14 +72,22 @@ func (l *Logger) Outf(color Color, s string, args ...interface{}) {
 
 // FOutf prints stuff to the given writer.
 func (l *Logger) FOutf(w io.Writer, color Color, s string, args ...interface{}) {
-	if len(args) == 0 {
-		s, args = "%s", []interface{}{s}
-	}
 	if !l.Color {
 		color = Default
 	}
 	print := color()
-	print(w, s, args...)
+	print(w, "%s", l.message(s, args...))
+}
+
+// message formats the given message, redacting it when needed
+func (l *Logger) message(s string, args ...interface{}) string {
+	if len(args) > 0 {
+		s = fmt.Sprintf(s, args...)
+	}
+	if l.Redact != nil {
+		s = l.Redact(s)
+	}
+	return s
 }
 
 // VerboseOutf prints stuff to STDOUT if verbose mode is enabled.
@@ -75,16 +97,29 @@ func (l *Logger) VerboseOutf(color Color, s string, args ...interface{}) {
 	}
 }
 
+// Debugf prints stuff to the debug writer if debugging is enabled.
+// It's safe to call on a nil Logger.
+func (l *Logger) Debugf(s string, args ...interface{}) {
+	if l == nil || l.Debug == nil {
+		return
+	}
+	debugMutex.Lock()
+	defer debugMutex.Unlock()
+	fmt.Fprint(l.Debug, l.message("task: [debug] "+s+"\n", args...))
+}
+
+// IsDebug returns true if debugging is enabled.
+func (l *Logger) IsDebug() bool {
+	return l != nil && l.Debug != nil
+}
+
 // Errf prints stuff to STDERR.
 func (l *Logger) Errf(color Color, s string, args ...interface{}) {
-	if len(args) == 0 {
-		s, args = "%s", []interface{}{s}
-	}
 	if !l.Color {
 		color = Default
 	}
 	print := color()
-	print(l.Stderr, s+"\n", args...)
+	print(l.Stderr, "%s", l.message(s, args...)+"\n")
 }
 
 // VerboseErrf prints stuff to STDERR if verbose mode is enabled.
@@ -0,0 +1,65 @@
+// Package notify sends native desktop notifications.
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// powerShellAppID is the application the notifications are shown as on
+// Windows. Toasts are only displayed for registered applications, so the one
+// of PowerShell itself is used.
+const powerShellAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// Send shows a desktop notification with the given title and message. It uses
+// osascript on macOS, notify-send on Linux and the BSDs and PowerShell on
+// Windows.
+func Send(title, message string) error {
+	name, args, err := command(runtime.GOOS, title, message)
+	if err != nil {
+		return err
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("%q is needed to send desktop notifications: %w", name, err)
+	}
+	if out, err := exec.Command(name, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// command returns the command that sends a notification on the given
+// operating system
+func command(goos, title, message string) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptQuote(message), appleScriptQuote(title))
+		return "osascript", []string{"-e", script}, nil
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return "notify-send", []string{"--app-name=Task", title, message}, nil
+	case "windows":
+		script := strings.Join([]string{
+			"[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null",
+			"$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)",
+			"$text = $xml.GetElementsByTagName('text')",
+			"$text.Item(0).AppendChild($xml.CreateTextNode(" + powerShellQuote(title) + ")) > $null",
+			"$text.Item(1).AppendChild($xml.CreateTextNode(" + powerShellQuote(message) + ")) > $null",
+			"$toast = [Windows.UI.Notifications.ToastNotification]::new($xml)",
+			"[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier(" + powerShellQuote(powerShellAppID) + ").Show($toast)",
+		}, "\n")
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", script}, nil
+	default:
+		return "", nil, fmt.Errorf("desktop notifications are not supported on %s", goos)
+	}
+}
+
+func appleScriptQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func powerShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,28 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommand(t *testing.T) {
+	name, args, err := command("darwin", "Task", `"build" finished`)
+	assert.NoError(t, err)
+	assert.Equal(t, "osascript", name)
+	assert.Equal(t, []string{"-e", `display notification "\"build\" finished" with title "Task"`}, args)
+
+	name, args, err = command("linux", "Task", `"build" finished`)
+	assert.NoError(t, err)
+	assert.Equal(t, "notify-send", name)
+	assert.Equal(t, []string{"--app-name=Task", "Task", `"build" finished`}, args)
+
+	name, args, err = command("windows", "Task", "it's done")
+	assert.NoError(t, err)
+	assert.Equal(t, "powershell", name)
+	assert.True(t, strings.Contains(args[len(args)-1], "$xml.CreateTextNode('it''s done')"))
+
+	_, _, err = command("plan9", "Task", "done")
+	assert.EqualError(t, err, "desktop notifications are not supported on plan9")
+}
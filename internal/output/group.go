@@ -3,42 +3,164 @@ package output
 import (
 	"bytes"
 	"io"
+	"os"
+	"sync"
 )
 
+// groupMemoryLimit is how much output of a command is buffered in memory.
+// Past it, the output is spilled to a temporary file until the command ends.
+const groupMemoryLimit = 1 << 20
+
 type Group struct {
 	Begin, End string
+	// Tail shows the last line of output of the running commands in place,
+	// so there's feedback while they run. It's only meant for terminals.
+	Tail bool
 }
 
-func (g Group) WrapWriter(stdOut, _ io.Writer, _ string, tmpl Templater) (io.Writer, io.Writer, CloseFunc) {
+func (g Group) WrapWriter(stdOut, _ io.Writer, prefix string, tmpl Templater) (io.Writer, io.Writer, CloseFunc) {
 	gw := &groupWriter{writer: stdOut}
+	if g.Tail {
+		gw.writer = TailSafe(stdOut)
+		gw.terminal = stdOut
+		gw.prefix = prefix
+	}
 	if g.Begin != "" {
 		gw.begin = tmpl.Replace(g.Begin) + "\n"
 	}
 	if g.End != "" {
 		gw.end = tmpl.Replace(g.End) + "\n"
 	}
-	return gw, gw, func() error { return gw.close() }
+	openGroups.add(gw)
+	return gw, gw, func() error {
+		openGroups.remove(gw)
+		return gw.close()
+	}
+}
+
+// openGroups are the group writers of the commands still running, so their
+// output can be flushed if Task is forced to exit
+var openGroups = &groupRegistry{writers: make(map[*groupWriter]struct{})}
+
+type groupRegistry struct {
+	mutex   sync.Mutex
+	writers map[*groupWriter]struct{}
+}
+
+func (r *groupRegistry) add(gw *groupWriter) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.writers[gw] = struct{}{}
 }
 
+func (r *groupRegistry) remove(gw *groupWriter) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.writers, gw)
+}
+
+// Flush writes the output buffered by the group output style for the
+// commands still running. It's meant to be called before Task exits without
+// waiting for them, so their output isn't lost.
+func Flush() {
+	openGroups.mutex.Lock()
+	defer openGroups.mutex.Unlock()
+	for gw := range openGroups.writers {
+		_ = gw.close()
+		delete(openGroups.writers, gw)
+	}
+}
+
+// groupWriter buffers the output of a command to write it at once when the
+// command ends. Stdout and stderr share it and may be written concurrently.
 type groupWriter struct {
 	writer     io.Writer
-	buff       bytes.Buffer
 	begin, end string
+	// terminal is where the tail line is shown, if it's shown, with the
+	// prefix of the task
+	terminal io.Writer
+	prefix   string
+
+	mutex sync.Mutex
+	buff  bytes.Buffer
+	// spill holds the output past groupMemoryLimit
+	spill *os.File
+	// closed is set once the output was written, after which writes go
+	// directly to the writer
+	closed bool
 }
 
 func (gw *groupWriter) Write(p []byte) (int, error) {
-	return gw.buff.Write(p)
+	gw.mutex.Lock()
+	defer gw.mutex.Unlock()
+
+	if gw.closed {
+		return gw.writer.Write(p)
+	}
+	if gw.terminal != nil {
+		if line := lastLine(p); line != "" {
+			tail.show(gw.terminal, gw.prefix, line)
+		}
+	}
+	if gw.spill != nil {
+		return gw.spill.Write(p)
+	}
+	if gw.buff.Len()+len(p) <= groupMemoryLimit {
+		return gw.buff.Write(p)
+	}
+
+	// Keep buffering in memory if the temporary file can't be created
+	f, err := os.CreateTemp("", "task-output-*")
+	if err != nil {
+		return gw.buff.Write(p)
+	}
+	if _, err := gw.buff.WriteTo(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return 0, err
+	}
+	gw.spill = f
+	return gw.spill.Write(p)
 }
 
 func (gw *groupWriter) close() error {
-	if gw.buff.Len() == 0 {
+	gw.mutex.Lock()
+	defer gw.mutex.Unlock()
+
+	if gw.closed {
+		return nil
+	}
+	gw.closed = true
+	if gw.terminal != nil {
+		tail.mutex.Lock()
+		tail.clear()
+		tail.mutex.Unlock()
+	}
+
+	if gw.spill != nil {
+		defer func() {
+			gw.spill.Close()
+			os.Remove(gw.spill.Name())
+			gw.spill = nil
+		}()
+	}
+	if gw.buff.Len() == 0 && gw.spill == nil {
 		// don't print begin/end messages if there's no buffered entries
 		return nil
 	}
 	if _, err := io.WriteString(gw.writer, gw.begin); err != nil {
 		return err
 	}
-	gw.buff.WriteString(gw.end)
-	_, err := io.Copy(gw.writer, &gw.buff)
+	if gw.spill != nil {
+		if _, err := gw.spill.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := io.Copy(gw.writer, gw.spill); err != nil {
+			return err
+		}
+	} else if _, err := gw.buff.WriteTo(gw.writer); err != nil {
+		return err
+	}
+	_, err := io.WriteString(gw.writer, gw.end)
 	return err
 }
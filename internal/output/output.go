@@ -38,6 +38,11 @@ func BuildFor(o *taskfile.Output) (Output, error) {
 			return nil, err
 		}
 		return Prefixed{}, nil
+	case "plain":
+		if err := checkOutputGroupUnset(o); err != nil {
+			return nil, err
+		}
+		return Plain{}, nil
 	default:
 		return nil, fmt.Errorf(`task: output style %q not recognized`, o.Name)
 	}
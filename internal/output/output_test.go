@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/go-task/task/v3/internal/templater"
@@ -75,6 +77,64 @@ func TestGroupWithBeginEnd(t *testing.T) {
 	})
 }
 
+func TestGroupSpill(t *testing.T) {
+	var b bytes.Buffer
+	var o output.Output = output.Group{Begin: "begin", End: "end"}
+	var stdOut, stdErr, cleanup = o.WrapWriter(&b, io.Discard, "", &templater.Templater{})
+
+	line := strings.Repeat("x", 1023) + "\n"
+	var wg sync.WaitGroup
+	for _, w := range []io.Writer{stdOut, stdErr} {
+		wg.Add(1)
+		go func(w io.Writer) {
+			defer wg.Done()
+			for i := 0; i < 1024; i++ {
+				fmt.Fprint(w, line)
+			}
+		}(w)
+	}
+	wg.Wait()
+	assert.Equal(t, "", b.String())
+
+	assert.NoError(t, cleanup())
+	assert.Equal(t, "begin\n"+strings.Repeat(line, 2048)+"end\n", b.String())
+}
+
+func TestGroupFlush(t *testing.T) {
+	var b bytes.Buffer
+	var o output.Output = output.Group{}
+	var w, _, cleanup = o.WrapWriter(&b, io.Discard, "", nil)
+
+	fmt.Fprintln(w, "foo")
+	assert.Equal(t, "", b.String())
+	output.Flush()
+	assert.Equal(t, "foo\n", b.String())
+
+	// Output written after the flush isn't grouped anymore
+	fmt.Fprintln(w, "bar")
+	assert.Equal(t, "foo\nbar\n", b.String())
+	assert.NoError(t, cleanup())
+	assert.Equal(t, "foo\nbar\n", b.String())
+}
+
+func TestGroupTail(t *testing.T) {
+	var b bytes.Buffer
+	var o output.Output = output.Group{Tail: true}
+	var w, _, cleanup = o.WrapWriter(&b, io.Discard, "build", nil)
+
+	fmt.Fprint(w, "step 1\n\x1b[1mstep 2\x1b[0m\n")
+	assert.Equal(t, "\r\x1b[K\x1b[2m[build] step 2\x1b[0m", b.String())
+
+	// Writes through TailSafe clear the tail line first
+	b.Reset()
+	fmt.Fprintln(output.TailSafe(&b), "task: [test] go test")
+	assert.Equal(t, "\r\x1b[Ktask: [test] go test\n", b.String())
+
+	b.Reset()
+	assert.NoError(t, cleanup())
+	assert.Equal(t, "step 1\n\x1b[1mstep 2\x1b[0m\n", b.String())
+}
+
 func TestPrefixed(t *testing.T) {
 	var b bytes.Buffer
 	var o output.Output = output.Prefixed{}
@@ -101,4 +161,43 @@ func TestPrefixed(t *testing.T) {
 		assert.NoError(t, cleanup())
 		assert.Equal(t, "[prefix] Test!\n", b.String())
 	})
+
+	t.Run("long line", func(t *testing.T) {
+		b.Reset()
+
+		long := strings.Repeat("x", 64<<10)
+		fmt.Fprint(w, long)
+		assert.Equal(t, "", b.String())
+		fmt.Fprint(w, "y")
+		assert.Equal(t, "[prefix] "+long+"y\n", b.String())
+		assert.NoError(t, cleanup())
+	})
+}
+
+func TestPlain(t *testing.T) {
+	var b bytes.Buffer
+	var o output.Output = output.Plain{}
+	var w, _, cleanup = o.WrapWriter(&b, io.Discard, "", nil)
+
+	t.Run("control sequences", func(t *testing.T) {
+		b.Reset()
+
+		fmt.Fprintln(w, "\x1b[1;31merror\x1b[0m\tfoo")
+		fmt.Fprintln(w, "\x1b]0;title\x07bar\r")
+		assert.Equal(t, "error\tfoo\nbar\n", b.String())
+		assert.NoError(t, cleanup())
+	})
+
+	t.Run("spinner", func(t *testing.T) {
+		b.Reset()
+
+		for _, frame := range []string{"|", "/", "-", "\\"} {
+			fmt.Fprint(w, "\r"+frame+" building")
+			assert.Equal(t, "", b.String())
+		}
+		fmt.Fprint(w, "\rdone")
+
+		assert.NoError(t, cleanup())
+		assert.Equal(t, "done\n", b.String())
+	})
 }
@@ -0,0 +1,92 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// controlSequences matches the ANSI escape sequences used for colors, cursor
+// movements and terminal titles
+var controlSequences = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// Plain writes the output of commands line by line, without colors or other
+// control sequences, so it's friendly to screen readers and to other tools
+type Plain struct{}
+
+func (Plain) WrapWriter(stdOut, stdErr io.Writer, _ string, _ Templater) (io.Writer, io.Writer, CloseFunc) {
+	ow := &plainWriter{writer: stdOut}
+	ew := &plainWriter{writer: stdErr}
+	return ow, ew, func() error {
+		if err := ow.close(); err != nil {
+			return err
+		}
+		return ew.close()
+	}
+}
+
+type plainWriter struct {
+	writer io.Writer
+	buff   bytes.Buffer
+}
+
+func (pw *plainWriter) Write(p []byte) (int, error) {
+	n, err := pw.buff.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	return n, pw.writeOutputLines(false)
+}
+
+func (pw *plainWriter) close() error {
+	return pw.writeOutputLines(true)
+}
+
+func (pw *plainWriter) writeOutputLines(force bool) error {
+	for {
+		switch line, err := pw.buff.ReadString('\n'); err {
+		case nil:
+			if err = pw.writeLine(line); err != nil {
+				return err
+			}
+		case io.EOF:
+			// if this line was not a complete line, re-add to the buffer
+			if !force && !strings.HasSuffix(line, "\n") {
+				_, err = pw.buff.WriteString(line)
+				return err
+			}
+
+			return pw.writeLine(line)
+		default:
+			return err
+		}
+	}
+}
+
+func (pw *plainWriter) writeLine(line string) error {
+	if line == "" {
+		return nil
+	}
+	_, err := io.WriteString(pw.writer, stripLine(line)+"\n")
+	return err
+}
+
+// stripLine removes the control sequences and characters of a line. Only the
+// text after the last carriage return is kept, since the text before it
+// would be overwritten on a terminal, like the frames of a spinner.
+func stripLine(line string) string {
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	line = controlSequences.ReplaceAllString(line, "")
+	if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+		line = line[i+1:]
+	}
+	return strings.Map(func(r rune) rune {
+		if r < ' ' && r != '\t' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, line)
+}
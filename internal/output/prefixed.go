@@ -5,8 +5,13 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 )
 
+// prefixedLineLimit is how long a line can be buffered while waiting for its
+// end. Longer lines are split.
+const prefixedLineLimit = 64 << 10
+
 type Prefixed struct{}
 
 func (Prefixed) WrapWriter(stdOut, _ io.Writer, prefix string, _ Templater) (io.Writer, io.Writer, CloseFunc) {
@@ -17,19 +22,29 @@ func (Prefixed) WrapWriter(stdOut, _ io.Writer, prefix string, _ Templater) (io.
 type prefixWriter struct {
 	writer io.Writer
 	prefix string
-	buff   bytes.Buffer
+
+	// mutex guards buff, since stdout and stderr share it and may be
+	// written concurrently
+	mutex sync.Mutex
+	buff  bytes.Buffer
 }
 
 func (pw *prefixWriter) Write(p []byte) (int, error) {
+	pw.mutex.Lock()
+	defer pw.mutex.Unlock()
+
 	n, err := pw.buff.Write(p)
 	if err != nil {
 		return n, err
 	}
 
-	return n, pw.writeOutputLines(false)
+	return n, pw.writeOutputLines(pw.buff.Len() > prefixedLineLimit)
 }
 
 func (pw *prefixWriter) close() error {
+	pw.mutex.Lock()
+	defer pw.mutex.Unlock()
+
 	return pw.writeOutputLines(true)
 }
 
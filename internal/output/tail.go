@@ -0,0 +1,113 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// tailRefresh is how often the tail line is redrawn at most
+const tailRefresh = 100 * time.Millisecond
+
+// tail is the line showing the last output of the running commands, under
+// the group output style on a terminal. It's shared by all the commands, so
+// only the one that wrote last is shown.
+var tail = &tailLine{}
+
+type tailLine struct {
+	mutex sync.Mutex
+	// w is the terminal the line is shown on, when it's shown
+	w     io.Writer
+	drawn time.Time
+}
+
+// show draws the given line of output of a command in place of the
+// previous one, unless it was drawn less than tailRefresh ago
+func (t *tailLine) show(w io.Writer, prefix, line string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if time.Since(t.drawn) < tailRefresh {
+		return
+	}
+	if prefix != "" {
+		line = fmt.Sprintf("[%s] %s", prefix, line)
+	}
+	line = truncateLine(line, terminalWidth(w)-1)
+	if _, err := fmt.Fprintf(w, "\r\x1b[K\x1b[2m%s\x1b[0m", line); err == nil {
+		t.w = w
+		t.drawn = time.Now()
+	}
+}
+
+// clear removes the line, if it's shown. The mutex must be held.
+func (t *tailLine) clear() {
+	if t.w == nil {
+		return
+	}
+	_, _ = io.WriteString(t.w, "\r\x1b[K")
+	t.w = nil
+	t.drawn = time.Time{}
+}
+
+// TailSafe wraps a writer to the terminal so the tail line shown by the group
+// output style is removed before anything is written to it, instead of being
+// mixed with the output
+func TailSafe(w io.Writer) io.Writer {
+	return tailSafeWriter{w}
+}
+
+type tailSafeWriter struct {
+	w io.Writer
+}
+
+func (tw tailSafeWriter) Write(p []byte) (int, error) {
+	tail.mutex.Lock()
+	defer tail.mutex.Unlock()
+	tail.clear()
+	return tw.w.Write(p)
+}
+
+// lastLine returns the last non-empty line of the given output, without the
+// text overwritten by carriage returns
+func lastLine(p []byte) string {
+	p = bytes.TrimRight(p, "\r\n")
+	if i := bytes.LastIndexByte(p, '\n'); i >= 0 {
+		p = p[i+1:]
+	}
+	if i := bytes.LastIndexByte(p, '\r'); i >= 0 {
+		p = p[i+1:]
+	}
+	return strings.TrimSpace(controlSequences.ReplaceAllString(string(p), ""))
+}
+
+// truncateLine shortens the line to the given number of characters, so it
+// doesn't wrap
+func truncateLine(line string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(line) <= width {
+		return line
+	}
+	runes := []rune(line)
+	return string(runes[:width-1]) + "…"
+}
+
+// terminalWidth returns the width of the terminal the writer writes to, or 80
+// if it can't be known
+func terminalWidth(w io.Writer) int {
+	if tw, ok := w.(tailSafeWriter); ok {
+		w = tw.w
+	}
+	if f, ok := w.(*os.File); ok {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			return width
+		}
+	}
+	return 80
+}
@@ -0,0 +1,262 @@
+// Package picker lets the user choose an item of a list on a terminal,
+// narrowing it down with fuzzy matching as they type.
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// ErrCanceled is returned when the user quits without choosing an item
+var ErrCanceled = errors.New("picker: canceled")
+
+// Item is an item of the list
+type Item struct {
+	Name     string
+	Desc     string
+	Location string
+}
+
+// Picker keeps the query typed by the user and the items matching it
+type Picker struct {
+	items    []Item
+	query    []rune
+	matches  []int
+	selected int
+}
+
+// New returns a picker of the given items, all matching the empty query
+func New(items []Item) *Picker {
+	p := &Picker{items: items}
+	p.filter()
+	return p
+}
+
+// Query returns the query typed so far
+func (p *Picker) Query() string {
+	return string(p.query)
+}
+
+// Type adds a character to the query
+func (p *Picker) Type(r rune) {
+	p.query = append(p.query, r)
+	p.filter()
+}
+
+// Backspace removes the last character of the query
+func (p *Picker) Backspace() {
+	if len(p.query) > 0 {
+		p.query = p.query[:len(p.query)-1]
+		p.filter()
+	}
+}
+
+// Clear empties the query
+func (p *Picker) Clear() {
+	p.query = nil
+	p.filter()
+}
+
+// Move moves the selection by delta matching items, wrapping around
+func (p *Picker) Move(delta int) {
+	n := len(p.matches)
+	if n == 0 {
+		return
+	}
+	p.selected = ((p.selected+delta)%n + n) % n
+}
+
+// Selected returns the selected item, if any item matches the query
+func (p *Picker) Selected() (Item, bool) {
+	if len(p.matches) == 0 {
+		return Item{}, false
+	}
+	return p.items[p.matches[p.selected]], true
+}
+
+// Matches returns the names of the items matching the query, best first
+func (p *Picker) Matches() []string {
+	names := make([]string, len(p.matches))
+	for i, m := range p.matches {
+		names[i] = p.items[m].Name
+	}
+	return names
+}
+
+func (p *Picker) filter() {
+	query := string(p.query)
+	scores := make(map[int]int, len(p.items))
+	p.matches = p.matches[:0]
+	for i, item := range p.items {
+		// The description only matches when it contains the query, since
+		// most long texts would otherwise match
+		score, ok := Match(query, item.Name)
+		if ok || strings.Contains(strings.ToLower(item.Desc), strings.ToLower(query)) {
+			scores[i] = score
+			p.matches = append(p.matches, i)
+		}
+	}
+	sort.SliceStable(p.matches, func(i, j int) bool {
+		return scores[p.matches[i]] > scores[p.matches[j]]
+	})
+	p.selected = 0
+}
+
+// Match reports whether all the characters of the query appear in s in
+// order, ignoring case, and scores how well they do. Consecutive characters
+// and characters at the start of words score higher.
+func Match(query, s string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	q := []rune(strings.ToLower(query))
+	runes := []rune(s)
+
+	score, qi, prev := 0, 0, -2
+	for i, r := range runes {
+		if qi == len(q) {
+			break
+		}
+		if unicode.ToLower(r) != q[qi] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 2
+		}
+		if i == 0 || strings.ContainsRune(":-_./ ", runes[i-1]) {
+			score += 3
+		}
+		prev = i
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score, true
+}
+
+// Render returns the picker drawn in a terminal of the given size, as lines
+func (p *Picker) Render(width, height int) []string {
+	lines := []string{
+		"Pick a task  (type to filter, up/down: select, enter: run, esc: cancel)",
+		"> " + string(p.query),
+		"",
+	}
+
+	nameWidth, descWidth := 0, 0
+	for _, m := range p.matches {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(p.items[m].Name))
+		descWidth = max(descWidth, utf8.RuneCountInString(p.items[m].Desc))
+	}
+
+	// Keep the selected item visible
+	room := max(0, height-len(lines))
+	first := 0
+	if p.selected >= room {
+		first = p.selected - room + 1
+	}
+	for i := first; i < len(p.matches) && i < first+room; i++ {
+		item := p.items[p.matches[i]]
+		cursor := " "
+		if i == p.selected {
+			cursor = ">"
+		}
+		line := fmt.Sprintf("%s %-*s  %-*s  %s", cursor, nameWidth, item.Name, descWidth, item.Desc, item.Location)
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+	if len(p.matches) == 0 {
+		lines = append(lines, "  No matching tasks")
+	}
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for i, line := range lines {
+		lines[i] = truncate(line, width)
+	}
+	return lines
+}
+
+// Run shows the picker on the terminal until the user chooses an item, which
+// is returned, or cancels. Both the given files must be terminals.
+func Run(stdin, stdout *os.File, items []Item) (Item, error) {
+	state, err := term.MakeRaw(int(stdin.Fd()))
+	if err != nil {
+		return Item{}, err
+	}
+	defer term.Restore(int(stdin.Fd()), state)
+
+	// The alternate screen keeps the scrollback of the terminal intact
+	io.WriteString(stdout, "\x1b[?1049h")
+	defer io.WriteString(stdout, "\x1b[?1049l")
+
+	p := New(items)
+	buf := make([]byte, 64)
+	for {
+		width, height, err := term.GetSize(int(stdout.Fd()))
+		if err != nil || width <= 0 || height <= 0 {
+			width, height = 80, 24
+		}
+		// Raw mode doesn't translate "\n" to "\r\n"
+		io.WriteString(stdout, "\x1b[H\x1b[2J"+strings.Join(p.Render(width, height), "\r\n"))
+		// Leave the cursor at the end of the query
+		fmt.Fprintf(stdout, "\x1b[2;%dH", 3+len(p.query))
+
+		n, err := stdin.Read(buf)
+		if err != nil {
+			return Item{}, err
+		}
+		switch key := string(buf[:n]); key {
+		case "\r", "\n":
+			if item, ok := p.Selected(); ok {
+				return item, nil
+			}
+		case "\x1b", "\x03", "\x04":
+			return Item{}, ErrCanceled
+		case "\x1b[A", "\x1bOA", "\x10":
+			p.Move(-1)
+		case "\x1b[B", "\x1bOB", "\x0e", "\t":
+			p.Move(1)
+		case "\x7f", "\b":
+			p.Backspace()
+		case "\x15":
+			p.Clear()
+		default:
+			if strings.HasPrefix(key, "\x1b") {
+				continue
+			}
+			for _, r := range key {
+				if unicode.IsPrint(r) {
+					p.Type(r)
+				}
+			}
+		}
+	}
+}
+
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width])
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
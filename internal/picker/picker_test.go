@@ -0,0 +1,80 @@
+package picker_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-task/task/v3/internal/picker"
+)
+
+func TestMatch(t *testing.T) {
+	_, ok := picker.Match("", "build")
+	assert.True(t, ok)
+
+	_, ok = picker.Match("bld", "build")
+	assert.True(t, ok)
+	_, ok = picker.Match("BLD", "build")
+	assert.True(t, ok)
+	_, ok = picker.Match("dlb", "build")
+	assert.False(t, ok)
+
+	consecutive, _ := picker.Match("abc", "abcx")
+	scattered, _ := picker.Match("abc", "axbxcx")
+	assert.Greater(t, consecutive, scattered)
+
+	wordStart, _ := picker.Match("d", "docs:deploy")
+	inside, _ := picker.Match("d", "build")
+	assert.Greater(t, wordStart, inside)
+}
+
+func TestPicker(t *testing.T) {
+	p := picker.New([]picker.Item{
+		{Name: "build", Desc: "Builds the app", Location: "Taskfile.yml:4:3"},
+		{Name: "docs:deploy", Desc: "Publishes the docs", Location: "docs/Taskfile.yml:8:3"},
+		{Name: "test", Desc: "Runs the tests", Location: "Taskfile.yml:9:3"},
+	})
+	assert.Equal(t, []string{"build", "docs:deploy", "test"}, p.Matches())
+
+	p.Type('d')
+	assert.Equal(t, []string{"docs:deploy", "build"}, p.Matches())
+	p.Type('p')
+	assert.Equal(t, []string{"docs:deploy"}, p.Matches())
+	p.Backspace()
+	p.Backspace()
+
+	// The description is searched too
+	for _, r := range "tests" {
+		p.Type(r)
+	}
+	assert.Equal(t, []string{"test"}, p.Matches())
+	p.Clear()
+
+	p.Move(-1)
+	item, ok := p.Selected()
+	assert.True(t, ok)
+	assert.Equal(t, "test", item.Name)
+
+	p.Type('x')
+	_, ok = p.Selected()
+	assert.False(t, ok)
+}
+
+func TestRender(t *testing.T) {
+	p := picker.New([]picker.Item{
+		{Name: "build", Desc: "Builds the app", Location: "Taskfile.yml:4:3"},
+		{Name: "test", Location: "Taskfile.yml:9:3"},
+	})
+	p.Move(1)
+	assert.Equal(t, []string{
+		"Pick a task  (type to filter, up/down: select, enter: run, esc: cancel)",
+		"> ",
+		"",
+		"  build  Builds the app  Taskfile.yml:4:3",
+		"> test                   Taskfile.yml:9:3",
+	}, p.Render(80, 24))
+
+	// The selected item is kept visible
+	lines := p.Render(20, 4)
+	assert.Equal(t, []string{"Pick a task  (type t", "> ", "", "> test              "}, lines)
+}
@@ -0,0 +1,122 @@
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+var (
+	// ErrNoValue is returned when the user doesn't give any value
+	ErrNoValue = errors.New("prompt: no value given")
+	// ErrNotTerminal is returned when a prompt requires an interactive
+	// terminal, but the standard input is not one
+	ErrNotTerminal = errors.New("prompt: standard input is not a terminal")
+)
+
+// Prompter asks the user for values on the terminal.
+// Questions are written to Stderr, so they don't mix with the output of
+// tasks.
+type Prompter struct {
+	Stdin  io.Reader
+	Stderr io.Writer
+
+	mu     sync.Mutex
+	reader *bufio.Reader
+}
+
+// IsTerminal returns true if the standard input is an interactive terminal
+func (p *Prompter) IsTerminal() bool {
+	f, ok := p.Stdin.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Text asks for a value, echoing what's typed
+func (p *Prompter) Text(msg string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fmt.Fprint(p.Stderr, msg)
+	return p.readLine()
+}
+
+// Password asks for a value without echoing what's typed. If the standard
+// input is not a terminal, the value is read as a line from it.
+func (p *Prompter) Password(msg string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fmt.Fprint(p.Stderr, msg)
+	if !p.IsTerminal() {
+		return p.readLine()
+	}
+
+	b, err := term.ReadPassword(int(p.Stdin.(*os.File).Fd()))
+	fmt.Fprintln(p.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if len(b) == 0 {
+		return "", ErrNoValue
+	}
+	return string(b), nil
+}
+
+// Select asks the user to choose one of the given options, either by its
+// number or by its value. It requires the standard input to be a terminal.
+func (p *Prompter) Select(msg string, options []string) (string, error) {
+	if !p.IsTerminal() {
+		return "", ErrNotTerminal
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.choose(msg, options)
+}
+
+func (p *Prompter) choose(msg string, options []string) (string, error) {
+	fmt.Fprintln(p.Stderr, strings.TrimRight(msg, " "))
+	for i, option := range options {
+		fmt.Fprintf(p.Stderr, "  %d) %s\n", i+1, option)
+	}
+
+	for {
+		fmt.Fprintf(p.Stderr, "Choose an option [1-%d]: ", len(options))
+		answer, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return options[n-1], nil
+		}
+		for _, option := range options {
+			if answer == option {
+				return option, nil
+			}
+		}
+		fmt.Fprintf(p.Stderr, "Invalid option %q\n", answer)
+	}
+}
+
+func (p *Prompter) readLine() (string, error) {
+	if p.reader == nil {
+		p.reader = bufio.NewReader(p.Stdin)
+	}
+	line, err := p.reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", ErrNoValue
+	}
+	return line, nil
+}
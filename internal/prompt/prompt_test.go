@@ -0,0 +1,44 @@
+package prompt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChoose(t *testing.T) {
+	options := []string{"staging", "prod"}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1\n", "staging"},
+		{"2\n", "prod"},
+		{"prod\n", "prod"},
+		{"3\ndev\n1\n", "staging"},
+	}
+	for _, test := range tests {
+		var buff bytes.Buffer
+		p := &Prompter{Stdin: strings.NewReader(test.input), Stderr: &buff}
+
+		result, err := p.choose("ENV: ", options)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, result)
+		assert.Contains(t, buff.String(), "ENV:\n  1) staging\n  2) prod\nChoose an option [1-2]: ")
+	}
+}
+
+func TestChooseNoValue(t *testing.T) {
+	p := &Prompter{Stdin: strings.NewReader(""), Stderr: &bytes.Buffer{}}
+	_, err := p.choose("ENV: ", []string{"staging", "prod"})
+	assert.ErrorIs(t, err, ErrNoValue)
+}
+
+func TestSelectRequiresTerminal(t *testing.T) {
+	p := &Prompter{Stdin: strings.NewReader("1\n"), Stderr: &bytes.Buffer{}}
+	_, err := p.Select("ENV: ", []string{"staging", "prod"})
+	assert.ErrorIs(t, err, ErrNotTerminal)
+}
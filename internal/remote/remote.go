@@ -0,0 +1,208 @@
+// Package remote fetches the Taskfiles included from URLs and from git
+// repositories, keeping them in a cache so they can also be read offline.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-task/task/v3/internal/fetch"
+)
+
+// commitRegexp matches full commit hashes, which never change once fetched
+var commitRegexp = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// cacheLocks serialize the fetches into the same cache path, like when the
+// same source is included under several namespaces
+var (
+	cacheLocksMutex sync.Mutex
+	cacheLocks      = make(map[string]*sync.Mutex)
+)
+
+// lockCache locks the given cache path until the returned function is called
+func lockCache(path string) func() {
+	cacheLocksMutex.Lock()
+	mutex, ok := cacheLocks[path]
+	if !ok {
+		mutex = &sync.Mutex{}
+		cacheLocks[path] = mutex
+	}
+	cacheLocksMutex.Unlock()
+
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+// Options are the options of Fetch
+type Options struct {
+	// Source is an "http://" or "https://" URL of a Taskfile, or a git
+	// repository, as "git::<url>//<path>?ref=<ref>"
+	Source string
+	// Checksum is the expected checksum of a Taskfile fetched over HTTP. With
+	// it, the cached Taskfile is used instead of being downloaded again.
+	Checksum string
+	// CacheDir keeps the fetched Taskfiles and repositories
+	CacheDir string
+	// Offline only uses what's already cached
+	Offline bool
+}
+
+// IsRemote returns true if the given include is a URL or a git repository,
+// instead of a local path
+func IsRemote(source string) bool {
+	return strings.HasPrefix(source, "https://") ||
+		strings.HasPrefix(source, "http://") ||
+		strings.HasPrefix(source, "git::")
+}
+
+// Fetch fetches the remote Taskfile into the cache, returning its local
+// path. For git repositories, it may be a directory to look for a Taskfile in.
+func Fetch(ctx context.Context, opts *Options) (string, error) {
+	if strings.HasPrefix(opts.Source, "git::") {
+		return fetchGit(ctx, opts)
+	}
+	return fetchHTTP(ctx, opts)
+}
+
+func fetchHTTP(ctx context.Context, opts *Options) (string, error) {
+	dest := filepath.Join(opts.CacheDir, "http", cacheKey(opts.Source), "Taskfile.yml")
+	defer lockCache(dest)()
+
+	if opts.Offline {
+		if _, err := os.Stat(dest); err != nil {
+			return "", notCached(opts.Source)
+		}
+		return dest, nil
+	}
+
+	if _, err := fetch.Download(ctx, &fetch.Options{
+		URL:      opts.Source,
+		Dest:     dest,
+		Checksum: opts.Checksum,
+	}); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+func fetchGit(ctx context.Context, opts *Options) (string, error) {
+	src, err := parseGitSource(opts.Source)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(opts.CacheDir, "git", cacheKey(src.url+"@"+src.ref))
+	path := filepath.Join(dir, filepath.FromSlash(src.path))
+	defer lockCache(dir)()
+
+	_, err = os.Stat(filepath.Join(dir, ".git"))
+	fetched := err == nil
+	switch {
+	case opts.Offline && !fetched:
+		return "", notCached(opts.Source)
+	case opts.Offline, fetched && commitRegexp.MatchString(src.ref):
+		return path, nil
+	}
+
+	if !fetched {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return "", err
+		}
+		if err := git(ctx, dir, "init", "--quiet"); err != nil {
+			return "", err
+		}
+	}
+	ref := src.ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if err := git(ctx, dir, "fetch", "--quiet", "--depth", "1", "--", src.url, ref); err != nil {
+		return "", err
+	}
+	if err := git(ctx, dir, "checkout", "--quiet", "--force", "FETCH_HEAD"); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// gitSource is a git repository, given as "git::<url>//<path>?ref=<ref>".
+// The path and the ref are optional.
+type gitSource struct {
+	url  string
+	path string
+	ref  string
+}
+
+func parseGitSource(source string) (*gitSource, error) {
+	s := strings.TrimPrefix(source, "git::")
+	var src gitSource
+	if i := strings.LastIndex(s, "?"); i >= 0 {
+		query, err := url.ParseQuery(s[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("remote: invalid query of %q: %w", source, err)
+		}
+		src.ref = query.Get("ref")
+		s = s[:i]
+	}
+
+	// The path starts after the "//" that doesn't belong to the scheme
+	start := 0
+	if i := strings.Index(s, "://"); i >= 0 {
+		start = i + len("://")
+	}
+	if i := strings.Index(s[start:], "//"); i >= 0 {
+		src.path = s[start+i+len("//"):]
+		s = s[:start+i]
+	}
+
+	if s == "" {
+		return nil, fmt.Errorf("remote: %q has no repository", source)
+	}
+	// They would be taken as options of git
+	if strings.HasPrefix(s, "-") {
+		return nil, fmt.Errorf("remote: invalid repository of %q", source)
+	}
+	if strings.HasPrefix(src.ref, "-") {
+		return nil, fmt.Errorf("remote: invalid ref of %q", source)
+	}
+	src.url = s
+	return &src, nil
+}
+
+func git(ctx context.Context, dir string, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return errors.New("remote: git is needed to include Taskfiles of git repositories, but it's not installed")
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("remote: git %s: %s", args[0], msg)
+	}
+	return nil
+}
+
+// cacheKey returns the name of the directory a source is cached in
+func cacheKey(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:8])
+}
+
+func notCached(source string) error {
+	return fmt.Errorf("remote: %s isn't cached, so it can't be included offline", source)
+}
@@ -0,0 +1,156 @@
+package remote
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRemote(t *testing.T) {
+	assert.True(t, IsRemote("https://example.com/Taskfile.yml"))
+	assert.True(t, IsRemote("http://example.com/Taskfile.yml"))
+	assert.True(t, IsRemote("git::https://github.com/org/repo.git//Taskfile.yml?ref=v1"))
+	assert.False(t, IsRemote("./Taskfile.common.yml"))
+	assert.False(t, IsRemote("/abs/Taskfile.yml"))
+}
+
+func TestParseGitSource(t *testing.T) {
+	tests := []struct {
+		source string
+		want   gitSource
+	}{
+		{"git::https://github.com/org/repo.git", gitSource{url: "https://github.com/org/repo.git"}},
+		{"git::https://github.com/org/repo.git//common/Taskfile.yml?ref=v1.2.0", gitSource{url: "https://github.com/org/repo.git", path: "common/Taskfile.yml", ref: "v1.2.0"}},
+		{"git::git@github.com:org/repo.git//tasks?ref=main", gitSource{url: "git@github.com:org/repo.git", path: "tasks", ref: "main"}},
+		{"git::file:///srv/repo?ref=abc", gitSource{url: "file:///srv/repo", ref: "abc"}},
+	}
+	for _, test := range tests {
+		src, err := parseGitSource(test.source)
+		assert.NoError(t, err, test.source)
+		assert.Equal(t, test.want, *src, test.source)
+	}
+
+	_, err := parseGitSource("git::?ref=main")
+	assert.EqualError(t, err, `remote: "git::?ref=main" has no repository`)
+
+	_, err = parseGitSource("git::--upload-pack=touch${IFS}pwned;git-upload-pack?ref=/srv/repo")
+	assert.EqualError(t, err, `remote: invalid repository of "git::--upload-pack=touch${IFS}pwned;git-upload-pack?ref=/srv/repo"`)
+	_, err = parseGitSource("git::https://github.com/org/repo.git?ref=--upload-pack=touch")
+	assert.EqualError(t, err, `remote: invalid ref of "git::https://github.com/org/repo.git?ref=--upload-pack=touch"`)
+}
+
+func TestFetchHTTP(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte("version: '3'\n"))
+	}))
+	defer srv.Close()
+
+	opts := &Options{
+		Source:   srv.URL + "/Taskfile.common.yml",
+		CacheDir: t.TempDir(),
+		Offline:  true,
+	}
+	_, err := Fetch(context.Background(), opts)
+	assert.ErrorContains(t, err, "isn't cached, so it can't be included offline")
+
+	opts.Offline = false
+	path, err := Fetch(context.Background(), opts)
+	assert.NoError(t, err)
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "version: '3'\n", string(b))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// With a checksum, the cached Taskfile isn't downloaded again
+	sum := sha256.Sum256([]byte("version: '3'\n"))
+	opts.Checksum = "sha256:" + hex.EncodeToString(sum[:])
+	_, err = Fetch(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	opts.Checksum = "sha256:" + strings.Repeat("0", 64)
+	_, err = Fetch(context.Background(), opts)
+	assert.ErrorContains(t, err, "doesn't match")
+
+	opts.Offline = true
+	opts.Checksum = ""
+	offline, err := Fetch(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, path, offline)
+}
+
+func TestFetchGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	gitRun := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Task", "-c", "user.email=task@example.com"}, args...)...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(out))
+	}
+	gitRun("init", "--quiet")
+	assert.NoError(t, os.MkdirAll(filepath.Join(repo, "common"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(repo, "common", "Taskfile.yml"), []byte("version: '3'\n"), 0o644))
+	gitRun("add", ".")
+	gitRun("commit", "--quiet", "-m", "Add Taskfile")
+	gitRun("tag", "v1")
+
+	opts := &Options{
+		Source:   "git::file://" + filepath.ToSlash(repo) + "//common/Taskfile.yml?ref=v1",
+		CacheDir: t.TempDir(),
+	}
+	path, err := Fetch(context.Background(), opts)
+	assert.NoError(t, err)
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "version: '3'\n", string(b))
+
+	// Fetching again updates the repository
+	assert.NoError(t, os.WriteFile(filepath.Join(repo, "common", "Taskfile.yml"), []byte("version: '3'\n\ntasks: {}\n"), 0o644))
+	gitRun("commit", "--quiet", "-am", "Add tasks")
+	gitRun("tag", "--force", "v1")
+	_, err = Fetch(context.Background(), opts)
+	assert.NoError(t, err)
+	b, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "version: '3'\n\ntasks: {}\n", string(b))
+
+	opts.Offline = true
+	offline, err := Fetch(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, path, offline)
+
+	// The same repository can be fetched concurrently, like when it's
+	// included under several namespaces
+	opts = &Options{Source: opts.Source, CacheDir: t.TempDir()}
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = Fetch(context.Background(), opts)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+}
@@ -10,17 +10,26 @@ import (
 	"strings"
 
 	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/logger"
 )
 
+// checksumAttempts is how many times sources are checksummed before giving up
+const checksumAttempts = 3
+
+// checksumRegexp matches a valid stored checksum
+var checksumRegexp = regexp.MustCompile("^[0-9a-f]{32}$")
+
 // Checksum validades if a task is up to date by calculating its source
 // files checksum
 type Checksum struct {
 	TempDir   string
+	Scope     string
 	TaskDir   string
 	Task      string
 	Sources   []string
 	Generates []string
 	Dry       bool
+	Logger    *logger.Logger
 }
 
 // IsUpToDate implements the Checker interface
@@ -31,23 +40,25 @@ func (c *Checksum) IsUpToDate() (bool, error) {
 
 	checksumFile := c.checksumFilePath()
 
-	data, _ := os.ReadFile(checksumFile)
+	data, _ := os.ReadFile(filepathext.LongPath(checksumFile))
 	oldMd5 := strings.TrimSpace(string(data))
-
-	sources, err := globs(c.TaskDir, c.Sources)
-	if err != nil {
-		return false, err
+	if oldMd5 != "" && !checksumRegexp.MatchString(oldMd5) {
+		c.Logger.Debugf("[%s] ignoring the invalid stored checksum %q", c.Task, oldMd5)
+		oldMd5 = ""
 	}
 
-	newMd5, err := c.checksum(sources...)
+	sources, newMd5, err := c.sourcesChecksum()
 	if err != nil {
+		c.Logger.VerboseErrf(logger.Yellow, "task: Could not checksum the sources of task %q: %v", c.Task, err)
 		return false, nil
 	}
+	c.Logger.Debugf("[%s] checksum of %d source files: stored %q, current %q", c.Task, len(sources), oldMd5, newMd5)
 
+	// The checksum not being saved only means it's checked again next time,
+	// so it doesn't fail the task
 	if !c.Dry {
-		_ = os.MkdirAll(filepathext.SmartJoin(c.TempDir, "checksum"), 0o755)
-		if err = os.WriteFile(checksumFile, []byte(newMd5+"\n"), 0o644); err != nil {
-			return false, err
+		if err := writeFileAtomic(checksumFile, []byte(newMd5+"\n")); err != nil {
+			c.Logger.VerboseErrf(logger.Yellow, "task: Could not save the checksum of task %q: %v", c.Task, err)
 		}
 	}
 
@@ -62,6 +73,7 @@ func (c *Checksum) IsUpToDate() (bool, error) {
 				return false, err
 			}
 			if len(generates) == 0 {
+				c.Logger.Debugf("[%s] no files generated for %q", c.Task, g)
 				return false, nil
 			}
 		}
@@ -70,6 +82,22 @@ func (c *Checksum) IsUpToDate() (bool, error) {
 	return oldMd5 == newMd5, nil
 }
 
+// sourcesChecksum returns the sources and their checksum. Sources may be
+// written or removed by other tasks running at the same time, so globbing
+// and checksumming are retried a few times before giving up.
+func (c *Checksum) sourcesChecksum() (sources []string, sum string, err error) {
+	for attempt := 0; attempt < checksumAttempts; attempt++ {
+		if sources, err = globs(c.TaskDir, c.Sources); err != nil {
+			return nil, "", err
+		}
+		if sum, err = c.checksum(sources...); err == nil {
+			return sources, sum, nil
+		}
+		c.Logger.Debugf("[%s] checksumming the sources again: %v", c.Task, err)
+	}
+	return nil, "", err
+}
+
 func (c *Checksum) checksum(files ...string) (string, error) {
 	h := md5.New()
 
@@ -78,11 +106,13 @@ func (c *Checksum) checksum(files ...string) (string, error) {
 		if _, err := io.Copy(h, strings.NewReader(filepath.Base(f))); err != nil {
 			return "", err
 		}
-		f, err := os.Open(f)
+		f, err := os.Open(filepathext.LongPath(f))
 		if err != nil {
 			return "", err
 		}
-		if _, err = io.Copy(h, f); err != nil {
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
 			return "", err
 		}
 	}
@@ -100,7 +130,7 @@ func (c *Checksum) OnError() error {
 	if len(c.Sources) == 0 {
 		return nil
 	}
-	return os.Remove(c.checksumFilePath())
+	return os.Remove(filepathext.LongPath(c.checksumFilePath()))
 }
 
 // Kind implements the Checker Interface
@@ -109,7 +139,7 @@ func (*Checksum) Kind() string {
 }
 
 func (c *Checksum) checksumFilePath() string {
-	return filepath.Join(c.TempDir, "checksum", c.normalizeFilename(c.Task))
+	return filepath.Join(c.TempDir, "checksum", c.Scope, c.normalizeFilename(c.Task))
 }
 
 var checksumFilenameRegexp = regexp.MustCompile("[^A-z0-9]")
@@ -1,9 +1,17 @@
 package status
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/go-task/task/v3/internal/logger"
 )
 
 func TestNormalizeFilename(t *testing.T) {
@@ -19,3 +27,62 @@ func TestNormalizeFilename(t *testing.T) {
 		assert.Equal(t, test.Out, (&Checksum{}).normalizeFilename(test.In))
 	}
 }
+
+func TestChecksumIgnoresInvalidStoredChecksum(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "source.txt"), []byte("source"), 0o644))
+
+	c := &Checksum{
+		TempDir: filepath.Join(dir, ".task"),
+		TaskDir: dir,
+		Task:    "build",
+		Sources: []string{"source.txt"},
+		Logger:  &logger.Logger{Stdout: io.Discard, Stderr: io.Discard},
+	}
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, ".task", "checksum"), 0o755))
+	assert.NoError(t, os.WriteFile(c.checksumFilePath(), []byte("\x00\x00garbage"), 0o644))
+
+	upToDate, err := c.IsUpToDate()
+	assert.NoError(t, err)
+	assert.False(t, upToDate)
+
+	upToDate, err = c.IsUpToDate()
+	assert.NoError(t, err)
+	assert.True(t, upToDate)
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checksum", "build")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, writeFileAtomic(path, []byte(fmt.Sprintf("%032d\n", i))))
+		}(i)
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Regexp(t, "^[0-9]{32}\n$", string(data))
+
+	// Only the file itself is left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestLockFileTakesOverStaleLocks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build.lock")
+	assert.NoError(t, os.WriteFile(path, nil, 0o644))
+	old := time.Now().Add(-2 * staleLockAge)
+	assert.NoError(t, os.Chtimes(path, old, old))
+
+	unlock, err := lockFile(path)
+	assert.NoError(t, err)
+	assert.FileExists(t, path)
+	unlock()
+	assert.NoFileExists(t, path)
+}
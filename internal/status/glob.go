@@ -38,14 +38,14 @@ func Glob(dir string, g string) ([]string, error) {
 	}
 
 	for _, f := range fs {
-		info, err := os.Stat(f)
+		info, err := os.Stat(filepathext.LongPath(f))
 		if err != nil {
 			return nil, err
 		}
 		if info.IsDir() {
 			continue
 		}
-		files = append(files, f)
+		files = append(files, filepathext.Normalize(f))
 	}
 	return files, nil
 }
@@ -0,0 +1,79 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+)
+
+const (
+	// lockTimeout is how long a lock is waited for
+	lockTimeout = 15 * time.Second
+	// staleLockAge is how old a lock has to be to be considered abandoned
+	// by a process that was killed while holding it
+	staleLockAge = 10 * time.Second
+)
+
+// lockFile takes a lock shared by the processes of Task, by creating the file
+// at path, and returns the function that releases it. It waits while another
+// process holds the lock, unless the lock is stale.
+func lockFile(path string) (func(), error) {
+	path = filepathext.LongPath(path)
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for the lock %s", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// writeFileAtomic writes data to the file at path while holding its lock.
+// The data is written to a temporary file first, so the file at path is
+// never seen partially written.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(filepathext.LongPath(dir), 0o755); err != nil {
+		return err
+	}
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.CreateTemp(filepathext.LongPath(dir), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), filepathext.LongPath(path)); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
@@ -3,14 +3,19 @@ package status
 import (
 	"os"
 	"time"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/logger"
 )
 
 // Timestamp checks if any source change compared with the generated files,
 // using file modifications timestamps.
 type Timestamp struct {
 	Dir       string
+	Task      string
 	Sources   []string
 	Generates []string
+	Logger    *logger.Logger
 }
 
 // IsUpToDate implements the Checker interface
@@ -38,6 +43,7 @@ func (t *Timestamp) IsUpToDate() (bool, error) {
 		return false, nil
 	}
 
+	t.Logger.Debugf("[%s] newest of %d source files modified at %s, oldest of %d generated files at %s", t.Task, len(sources), sourcesMaxTime.Format(time.RFC3339Nano), len(generates), generatesMinTime.Format(time.RFC3339Nano))
 	return !generatesMinTime.Before(sourcesMaxTime), nil
 }
 
@@ -67,7 +73,7 @@ func (t *Timestamp) Value() (interface{}, error) {
 func getMinTime(files ...string) (time.Time, error) {
 	var t time.Time
 	for _, f := range files {
-		info, err := os.Stat(f)
+		info, err := os.Stat(filepathext.LongPath(f))
 		if err != nil {
 			return time.Time{}, err
 		}
@@ -79,7 +85,7 @@ func getMinTime(files ...string) (time.Time, error) {
 func getMaxTime(files ...string) (time.Time, error) {
 	var t time.Time
 	for _, f := range files {
-		info, err := os.Stat(f)
+		info, err := os.Stat(filepathext.LongPath(f))
 		if err != nil {
 			return time.Time{}, err
 		}
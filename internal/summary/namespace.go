@@ -0,0 +1,69 @@
+package summary
+
+import (
+	"strings"
+
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// PrintNamespace prints the vars a Taskfile included under the given namespace
+// expects, and its tasks
+func PrintNamespace(l *logger.Logger, namespace string, params *taskfile.Params, tasks []*taskfile.Task) {
+	l.FOutf(l.Stdout, logger.Default, "namespace: ")
+	l.FOutf(l.Stdout, logger.Green, "%s\n", namespace)
+	printNamespaceParams(l, params)
+	printNamespaceTasks(l, tasks)
+}
+
+func printNamespaceParams(l *logger.Logger, params *taskfile.Params) {
+	l.Outf(logger.Default, "")
+	if params.Len() == 0 {
+		l.Outf(logger.Default, "(namespace does not declare vars)")
+		return
+	}
+
+	l.Outf(logger.Default, "vars:")
+	_ = params.Range(func(name string, p *taskfile.Param) error {
+		l.FOutf(l.Stdout, logger.Default, " - ")
+		l.FOutf(l.Stdout, logger.Cyan, "%s", name)
+		if p.Type != "" {
+			l.FOutf(l.Stdout, logger.Default, " (%s)", p.Type)
+		}
+		if p.Desc != "" {
+			l.FOutf(l.Stdout, logger.Default, ": %s", p.Desc)
+		}
+		switch {
+		case p.Required:
+			l.FOutf(l.Stdout, logger.Default, " (required)")
+		case p.Default != "":
+			l.FOutf(l.Stdout, logger.Default, " (default: %s)", p.Default)
+		}
+		if len(p.Enum) > 0 {
+			l.FOutf(l.Stdout, logger.Default, " [%s]", strings.Join(p.Enum, ", "))
+		}
+		l.FOutf(l.Stdout, logger.Default, "\n")
+		return nil
+	})
+}
+
+func printNamespaceTasks(l *logger.Logger, tasks []*taskfile.Task) {
+	if len(tasks) == 0 {
+		return
+	}
+
+	l.Outf(logger.Default, "")
+	l.Outf(logger.Default, "tasks:")
+	for _, t := range tasks {
+		l.FOutf(l.Stdout, logger.Default, " - ")
+		l.FOutf(l.Stdout, logger.Green, "%s", t.Task)
+		if t.Desc != "" {
+			l.FOutf(l.Stdout, logger.Default, ": %s", t.Desc)
+		}
+		l.FOutf(l.Stdout, logger.Default, "\n")
+		for _, key := range metadataKeys(t.Metadata) {
+			l.FOutf(l.Stdout, logger.Cyan, "     %s", key)
+			l.FOutf(l.Stdout, logger.Default, ": %s\n", t.Metadata[key])
+		}
+	}
+}
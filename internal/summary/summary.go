@@ -1,6 +1,7 @@
 package summary
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/go-task/task/v3/internal/logger"
@@ -28,7 +29,9 @@ func PrintTask(l *logger.Logger, t *taskfile.Task) {
 	printTaskName(l, t)
 	printTaskDescribingText(t, l)
 	printTaskDependencies(l, t)
+	printTaskFlags(l, t)
 	printTaskAliases(l, t)
+	printTaskMetadata(l, t)
 	printTaskCommands(l, t)
 }
 
@@ -74,6 +77,29 @@ func printTaskAliases(l *logger.Logger, t *taskfile.Task) {
 	}
 }
 
+func printTaskMetadata(l *logger.Logger, t *taskfile.Task) {
+	if len(t.Metadata) == 0 {
+		return
+	}
+	l.Outf(logger.Default, "")
+	l.Outf(logger.Default, "metadata:")
+	for _, key := range metadataKeys(t.Metadata) {
+		l.FOutf(l.Stdout, logger.Default, " - ")
+		l.FOutf(l.Stdout, logger.Cyan, "%s", key)
+		l.FOutf(l.Stdout, logger.Default, ": %s\n", t.Metadata[key])
+	}
+}
+
+// metadataKeys returns the keys of the metadata of a task, sorted
+func metadataKeys(metadata map[string]string) []string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func hasDescription(t *taskfile.Task) bool {
 	return t.Desc != ""
 }
@@ -95,8 +121,48 @@ func printTaskDependencies(l *logger.Logger, t *taskfile.Task) {
 	l.Outf(logger.Default, "dependencies:")
 
 	for _, d := range t.Deps {
-		l.Outf(logger.Default, " - %s", d.Task)
+		l.Outf(logger.Default, " - %s", depName(d))
+	}
+}
+
+// depName returns the name of the task of a dependency, or the names of the
+// tasks of a serial group, in order
+func depName(d *taskfile.Dep) string {
+	if d.Serial == nil {
+		return d.Task
+	}
+	names := make([]string, 0, len(d.Serial))
+	for _, sd := range d.Serial {
+		names = append(names, depName(sd))
+	}
+	return "(" + strings.Join(names, " then ") + ")"
+}
+
+func printTaskFlags(l *logger.Logger, t *taskfile.Task) {
+	if t.Flags.Len() == 0 {
+		return
 	}
+
+	l.Outf(logger.Default, "")
+	l.Outf(logger.Default, "flags:")
+	_ = t.Flags.Range(func(name string, f *taskfile.Flag) error {
+		l.FOutf(l.Stdout, logger.Default, " - ")
+		l.FOutf(l.Stdout, logger.Cyan, "--%s", name)
+		if f.Desc != "" {
+			l.FOutf(l.Stdout, logger.Default, ": %s", f.Desc)
+		}
+		switch {
+		case f.Required:
+			l.FOutf(l.Stdout, logger.Default, " (required)")
+		case f.Default != "":
+			l.FOutf(l.Stdout, logger.Default, " (default: %s)", f.Default)
+		}
+		if len(f.Enum) > 0 {
+			l.FOutf(l.Stdout, logger.Default, " [%s]", strings.Join(f.Enum, ", "))
+		}
+		l.FOutf(l.Stdout, logger.Default, "\n")
+		return nil
+	})
 }
 
 func printTaskCommands(l *logger.Logger, t *taskfile.Task) {
@@ -27,6 +27,20 @@ func TestPrintsDependenciesIfPresent(t *testing.T) {
 	assert.Contains(t, buffer.String(), "\ndependencies:\n - dep1\n - dep2\n - dep3\n")
 }
 
+func TestPrintsSerialDependencies(t *testing.T) {
+	buffer, l := createDummyLogger()
+	task := &taskfile.Task{
+		Deps: []*taskfile.Dep{
+			{Serial: []*taskfile.Dep{{Task: "dep1"}, {Task: "dep2"}}},
+			{Task: "dep3"},
+		},
+	}
+
+	summary.PrintTask(&l, task)
+
+	assert.Contains(t, buffer.String(), "\ndependencies:\n - (dep1 then dep2)\n - dep3\n")
+}
+
 func createDummyLogger() (*bytes.Buffer, logger.Logger) {
 	buffer := &bytes.Buffer{}
 	l := logger.Logger{
@@ -171,3 +185,34 @@ func TestPrintAllWithSpaces(t *testing.T) {
 	assert.Contains(t, buffer.String(), "\n(task does not have description or summary)\n\n\ntask: t3")
 
 }
+
+func TestPrintFlags(t *testing.T) {
+	buffer, l := createDummyLogger()
+	task := &taskfile.Task{
+		Flags: &taskfile.Flags{
+			Keys: []string{"env", "version"},
+			Mapping: map[string]*taskfile.Flag{
+				"env":     {Desc: "The environment", Default: "staging", Enum: []string{"staging", "prod"}},
+				"version": {Required: true},
+			},
+		},
+	}
+
+	summary.PrintTask(&l, task)
+
+	assert.Contains(t, buffer.String(), "\nflags:\n - --env: The environment (default: staging) [staging, prod]\n - --version (required)\n")
+}
+
+func TestPrintMetadata(t *testing.T) {
+	buffer, l := createDummyLogger()
+	task := &taskfile.Task{
+		Metadata: map[string]string{
+			"slo":   "99.9",
+			"owner": "platform-team",
+		},
+	}
+
+	summary.PrintTask(&l, task)
+
+	assert.Contains(t, buffer.String(), "\nmetadata:\n - owner: platform-team\n - slo: 99.9\n")
+}
@@ -8,6 +8,8 @@ import (
 
 	sprig "github.com/go-task/slim-sprig"
 	"mvdan.cc/sh/v3/syntax"
+
+	"github.com/go-task/task/v3/internal/filepathext"
 )
 
 var (
@@ -32,6 +34,9 @@ func init() {
 		"toSlash": func(path string) string {
 			return filepath.ToSlash(path)
 		},
+		"wslPath": func(path string) string {
+			return filepathext.ToWSL(path)
+		},
 		"exeExt": func() string {
 			if runtime.GOOS == "windows" {
 				return ".exe"
@@ -15,6 +15,9 @@ import (
 type Templater struct {
 	Vars          *taskfile.Vars
 	RemoveNoValue bool
+	// Funcs are additional template functions, which depend on the state of
+	// the run
+	Funcs template.FuncMap
 
 	cacheMap map[string]interface{}
 	err      error
@@ -29,7 +32,7 @@ func (r *Templater) Replace(str string) string {
 		return ""
 	}
 
-	templ, err := template.New("").Funcs(templateFuncs).Parse(str)
+	templ, err := template.New("").Funcs(templateFuncs).Funcs(r.Funcs).Parse(str)
 	if err != nil {
 		r.err = err
 		return ""
@@ -73,6 +76,9 @@ func (r *Templater) ReplaceVars(vars *taskfile.Vars) *taskfile.Vars {
 			Static: r.Replace(v.Static),
 			Live:   v.Live,
 			Sh:     r.Replace(v.Sh),
+			Prompt: v.Prompt,
+			Secret: v.Secret,
+			Enum:   v.Enum,
 		})
 		return nil
 	})
@@ -0,0 +1,241 @@
+package toolversions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// version is a version with up to three numeric components. Missing
+// components of partial versions, like "18" or "1.2.x", are -1.
+type version [3]int
+
+func parseVersion(s string) (version, error) {
+	v := version{-1, -1, -1}
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "="), "v")
+	// Pre-release and build metadata are ignored
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		s = s[:i]
+	}
+	if s == "" || s == "*" || s == "x" || s == "X" {
+		return v, nil
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	for i, p := range parts {
+		if p == "*" || p == "x" || p == "X" {
+			break
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+// components returns how many leading components are set
+func (v version) components() int {
+	for i, n := range v {
+		if n < 0 {
+			return i
+		}
+	}
+	return 3
+}
+
+// floor returns the lowest full version matching the partial version
+func (v version) floor() version {
+	for i := range v {
+		if v[i] < 0 {
+			v[i] = 0
+		}
+	}
+	return v
+}
+
+// next returns the lowest full version greater than the ones matching the
+// partial version, like "1.3.0" for "1.2"
+func (v version) next() version {
+	n := v.components()
+	if n == 0 {
+		return version{-1, -1, -1}
+	}
+	next := version{0, 0, 0}
+	copy(next[:n], v[:n])
+	next[n-1]++
+	return next
+}
+
+func compare(a, b version) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// comparator is a condition on a version, like ">=1.2.0"
+type comparator struct {
+	op string
+	v  version
+}
+
+func (c comparator) matches(v version) bool {
+	cmp := compare(v, c.v)
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp == 0
+	}
+}
+
+// MatchRange reports whether the given version satisfies a version range in
+// the syntax used by npm, like the "engines" of package.json: comparators
+// (">=18", "<21.0.0"), caret and tilde ranges ("^18.2", "~18.2.1"), partial
+// and wildcard versions ("18", "18.x"), hyphen ranges ("16 - 18") and the
+// alternatives between them ("16.x || >=18").
+func MatchRange(rng, v string) (bool, error) {
+	active, err := parseVersion(strings.TrimSpace(v))
+	if err != nil {
+		return false, err
+	}
+	if active.components() == 0 {
+		return false, fmt.Errorf("invalid version %q", v)
+	}
+	active = active.floor()
+
+	for _, alternative := range strings.Split(rng, "||") {
+		comparators, err := parseComparators(alternative)
+		if err != nil {
+			return false, err
+		}
+		matches := true
+		for _, c := range comparators {
+			if !c.matches(active) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func parseComparators(s string) ([]comparator, error) {
+	fields := strings.Fields(s)
+
+	// Hyphen range: "1.2 - 2.3" is ">=1.2.0 <2.4.0"
+	if len(fields) == 3 && fields[1] == "-" {
+		from, err := parseVersion(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseVersion(fields[2])
+		if err != nil {
+			return nil, err
+		}
+		comparators := []comparator{{">=", from.floor()}}
+		if to.components() == 3 {
+			comparators = append(comparators, comparator{"<=", to})
+		} else if to.components() > 0 {
+			comparators = append(comparators, comparator{"<", to.next()})
+		}
+		return comparators, nil
+	}
+
+	var comparators []comparator
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		// Allow a space between the operator and the version, like ">= 18"
+		if strings.Trim(field, "<>=~^") == "" && i+1 < len(fields) {
+			i++
+			field += fields[i]
+		}
+
+		op := field[:len(field)-len(strings.TrimLeft(field, "<>=~^"))]
+		v, err := parseVersion(field[len(op):])
+		if err != nil {
+			return nil, err
+		}
+		expanded, err := expandComparator(op, v)
+		if err != nil {
+			return nil, err
+		}
+		comparators = append(comparators, expanded...)
+	}
+	return comparators, nil
+}
+
+// expandComparator converts an operator and a possibly partial version into
+// comparators of full versions
+func expandComparator(op string, v version) ([]comparator, error) {
+	n := v.components()
+	if n == 0 {
+		// "*", "x" or "": any version
+		if op == "<" || op == ">" {
+			return []comparator{{"<", version{0, 0, 0}}}, nil
+		}
+		return nil, nil
+	}
+
+	switch op {
+	case "", "=":
+		if n == 3 {
+			return []comparator{{"=", v}}, nil
+		}
+		return []comparator{{">=", v.floor()}, {"<", v.next()}}, nil
+	case ">=":
+		return []comparator{{">=", v.floor()}}, nil
+	case ">":
+		if n == 3 {
+			return []comparator{{">", v}}, nil
+		}
+		return []comparator{{">=", v.next()}}, nil
+	case "<":
+		return []comparator{{"<", v.floor()}}, nil
+	case "<=":
+		if n == 3 {
+			return []comparator{{"<=", v}}, nil
+		}
+		return []comparator{{"<", v.next()}}, nil
+	case "~":
+		// Patch changes if the minor is given, minor changes otherwise
+		upper := version{v[0] + 1, 0, 0}
+		if n >= 2 {
+			upper = version{v[0], v[1] + 1, 0}
+		}
+		return []comparator{{">=", v.floor()}, {"<", upper}}, nil
+	case "^":
+		// Changes that don't modify the left-most non-zero component
+		var upper version
+		switch {
+		case v[0] > 0 || n == 1:
+			upper = version{v[0] + 1, 0, 0}
+		case v[1] > 0 || n == 2:
+			upper = version{0, v[1] + 1, 0}
+		default:
+			upper = version{0, 0, v[2] + 1}
+		}
+		return []comparator{{">=", v.floor()}, {"<", upper}}, nil
+	default:
+		return nil, fmt.Errorf("invalid operator %q", op)
+	}
+}
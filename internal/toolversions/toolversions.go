@@ -0,0 +1,203 @@
+// Package toolversions reads the tool versions pinned for a project by asdf
+// (".tool-versions") and mise ("mise.toml"), and checks the active versions
+// of tools against them.
+package toolversions
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+)
+
+// Tool is a tool pinned to one or more versions. Like asdf, any of the
+// versions satisfies the pin.
+type Tool struct {
+	Name     string
+	Versions []string
+	// File is the name of the file the tool was pinned in
+	File string
+}
+
+// Read returns the tools pinned in the ".tool-versions" and "mise.toml" files
+// of the given directory. Tools pinned in both are only returned once, with
+// mise taking precedence like it does when it reads both files.
+func Read(dir string) ([]Tool, error) {
+	var tools []Tool
+	index := make(map[string]int)
+	add := func(file string, parse func([]byte) ([]Tool, error)) error {
+		data, err := os.ReadFile(filepathext.SmartJoin(dir, file))
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		parsed, err := parse(data)
+		if err != nil {
+			return fmt.Errorf("task: %s: %w", file, err)
+		}
+		for _, tool := range parsed {
+			tool.File = file
+			if i, ok := index[canonicalName(tool.Name)]; ok {
+				tools[i] = tool
+				continue
+			}
+			index[canonicalName(tool.Name)] = len(tools)
+			tools = append(tools, tool)
+		}
+		return nil
+	}
+
+	if err := add(".tool-versions", parseToolVersions); err != nil {
+		return nil, err
+	}
+	if err := add("mise.toml", parseMiseToml); err != nil {
+		return nil, err
+	}
+	return tools, nil
+}
+
+// parseToolVersions parses the asdf format, where each line has the name of a
+// tool followed by its versions
+func parseToolVersions(data []byte) ([]Tool, error) {
+	var tools []Tool
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) == 1 {
+			return nil, fmt.Errorf("no version given for %q", fields[0])
+		}
+		tools = append(tools, Tool{Name: fields[0], Versions: fields[1:]})
+	}
+	return tools, scanner.Err()
+}
+
+// parseMiseToml parses the "[tools]" table of a mise.toml file. Only the
+// forms used to pin versions are supported: a string, an array of strings or
+// an inline table with a "version" key.
+func parseMiseToml(data []byte) ([]Tool, error) {
+	var (
+		tools   []Tool
+		inTools bool
+	)
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			inTools = line == "[tools]"
+			continue
+		}
+		if !inTools {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid line in [tools]: %s", line)
+		}
+		name := strings.Trim(strings.TrimSpace(key), `"'`)
+		versions, err := parseMiseVersions(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("tool %q: %w", name, err)
+		}
+		tools = append(tools, Tool{Name: name, Versions: versions})
+	}
+	return tools, scanner.Err()
+}
+
+func parseMiseVersions(value string) ([]string, error) {
+	switch {
+	case strings.HasPrefix(value, "["):
+		var versions []string
+		for _, v := range strings.Split(strings.Trim(value, "[]"), ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				versions = append(versions, unquote(v))
+			}
+		}
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("no version given")
+		}
+		return versions, nil
+	case strings.HasPrefix(value, "{"):
+		for _, pair := range strings.Split(strings.Trim(value, "{}"), ",") {
+			k, v, ok := strings.Cut(pair, "=")
+			if ok && strings.TrimSpace(k) == "version" {
+				return []string{unquote(strings.TrimSpace(v))}, nil
+			}
+		}
+		return nil, fmt.Errorf("no version given")
+	default:
+		return []string{unquote(value)}, nil
+	}
+}
+
+// stripComment removes a TOML comment from the line, ignoring the "#" inside
+// of strings
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// ShimDirs returns the existing directories of asdf and mise shims. These
+// select the pinned version of each tool, so adding them to the beginning of
+// PATH makes commands use it.
+func ShimDirs() []string {
+	var dirs []string
+	for _, dir := range []string{
+		dataDir("MISE_DATA_DIR", filepath.Join(".local", "share", "mise")),
+		dataDir("ASDF_DATA_DIR", ".asdf"),
+	} {
+		if dir == "" {
+			continue
+		}
+		shims := filepath.Join(dir, "shims")
+		if info, err := os.Stat(shims); err == nil && info.IsDir() {
+			dirs = append(dirs, shims)
+		}
+	}
+	return dirs
+}
+
+func dataDir(envName, defaultDir string) string {
+	if dir := os.Getenv(envName); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, defaultDir)
+}
@@ -0,0 +1,110 @@
+package toolversions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseToolVersions(t *testing.T) {
+	tools, err := parseToolVersions([]byte("# comment\ngolang 1.21.3\n\nnodejs 20.1.0 18.0.0 # fallback\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []Tool{
+		{Name: "golang", Versions: []string{"1.21.3"}},
+		{Name: "nodejs", Versions: []string{"20.1.0", "18.0.0"}},
+	}, tools)
+
+	_, err = parseToolVersions([]byte("golang\n"))
+	assert.EqualError(t, err, `no version given for "golang"`)
+}
+
+func TestParseMiseToml(t *testing.T) {
+	data := `
+[env]
+GO = "not a tool"
+
+[tools]
+go = "1.21" # comment
+node = ['20', "18"]
+"python" = { version = "3.11", virtualenv = ".venv" }
+ruby = "#3.2"
+
+[settings]
+experimental = true
+`
+	tools, err := parseMiseToml([]byte(data))
+	assert.NoError(t, err)
+	assert.Equal(t, []Tool{
+		{Name: "go", Versions: []string{"1.21"}},
+		{Name: "node", Versions: []string{"20", "18"}},
+		{Name: "python", Versions: []string{"3.11"}},
+		{Name: "ruby", Versions: []string{"#3.2"}},
+	}, tools)
+
+	_, err = parseMiseToml([]byte("[tools]\ngo\n"))
+	assert.EqualError(t, err, "invalid line in [tools]: go")
+}
+
+func TestMatchesAny(t *testing.T) {
+	tests := []struct {
+		Pinned   []string
+		Active   string
+		Expected bool
+	}{
+		{[]string{"1.21"}, "1.21.3", true},
+		{[]string{"1.21.3"}, "1.21.3", true},
+		{[]string{"v20"}, "20.1.0", true},
+		{[]string{"1.2"}, "1.21.3", false},
+		{[]string{"1.20", "1.21"}, "1.21.3", true},
+		{[]string{"1.22"}, "1.21.3", false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.Expected, matchesAny(test.Pinned, test.Active), "%v %s", test.Pinned, test.Active)
+	}
+}
+
+func TestMatchRange(t *testing.T) {
+	tests := []struct {
+		Range    string
+		Version  string
+		Expected bool
+	}{
+		{"", "18.0.0", true},
+		{"*", "18.0.0", true},
+		{"18", "18.17.1", true},
+		{"18", "19.0.0", false},
+		{"18.x", "18.17.1", true},
+		{"v18.17", "v18.17.1", true},
+		{"=18.17.1", "18.17.1", true},
+		{">=18", "20.0.0", true},
+		{">= 18", "16.0.0", false},
+		{">18", "18.9.9", false},
+		{">18.1.0", "18.1.1", true},
+		{"<=18", "18.9.9", true},
+		{"<18", "18.0.0", false},
+		{">=16 <18", "17.5.0", true},
+		{">=16 <18", "18.0.0", false},
+		{"^18.2", "18.9.0", true},
+		{"^18.2", "19.0.0", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"~18.2.1", "18.2.9", true},
+		{"~18.2.1", "18.3.0", false},
+		{"~18", "18.9.0", true},
+		{"16 - 18", "18.5.0", true},
+		{"16 - 18.2.0", "18.5.0", false},
+		{"14.x || >=18", "16.0.0", false},
+		{"14.x || >=18", "14.1.0", true},
+		{"14.x || >=18", "20.0.0", true},
+		{">=18.0.0-rc.1", "18.0.0", true},
+	}
+	for _, test := range tests {
+		ok, err := MatchRange(test.Range, test.Version)
+		assert.NoError(t, err, test.Range)
+		assert.Equal(t, test.Expected, ok, "%q %s", test.Range, test.Version)
+	}
+
+	_, err := MatchRange(">=eighteen", "18.0.0")
+	assert.EqualError(t, err, `invalid version "eighteen"`)
+}
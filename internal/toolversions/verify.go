@@ -0,0 +1,97 @@
+package toolversions
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// versionCommands are the commands that print the active version of the
+// known tools. Other tools are not verified, since the name of their plugin
+// doesn't necessarily match the one of their binary.
+var versionCommands = map[string][]string{
+	"go":        {"go", "env", "GOVERSION"},
+	"node":      {"node", "--version"},
+	"python":    {"python3", "--version"},
+	"ruby":      {"ruby", "--version"},
+	"deno":      {"deno", "--version"},
+	"bun":       {"bun", "--version"},
+	"terraform": {"terraform", "version"},
+	"rust":      {"rustc", "--version"},
+}
+
+// aliases are the alternative names of the known tools, like the ones of
+// their asdf plugins
+var aliases = map[string]string{
+	"golang": "go",
+	"nodejs": "node",
+}
+
+var (
+	versionRegexp       = regexp.MustCompile(`\d+(\.\d+)*`)
+	pinnedVersionRegexp = regexp.MustCompile(`^v?\d+(\.\d+)*$`)
+)
+
+func canonicalName(name string) string {
+	if alias, ok := aliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// Verify checks that the active version of each known tool, as found on
+// PATH when run in the given directory, matches one of its pinned versions. A pinned version matches
+// the versions it's a prefix of, by component, so "1.21" matches "1.21.3".
+// Pins that aren't plain versions, like "latest" or "system", are ignored.
+func Verify(ctx context.Context, dir string, tools []Tool) error {
+	for _, tool := range tools {
+		args, ok := versionCommands[canonicalName(tool.Name)]
+		if !ok || !hasPlainVersion(tool.Versions) {
+			continue
+		}
+
+		active, err := activeVersion(ctx, dir, args)
+		if err != nil {
+			return fmt.Errorf(`task: "%s" is pinned to %s in %s, but its version could not be checked: %w`, tool.Name, strings.Join(tool.Versions, " or "), tool.File, err)
+		}
+		if !matchesAny(tool.Versions, active) {
+			return fmt.Errorf(`task: "%s" is pinned to %s in %s, but version %s is active`, tool.Name, strings.Join(tool.Versions, " or "), tool.File, active)
+		}
+	}
+	return nil
+}
+
+func activeVersion(ctx context.Context, dir string, args []string) (string, error) {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	version := versionRegexp.FindString(string(out))
+	if version == "" {
+		return "", fmt.Errorf("no version found in %q", strings.TrimSpace(string(out)))
+	}
+	return version, nil
+}
+
+func hasPlainVersion(versions []string) bool {
+	for _, v := range versions {
+		if pinnedVersionRegexp.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(pinned []string, active string) bool {
+	for _, p := range pinned {
+		p = strings.TrimPrefix(p, "v")
+		if active == p || strings.HasPrefix(active, p+".") {
+			return true
+		}
+	}
+	return false
+}
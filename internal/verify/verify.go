@@ -0,0 +1,136 @@
+// Package verify checks files against checksums and signatures.
+package verify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidChecksum is returned when a checksum is not written as
+// "algorithm:hex", like "sha256:2cf24d...", or as a bare SHA-256 hex digest
+var ErrInvalidChecksum = errors.New(`verify: checksums must be written as "sha256:<hex>" or "sha512:<hex>"`)
+
+// Checksum is a parsed checksum
+type Checksum struct {
+	Algorithm string
+	Digest    string
+}
+
+// ParseChecksum parses a checksum written as "sha256:<hex>" or
+// "sha512:<hex>". A bare hex digest is a SHA-256 one.
+func ParseChecksum(s string) (*Checksum, error) {
+	algorithm, digest, ok := strings.Cut(s, ":")
+	if !ok {
+		algorithm, digest = "sha256", s
+	}
+	c := &Checksum{Algorithm: strings.ToLower(algorithm), Digest: strings.ToLower(digest)}
+	if _, err := hex.DecodeString(c.Digest); err != nil || c.Digest == "" || c.newHash() == nil {
+		return nil, ErrInvalidChecksum
+	}
+	return c, nil
+}
+
+func (c *Checksum) String() string {
+	return c.Algorithm + ":" + c.Digest
+}
+
+func (c *Checksum) newHash() hash.Hash {
+	switch c.Algorithm {
+	case "sha256":
+		return sha256.New()
+	case "sha512":
+		return sha512.New()
+	default:
+		return nil
+	}
+}
+
+// Matches reports whether the file at path has the checksum
+func (c *Checksum) Matches(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	h := c.newHash()
+	if _, err := io.Copy(h, f); err != nil {
+		return false, err
+	}
+	return hex.EncodeToString(h.Sum(nil)) == c.Digest, nil
+}
+
+// File returns an error if the file at path doesn't have the checksum
+func (c *Checksum) File(path string) error {
+	ok, err := c.Matches(path)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("verify: checksum of %s doesn't match %s", path, c)
+	}
+	return nil
+}
+
+// LookupChecksum returns the checksum of the file with the given name in a
+// checksums file, in the format written by sha256sum and sha512sum. The
+// algorithm is told by the length of the digest.
+func LookupChecksum(checksumsFile, name string) (*Checksum, error) {
+	data, err := os.ReadFile(checksumsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	name = filepath.ToSlash(name)
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		digest, file, ok := strings.Cut(strings.TrimSpace(s.Text()), " ")
+		if !ok {
+			continue
+		}
+		file = strings.TrimPrefix(strings.TrimSpace(file), "*")
+		file = strings.TrimPrefix(filepath.ToSlash(file), "./")
+		if file != name && file != filepath.Base(name) {
+			continue
+		}
+		switch len(digest) {
+		case sha256.Size * 2:
+			return ParseChecksum("sha256:" + digest)
+		case sha512.Size * 2:
+			return ParseChecksum("sha512:" + digest)
+		default:
+			return nil, ErrInvalidChecksum
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("verify: %s has no checksum for %s", checksumsFile, name)
+}
+
+// Signature verifies the signature of the file at path with the public key,
+// using cosign
+func Signature(ctx context.Context, path, signature, key string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "cosign", "verify-blob", "--key", key, "--signature", signature, path)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return errors.New("verify: cosign is needed to verify signatures, but it's not installed")
+		}
+		return fmt.Errorf("verify: signature of %s is invalid: %s", path, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
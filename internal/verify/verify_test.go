@@ -0,0 +1,65 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChecksum(t *testing.T) {
+	c, err := ParseChecksum("SHA512:ABCD")
+	assert.NoError(t, err)
+	assert.Equal(t, &Checksum{Algorithm: "sha512", Digest: "abcd"}, c)
+
+	c, err = ParseChecksum("abcd")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha256", c.Algorithm)
+
+	_, err = ParseChecksum("md5:abcd")
+	assert.ErrorIs(t, err, ErrInvalidChecksum)
+	_, err = ParseChecksum("sha256:xyz")
+	assert.ErrorIs(t, err, ErrInvalidChecksum)
+	_, err = ParseChecksum("sha256:")
+	assert.ErrorIs(t, err, ErrInvalidChecksum)
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool")
+	assert.NoError(t, os.WriteFile(path, []byte("tool"), 0o644))
+	sum := sha512.Sum512([]byte("tool"))
+
+	c, err := ParseChecksum("sha512:" + hex.EncodeToString(sum[:]))
+	assert.NoError(t, err)
+	assert.NoError(t, c.File(path))
+
+	c, err = ParseChecksum("sha256:" + hex.EncodeToString(sum[:32]))
+	assert.NoError(t, err)
+	assert.ErrorContains(t, c.File(path), "verify: checksum of "+path+" doesn't match sha256:")
+}
+
+func TestLookupChecksum(t *testing.T) {
+	dir := t.TempDir()
+	sum256 := sha256.Sum256([]byte("a"))
+	sum512 := sha512.Sum512([]byte("b"))
+	sums := filepath.Join(dir, "checksums.txt")
+	assert.NoError(t, os.WriteFile(sums, []byte(
+		hex.EncodeToString(sum256[:])+"  tool-linux.tar.gz\n"+
+			hex.EncodeToString(sum512[:])+" *./dist/tool-darwin.tar.gz\n",
+	), 0o644))
+
+	c, err := LookupChecksum(sums, "bin/tool-linux.tar.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha256:"+hex.EncodeToString(sum256[:]), c.String())
+
+	c, err = LookupChecksum(sums, "dist/tool-darwin.tar.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha512", c.Algorithm)
+
+	_, err = LookupChecksum(sums, "tool-windows.zip")
+	assert.EqualError(t, err, "verify: "+sums+" has no checksum for tool-windows.zip")
+}
@@ -0,0 +1,24 @@
+package task
+
+import (
+	"strings"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// brokenInclude returns the included Taskfile skipped by Lenient the given
+// task would belong to, if any
+func (e *Executor) brokenInclude(taskName string) *taskfile.BrokenInclude {
+	for _, broken := range e.Taskfile.BrokenIncludes {
+		if taskName == broken.Namespace || strings.HasPrefix(taskName, broken.Namespace+taskfile.NamespaceSeparator) {
+			return broken
+		}
+	}
+	return nil
+}
+
+// invalidReason returns an error message to show on a single line, next to
+// an invalid task when listing tasks
+func invalidReason(msg string) string {
+	return strings.Join(strings.Fields(strings.TrimPrefix(msg, "task: ")), " ")
+}
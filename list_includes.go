@@ -0,0 +1,72 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// ListIncludes prints the tree of the Taskfiles that were read, starting at
+// the entrypoint, with the namespace, the path and the version of each
+// include, where the remote and packaged ones came from, and the includes
+// that were skipped.
+func (e *Executor) ListIncludes() error {
+	tree := e.Taskfile.IncludeTree
+	if tree == nil {
+		return fmt.Errorf("task: The includes of the Taskfile are unknown")
+	}
+	fmt.Fprintln(e.Stdout, filepathext.TryAbsToRel(tree.Taskfile)+includeDetails(tree))
+	printIncludes(e.Stdout, tree.Includes, "", e.ASCII)
+	return nil
+}
+
+func printIncludes(w io.Writer, nodes []*taskfile.IncludeNode, indent string, ascii bool) {
+	branch, last, pipe := "├── ", "└── ", "│   "
+	if ascii {
+		branch, last, pipe = "|-- ", "`-- ", "|   "
+	}
+	for i, node := range nodes {
+		prefix, childIndent := branch, indent+pipe
+		if i == len(nodes)-1 {
+			prefix, childIndent = last, indent+"    "
+		}
+		path := node.Taskfile
+		if node.Origin != "" && !node.Skipped {
+			path = node.Origin
+		}
+		line := indent + prefix + node.Namespace
+		if path != "" {
+			line += ": " + filepathext.TryAbsToRel(path)
+		}
+		fmt.Fprintln(w, line+includeDetails(node))
+		printIncludes(w, node.Includes, childIndent, ascii)
+	}
+}
+
+// includeDetails returns what is shown after the path of an include
+func includeDetails(node *taskfile.IncludeNode) string {
+	var details []string
+	if node.Version != "" {
+		details = append(details, "version "+node.Version)
+	}
+	if node.Optional {
+		details = append(details, "optional")
+	}
+	if node.Origin != "" && !node.Skipped {
+		details = append(details, "read from "+filepathext.TryAbsToRel(node.Taskfile))
+	}
+	if node.Skipped {
+		if node.Err != "" {
+			details = append(details, "skipped: "+invalidReason(node.Err))
+		} else {
+			details = append(details, "skipped")
+		}
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(details, ", ") + ")"
+}
@@ -0,0 +1,85 @@
+package task
+
+import (
+	"encoding/json"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// taskList is the list of tasks printed by "--list --json"
+type taskList struct {
+	Tasks []*listedTask `json:"tasks"`
+}
+
+type listedTask struct {
+	Name     string            `json:"name"`
+	Desc     string            `json:"desc"`
+	Summary  string            `json:"summary"`
+	Aliases  []string          `json:"aliases"`
+	Tags     []string          `json:"tags"`
+	Metadata map[string]string `json:"metadata"`
+	Deps     []string          `json:"deps"`
+	Sources  []string          `json:"sources"`
+	Location *listedLocation   `json:"location"`
+}
+
+type listedLocation struct {
+	Taskfile string `json:"taskfile"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+// ListTasksJSON prints the tasks like ListTasks does, but as a JSON document,
+// including the metadata of each task, so other tools can consume them.
+// Tasks that match the given filters are excluded from the list.
+func (e *Executor) ListTasksJSON(filters ...FilterFunc) error {
+	l := &taskList{Tasks: []*listedTask{}}
+	_ = e.RangeTaskList(func(t *taskfile.Task) error {
+		lt := &listedTask{
+			Name:     t.Task,
+			Desc:     t.Desc,
+			Summary:  t.Summary,
+			Aliases:  t.Aliases,
+			Tags:     t.Tags,
+			Metadata: t.Metadata,
+			Deps:     listedDeps(t.Deps),
+			Sources:  t.Sources,
+			Location: &listedLocation{
+				Taskfile: t.Location.Taskfile,
+				Line:     t.Location.Line,
+				Column:   t.Location.Column,
+			},
+		}
+		if lt.Aliases == nil {
+			lt.Aliases = []string{}
+		}
+		if lt.Tags == nil {
+			lt.Tags = []string{}
+		}
+		if lt.Metadata == nil {
+			lt.Metadata = map[string]string{}
+		}
+		if lt.Sources == nil {
+			lt.Sources = []string{}
+		}
+		l.Tasks = append(l.Tasks, lt)
+		return nil
+	}, filters...)
+
+	enc := json.NewEncoder(e.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(l)
+}
+
+// listedDeps returns the names of the tasks a task depends on, including the
+// ones of its serial groups, as they're written in the Taskfile
+func listedDeps(deps []*taskfile.Dep) []string {
+	names := []string{}
+	for _, d := range deps {
+		if d.Task != "" {
+			names = append(names, d.Task)
+		}
+		names = append(names, listedDeps(d.Serial)...)
+	}
+	return names
+}
@@ -0,0 +1,74 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// matrixCalls returns a call for each instance of the given matrix task,
+// which set the values of the matrix vars that the call doesn't set itself.
+// It returns nil for the other tasks, and for the calls of an instance. It
+// also returns whether the instances run serially.
+func (e *Executor) matrixCalls(call taskfile.Call) ([]taskfile.Call, bool, error) {
+	t, err := e.GetTask(call)
+	if err != nil {
+		return nil, false, err
+	}
+	if t.Matrix == nil {
+		return nil, false, nil
+	}
+	combinations := t.Matrix.Combinations(call.Vars)
+	if len(combinations) == 1 && combinations[0].Len() == 0 {
+		return nil, false, nil
+	}
+
+	calls := make([]taskfile.Call, 0, len(combinations))
+	for _, vars := range combinations {
+		c := taskfile.Call{Task: call.Task, Vars: &taskfile.Vars{}}
+		c.Vars.Merge(call.Vars)
+		c.Vars.Merge(vars)
+		calls = append(calls, c)
+	}
+	return calls, t.Matrix.Serial, nil
+}
+
+// runMatrixTask runs the given instances of a matrix task, in parallel unless
+// serial. The concurrency limit is still respected, since it's acquired by
+// each instance.
+func (e *Executor) runMatrixTask(ctx context.Context, calls []taskfile.Call, serial, isDep bool) error {
+	if serial {
+		for _, c := range calls {
+			if err := e.runTask(ctx, c, isDep); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	for _, c := range calls {
+		c := c
+		g.Go(func() error { return e.runTask(ctx, c, isDep) })
+	}
+	return g.Wait()
+}
+
+// matrixLabel returns the name of an instance of a matrix task, like
+// "build (OS=linux, ARCH=amd64)", or an empty string if the given vars don't
+// set all the matrix vars
+func matrixLabel(t *taskfile.Task, vars *taskfile.Vars) string {
+	values := make([]string, 0, len(t.Matrix.Keys))
+	for _, key := range t.Matrix.Keys {
+		v, ok := vars.Mapping[key]
+		if !ok {
+			return ""
+		}
+		values = append(values, fmt.Sprintf("%s=%s", key, v.Static))
+	}
+	return fmt.Sprintf("%s (%s)", t.Task, strings.Join(values, ", "))
+}
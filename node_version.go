@@ -0,0 +1,66 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/execext"
+	"github.com/go-task/task/v3/internal/toolversions"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// checkNodeVersions fails if the active Node.js version, as found by the
+// commands of the task, doesn't satisfy the versions required by it
+func (e *Executor) checkNodeVersions(ctx context.Context, t *taskfile.Task) error {
+	if len(t.NodeVersions) == 0 || e.Dry {
+		return nil
+	}
+
+	const command = "node --version"
+	if err := e.checkPolicy(ctx, t, &PolicyCommand{Kind: "node_version", Cmd: command}); err != nil {
+		return err
+	}
+
+	var stdout bytes.Buffer
+	err := execext.RunCommand(ctx, &execext.RunCommandOptions{
+		Command: command,
+		Dir:     t.Dir,
+		Env:     e.getEnviron(t),
+		Stdout:  &stdout,
+		Stderr:  &stdout,
+	})
+	if err != nil {
+		return fmt.Errorf(`task: Node.js "%s" is required by %s, but it could not be run: %w`, t.NodeVersions[0].Range, t.NodeVersions[0].Source, err)
+	}
+	active := strings.TrimSpace(stdout.String())
+
+	for _, required := range t.NodeVersions {
+		ok, err := toolversions.MatchRange(required.Range, active)
+		if err != nil {
+			return fmt.Errorf(`task: Invalid Node.js version range "%s" in %s: %w`, required.Range, required.Source, err)
+		}
+		if !ok {
+			return fmt.Errorf(`task: Node.js "%s" is required by %s, but version %s is active%s`, required.Range, required.Source, active, nodeVersionManagerHint(required))
+		}
+	}
+	return nil
+}
+
+// nodeVersionManagerHint suggests how to switch to the version of .nvmrc when
+// a version manager that reads it is installed
+func nodeVersionManagerHint(required taskfile.NodeVersion) string {
+	if required.Source != ".nvmrc" {
+		return ""
+	}
+	if _, err := exec.LookPath("fnm"); err == nil {
+		return `. Run "fnm use" to switch to it`
+	}
+	if os.Getenv("NVM_DIR") != "" {
+		return `. Run "nvm use" to switch to it`
+	}
+	return ""
+}
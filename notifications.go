@@ -0,0 +1,137 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// webhookTimeout is how long a notification webhook can take to respond
+const webhookTimeout = 10 * time.Second
+
+// runSummary is the default payload of generic notification webhooks
+type runSummary struct {
+	Tasks    []string `json:"tasks"`
+	Status   string   `json:"status"`
+	Duration string   `json:"duration"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// sendWebhooks calls the webhooks of the "notifications" of the Taskfile
+// that are triggered by the result of the run. Failures are only reported,
+// since the run itself is already over.
+func (e *Executor) sendWebhooks(calls []taskfile.Call, start time.Time, runErr error) {
+	summary := runSummary{
+		Tasks:    make([]string, 0, len(calls)),
+		Status:   "success",
+		Duration: formatReportDuration(time.Since(start)),
+	}
+	for _, c := range calls {
+		summary.Tasks = append(summary.Tasks, c.Task)
+	}
+	if runErr != nil {
+		summary.Status = "failure"
+		summary.Error = e.redact(runErr.Error())
+	}
+
+	for _, n := range e.Taskfile.Notifications {
+		if !n.Triggers(runErr != nil) {
+			continue
+		}
+		if err := e.sendWebhook(n, summary); err != nil {
+			e.Logger.Errf(logger.Yellow, "task: Failed to send notification: %v", err)
+		}
+	}
+}
+
+func (e *Executor) sendWebhook(n *taskfile.Notification, summary runSummary) error {
+	vars, err := e.Compiler.GetTaskfileVariables()
+	if err != nil {
+		return err
+	}
+	vars.Set("TASKS", taskfile.Var{Static: strings.Join(summary.Tasks, " ")})
+	vars.Set("STATUS", taskfile.Var{Static: summary.Status})
+	vars.Set("DURATION", taskfile.Var{Static: summary.Duration})
+	vars.Set("ERROR", taskfile.Var{Static: summary.Error})
+	r := templater.Templater{Vars: vars, RemoveNoValue: true}
+
+	target := r.Replace(n.URL)
+	payload := r.Replace(n.Payload)
+	if n.Slack != "" {
+		target = r.Replace(n.Slack)
+		if payload == "" {
+			payload, err = slackPayload(summary)
+		}
+	} else if payload == "" {
+		payload, err = jsonPayload(summary)
+	}
+	if err != nil {
+		return err
+	}
+	headers := make(map[string]string, len(n.Headers))
+	for k, v := range n.Headers {
+		headers[k] = r.Replace(v)
+	}
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	// The URLs of webhooks usually work as credentials, so only the host is
+	// ever printed
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL")
+	}
+	e.Logger.VerboseErrf(logger.Magenta, "task: Sending notification to %s", u.Host)
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("%s: %w", u.Host, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// The error includes the whole URL
+		return fmt.Errorf("%s: request failed", u.Host)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s: %s", u.Host, resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+func jsonPayload(summary runSummary) (string, error) {
+	payload, err := json.Marshal(summary)
+	return string(payload), err
+}
+
+func slackPayload(summary runSummary) (string, error) {
+	names := make([]string, 0, len(summary.Tasks))
+	for _, name := range summary.Tasks {
+		names = append(names, fmt.Sprintf("%q", name))
+	}
+	text := fmt.Sprintf(":white_check_mark: %s finished in %s", strings.Join(names, ", "), summary.Duration)
+	if summary.Status == "failure" {
+		text = fmt.Sprintf(":x: %s failed after %s:\n```\n%s\n```", strings.Join(names, ", "), summary.Duration, summary.Error)
+	}
+	payload, err := json.Marshal(map[string]string{"text": text})
+	return string(payload), err
+}
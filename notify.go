@@ -0,0 +1,54 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/notify"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// notifyRun sends the notifications of a finished run: a desktop one with
+// Notify and the webhooks of the Taskfile
+func (e *Executor) notifyRun(calls []taskfile.Call, start time.Time, err error) {
+	e.sendWebhooks(calls, start, err)
+	if !e.Notify {
+		return
+	}
+
+	names := make([]string, 0, len(calls))
+	for _, c := range calls {
+		names = append(names, fmt.Sprintf("%q", c.Task))
+	}
+	e.sendNotification(e.redact(strings.Join(names, ", ")), start, err)
+}
+
+// notifyTask sends a desktop notification when a task with "notify: true"
+// finishes. It's skipped for tasks that were up to date and when the whole
+// run already notifies.
+func (e *Executor) notifyTask(t *taskfile.Task, status reportStatus, start time.Time, err error) {
+	if !t.Notify || e.Notify || e.Dry || status == reportUpToDate {
+		return
+	}
+	e.sendNotification(fmt.Sprintf("%q", e.redact(t.Name())), start, err)
+}
+
+func (e *Executor) sendNotification(subject string, start time.Time, err error) {
+	title := "Task"
+	message := fmt.Sprintf("%s finished in %s", subject, formatReportDuration(time.Since(start)))
+	if err != nil {
+		title = "Task failed"
+		message = fmt.Sprintf("%s failed after %s: %s", subject, formatReportDuration(time.Since(start)), e.redact(err.Error()))
+	}
+
+	send := e.Notifier
+	if send == nil {
+		send = notify.Send
+	}
+	// A notification that couldn't be sent shouldn't fail the run
+	if err := send(title, message); err != nil {
+		e.Logger.VerboseErrf(logger.Yellow, "task: Failed to send desktop notification: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package task
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// receiptFilenameRegexp matches the characters of task names replaced to
+// make them file names
+var receiptFilenameRegexp = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// receipt returns the receipt a task run once per some scopes leaves after
+// succeeding, which tells the scopes it ran in, like the day. It's empty for
+// the other tasks.
+func (e *Executor) receipt(ctx context.Context, t *taskfile.Task) (string, error) {
+	var lines []string
+	for _, scope := range t.OncePer {
+		var value string
+		switch scope {
+		case taskfile.OncePerDay:
+			value = time.Now().Format("2006-01-02")
+		case taskfile.OncePerCommit:
+			cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+			cmd.Dir = t.Dir
+			out, err := cmd.Output()
+			if err != nil {
+				return "", &TaskRunError{t.Task, fmt.Errorf(`task: Could not get the git commit of "once_per": %w`, err)}
+			}
+			value = strings.TrimSpace(string(out))
+		case taskfile.OncePerVars:
+			value = t.VarsHash
+		}
+		lines = append(lines, scope+"="+value)
+	}
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// hasReceipt returns true if the task already succeeded in the scopes of the
+// given receipt
+func (e *Executor) hasReceipt(t *taskfile.Task, receipt string) bool {
+	b, err := os.ReadFile(e.receiptPath(t))
+	return err == nil && string(b) == receipt
+}
+
+// writeReceipt records that the task succeeded in the scopes of the given
+// receipt, replacing the receipt of the previous run
+func (e *Executor) writeReceipt(t *taskfile.Task, receipt string) error {
+	path := e.receiptPath(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(receipt), 0o644)
+}
+
+func (e *Executor) receiptPath(t *taskfile.Task) string {
+	return filepath.Join(e.TempDir, "receipt", receiptFilenameRegexp.ReplaceAllString(t.Task, "-"))
+}
+
+// varsHash returns a hash of the values of the given variables, leaving out
+// the ones set by Task itself
+func varsHash(vars *taskfile.Vars) string {
+	special := make(map[string]bool, len(specialVars))
+	for _, name := range specialVars {
+		special[name] = true
+	}
+
+	h := sha256.New()
+	_ = vars.Range(func(key string, v taskfile.Var) error {
+		if special[key] {
+			return nil
+		}
+		value := v.Static
+		if v.Live != nil {
+			value = fmt.Sprint(v.Live)
+		}
+		fmt.Fprintf(h, "%s=%s\x00", key, value)
+		return nil
+	})
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,102 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/template"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// outputFuncs returns the "output" template function, which returns the
+// path of a file output or the value of a value output of another task. The
+// outputs used are appended to inputs, so it can be checked that their tasks
+// ran before.
+func (e *Executor) outputFuncs(namespace string, inputs *[]taskfile.Input) template.FuncMap {
+	return template.FuncMap{
+		"output": func(task, name string) (string, error) {
+			producer, err := e.CompiledTask(taskfile.Call{Task: taskfile.ResolveReference(task, namespace)})
+			if err != nil {
+				return "", err
+			}
+			output, ok := producer.Outputs[name]
+			if !ok {
+				return "", fmt.Errorf(`task: Task "%s" has no output "%s"`, producer.Task, name)
+			}
+
+			*inputs = append(*inputs, taskfile.Input{Task: producer.Task, Output: name})
+			if output.File != "" {
+				return output.File, nil
+			}
+			return output.Value, nil
+		},
+	}
+}
+
+// checkInputs checks that the tasks whose outputs are used by the given one
+// ran before it, and that the files they output exist
+func (e *Executor) checkInputs(t *taskfile.Task) error {
+	for _, input := range t.Inputs {
+		if !e.isProduced(input.Task) {
+			return fmt.Errorf(`task: Task "%s" uses the output "%s" of task "%s", which didn't run before it. Add "%s" to its deps`, t.Name(), input.Output, input.Task, input.Task)
+		}
+	}
+	if e.Dry {
+		return nil
+	}
+
+	for _, input := range t.Inputs {
+		producer, err := e.CompiledTask(taskfile.Call{Task: input.Task})
+		if err != nil {
+			return err
+		}
+		if file := producer.Outputs[input.Output].File; file != "" {
+			if _, err := os.Stat(filepathext.LongPath(file)); err != nil {
+				return fmt.Errorf(`task: Task "%s" uses the output "%s" of task "%s", but %s doesn't exist`, t.Name(), input.Output, input.Task, file)
+			}
+		}
+	}
+	return nil
+}
+
+// checkOutputs checks that a task created its file outputs
+func (e *Executor) checkOutputs(t *taskfile.Task) error {
+	if e.Dry {
+		return nil
+	}
+	names := make([]string, 0, len(t.Outputs))
+	for name := range t.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		output := t.Outputs[name]
+		if output.File == "" {
+			continue
+		}
+		if _, err := os.Stat(filepathext.LongPath(output.File)); err != nil {
+			return fmt.Errorf(`task: Output "%s" was not created: %s`, name, output.File)
+		}
+	}
+	return nil
+}
+
+// markProduced records that the outputs of the given task can be used
+func (e *Executor) markProduced(t *taskfile.Task) {
+	e.producedTasksMutex.Lock()
+	defer e.producedTasksMutex.Unlock()
+
+	if e.producedTasks == nil {
+		e.producedTasks = make(map[string]bool)
+	}
+	e.producedTasks[t.Task] = true
+}
+
+func (e *Executor) isProduced(task string) bool {
+	e.producedTasksMutex.Lock()
+	defer e.producedTasksMutex.Unlock()
+
+	return e.producedTasks[task]
+}
@@ -0,0 +1,154 @@
+package task
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/codeowners"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// OwnerMetadataKey is the key of the metadata of a task naming its owners,
+// separated by commas or spaces
+const OwnerMetadataKey = "owner"
+
+// taskOwners returns the owners of a task: the ones given by its metadata or,
+// when it has none, the ones the CODEOWNERS file of the repository assigns to
+// its Taskfile
+func (e *Executor) taskOwners(t *taskfile.Task) []string {
+	if owners := strings.FieldsFunc(t.Metadata[OwnerMetadataKey], func(r rune) bool {
+		return r == ',' || r == ' '
+	}); len(owners) > 0 {
+		return owners
+	}
+
+	e.codeownersOnce.Do(e.readCodeowners)
+	if e.codeowners == nil {
+		return nil
+	}
+	rel, ok := e.repositoryPath(t.Taskfile)
+	if !ok {
+		return nil
+	}
+	return e.codeowners.Owners(rel)
+}
+
+// repositoryPath returns the path of a file relative to the root of the
+// repository, with forward slashes, or false if it's outside of it
+func (e *Executor) repositoryPath(path string) (string, bool) {
+	if path == "" {
+		return "", false
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	rel, err := filepath.Rel(e.repositoryRoot, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// readCodeowners reads the CODEOWNERS file of the git repository of the
+// Taskfile. Outside of a repository, it's looked for in the directory of the
+// Taskfile.
+func (e *Executor) readCodeowners() {
+	e.repositoryRoot = e.Dir
+	// The git config of an untrusted repository could run commands, so
+	// CODEOWNERS is only looked for in the directory of the Taskfile
+	if !e.Inspect {
+		if root, err := gitOutput(e.Dir, "rev-parse", "--show-toplevel"); err == nil {
+			e.repositoryRoot = filepath.Clean(root)
+		}
+	}
+	if resolved, err := filepath.EvalSymlinks(e.repositoryRoot); err == nil {
+		e.repositoryRoot = resolved
+	}
+
+	f, err := codeowners.Find(e.repositoryRoot)
+	if err != nil {
+		e.Logger.Errf(logger.Yellow, "task: Ignoring CODEOWNERS: %v", err)
+		return
+	}
+	e.codeowners = f
+}
+
+// FilterOutNotOwnedBy removes all tasks owned by none of the given owners.
+// The "@" of owners is optional, and they're compared ignoring case.
+func (e *Executor) FilterOutNotOwnedBy(owners ...string) FilterFunc {
+	return Filter(func(t *taskfile.Task) bool {
+		for _, owner := range e.taskOwners(t) {
+			for _, wanted := range owners {
+				if sameOwner(owner, wanted) {
+					return false
+				}
+			}
+		}
+		return true
+	})
+}
+
+func sameOwner(a, b string) bool {
+	return strings.EqualFold(strings.TrimPrefix(a, "@"), strings.TrimPrefix(b, "@"))
+}
+
+// CheckOwners warns about the tasks without an owner defined in the
+// Taskfiles changed since the given git revision, including the Taskfiles
+// not committed yet.
+func (e *Executor) CheckOwners(base string) error {
+	if err := e.checkNotInspecting("check the owners of changed Taskfiles"); err != nil {
+		return err
+	}
+	e.codeownersOnce.Do(e.readCodeowners)
+
+	changed := make(map[string]bool)
+	for _, args := range [][]string{
+		{"diff", "--name-only", base, "--"},
+		{"ls-files", "--others", "--exclude-standard"},
+	} {
+		out, err := gitOutput(e.repositoryRoot, args...)
+		if err != nil {
+			return fmt.Errorf("task: Could not list the files changed since %q: %w", base, err)
+		}
+		for _, file := range strings.Split(out, "\n") {
+			if file != "" {
+				changed[file] = true
+			}
+		}
+	}
+
+	var unowned []string
+	for _, t := range e.Taskfile.Tasks {
+		rel, ok := e.repositoryPath(t.Taskfile)
+		if !t.Internal && ok && changed[rel] && len(e.taskOwners(t)) == 0 {
+			unowned = append(unowned, fmt.Sprintf(`task: Task "%s" of the changed Taskfile %s has no owner`, t.Task, rel))
+		}
+	}
+	sort.Strings(unowned)
+
+	for _, warning := range unowned {
+		e.Logger.Errf(logger.Yellow, "%s", warning)
+	}
+	if len(unowned) == 0 {
+		e.Logger.VerboseErrf(logger.Green, "task: All the changed tasks have an owner")
+	}
+	return nil
+}
+
+// gitOutput runs git in the given directory, and returns its output
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
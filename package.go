@@ -0,0 +1,18 @@
+package task
+
+import (
+	"github.com/go-task/task/v3/internal/bundle"
+	"github.com/go-task/task/v3/internal/logger"
+)
+
+// Package bundles the Taskfile, the Taskfiles it includes and the files its
+// commands reference into an archive at dest, which other Taskfiles can
+// include
+func (e *Executor) Package(dest string) error {
+	m, err := bundle.Create(e.Dir, e.Entrypoint, dest)
+	if err != nil {
+		return err
+	}
+	e.Logger.Errf(logger.Green, "task: Packaged %q with %d files into %s", m.Name, len(m.Files), dest)
+	return nil
+}
@@ -0,0 +1,53 @@
+package task
+
+import (
+	"errors"
+	"os"
+
+	"github.com/go-task/task/v3/internal/picker"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+var (
+	// ErrPickNotTerminal is returned when picking a task without a terminal
+	ErrPickNotTerminal = errors.New("task: Picking a task requires STDIN and STDOUT to be a terminal")
+	// ErrNoTaskPicked is returned when the user cancels picking a task
+	ErrNoTaskPicked = errors.New("task: No task picked")
+)
+
+// CanPickTask reports whether tasks can be picked interactively, which
+// requires STDIN and STDOUT to be a terminal
+func (e *Executor) CanPickTask() bool {
+	return isTerminal(e.Stdin) && isTerminal(e.Stdout)
+}
+
+// PickTask shows the tasks listed by --list-all, excluding the ones removed
+// by the given filters, with their description and location, and returns
+// the one the user picks by fuzzy searching them
+func (e *Executor) PickTask(filters ...FilterFunc) (string, error) {
+	if !e.CanPickTask() {
+		return "", ErrPickNotTerminal
+	}
+
+	var items []picker.Item
+	_ = e.RangeTaskList(func(t *taskfile.Task) error {
+		item := picker.Item{Name: t.Task, Desc: t.Desc}
+		if t.Location.Taskfile != "" {
+			item.Location = t.Location.String()
+		}
+		items = append(items, item)
+		return nil
+	}, append(filters, FilterOutInternal())...)
+	if len(items) == 0 {
+		return "", errors.New("task: No tasks available")
+	}
+
+	item, err := picker.Run(e.Stdin.(*os.File), e.Stdout.(*os.File), items)
+	if errors.Is(err, picker.ErrCanceled) {
+		return "", ErrNoTaskPicked
+	}
+	if err != nil {
+		return "", err
+	}
+	return item.Name, nil
+}
@@ -0,0 +1,176 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// plan is the execution plan printed by "--dry --json"
+type plan struct {
+	Tasks []*planTask `json:"tasks"`
+}
+
+// planTask is a task of the plan, in the order tasks would start
+type planTask struct {
+	Task string     `json:"task"`
+	Dir  string     `json:"dir"`
+	Deps []string   `json:"deps,omitempty"`
+	Cmds []*planCmd `json:"cmds,omitempty"`
+	// Env are the names of the variables set by the Taskfile. Values are
+	// left out since they may be secrets.
+	Env []string `json:"env,omitempty"`
+	// Skip is whether the task is predicted to be skipped, since it's up to
+	// date, and SkipReason why it's predicted to run or not
+	Skip       bool   `json:"skip"`
+	SkipReason string `json:"skip_reason"`
+}
+
+// planCmd is a command of a task of the plan. Only one of Cmd, Task, Fetch,
+// FileOp, Verify and WaitFor is set.
+type planCmd struct {
+	Cmd         string       `json:"cmd,omitempty"`
+	Task        string       `json:"task,omitempty"`
+	Fetch       *planFetch   `json:"fetch,omitempty"`
+	FileOp      *planFileOp  `json:"file_op,omitempty"`
+	Verify      string       `json:"verify,omitempty"`
+	WaitFor     *planWaitFor `json:"wait_for,omitempty"`
+	Defer       bool         `json:"defer,omitempty"`
+	Privileged  bool         `json:"privileged,omitempty"`
+	TTY         bool         `json:"tty,omitempty"`
+	IgnoreError bool         `json:"ignore_error,omitempty"`
+}
+
+type planFetch struct {
+	URL  string `json:"url"`
+	Dest string `json:"dest"`
+}
+
+type planFileOp struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	Dest string `json:"dest,omitempty"`
+}
+
+type planWaitFor struct {
+	TCP  string `json:"tcp,omitempty"`
+	HTTP string `json:"http,omitempty"`
+	File string `json:"file,omitempty"`
+	Cmd  string `json:"cmd,omitempty"`
+}
+
+// PlanJSON prints the tasks the given calls would run as a JSON document,
+// without running them: their rendered commands, directories, the names of
+// their environment variables and whether they're predicted to be skipped.
+// Tasks appear once, in the order they would start, after their
+// dependencies. Like with "--status --json", "status" commands are run to
+// predict the skips.
+func (e *Executor) PlanJSON(ctx context.Context, calls ...taskfile.Call) error {
+	if err := e.checkNotInspecting("plan tasks"); err != nil {
+		return err
+	}
+	calls, err := e.expandCalls(calls...)
+	if err != nil {
+		return err
+	}
+
+	p := &plan{Tasks: []*planTask{}}
+	planned := make(map[string]bool)
+	for _, call := range calls {
+		if err := e.planCall(ctx, p, planned, call); err != nil {
+			return err
+		}
+	}
+
+	enc := json.NewEncoder(e.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// planCall adds the task of the call to the plan, after its dependencies
+// and before the tasks it calls, unless it's already planned
+func (e *Executor) planCall(ctx context.Context, p *plan, planned map[string]bool, call taskfile.Call) error {
+	t, err := e.CompiledTask(call)
+	if err != nil {
+		return err
+	}
+	h, err := e.GetHash(t)
+	if err != nil {
+		return err
+	}
+	// Tasks with an empty hash, like the ones with "run: always", run every
+	// time they're called
+	if h != "" {
+		if planned[h] {
+			return nil
+		}
+		planned[h] = true
+	}
+
+	deps := taskfile.AllDeps(t.Deps)
+	for _, dep := range deps {
+		if err := e.planCall(ctx, p, planned, taskfile.Call{Task: dep.Task, Vars: dep.Vars}); err != nil {
+			return err
+		}
+	}
+
+	pt := &planTask{Task: e.redact(t.Name()), Dir: t.Dir}
+	for _, dep := range deps {
+		pt.Deps = append(pt.Deps, dep.Task)
+	}
+	for k := range t.Env.ToCacheMap() {
+		pt.Env = append(pt.Env, k)
+	}
+	sort.Strings(pt.Env)
+	for _, cmd := range t.Cmds {
+		pt.Cmds = append(pt.Cmds, e.planCmd(t, cmd))
+	}
+
+	report, err := e.statusReport(ctx, t)
+	if err != nil {
+		return err
+	}
+	pt.Skip = report.UpToDate
+	pt.SkipReason = report.Reason
+	p.Tasks = append(p.Tasks, pt)
+
+	// Up to date tasks don't call other tasks
+	if pt.Skip {
+		return nil
+	}
+	for _, cmd := range t.Cmds {
+		if cmd.Task == "" {
+			continue
+		}
+		if err := e.planCall(ctx, p, planned, taskfile.Call{Task: cmd.Task, Vars: cmd.Vars}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Executor) planCmd(t *taskfile.Task, cmd *taskfile.Cmd) *planCmd {
+	pc := &planCmd{
+		Cmd:         e.redact(cmd.Cmd),
+		Task:        cmd.Task,
+		Defer:       cmd.Defer,
+		Privileged:  cmd.Privileged || t.Privileged,
+		TTY:         cmd.TTY,
+		IgnoreError: cmd.IgnoreError,
+	}
+	if f := cmd.Fetch; f != nil {
+		pc.Fetch = &planFetch{URL: e.redact(f.URL), Dest: f.Dest}
+	}
+	if op := cmd.FileOp; op != nil {
+		pc.FileOp = &planFileOp{Op: op.Op, Path: op.Path, Dest: op.Dest}
+	}
+	if v := cmd.Verify; v != nil {
+		pc.Verify = v.File
+	}
+	if w := cmd.WaitFor; w != nil {
+		pc.WaitFor = &planWaitFor{TCP: w.TCP, HTTP: e.redact(w.HTTP), File: w.File, Cmd: e.redact(w.Cmd)}
+	}
+	return pc
+}
@@ -0,0 +1,118 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/execext"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// PolicyCommand is a command about to run, given to the Policy to approve
+type PolicyCommand struct {
+	Task string `json:"task"`
+	// Kind is where the command comes from: "cmd", "status", "precondition",
+	// "fetch", "wait_for", "artifact", "node_version" or "var", for the
+	// commands of dynamic variables. Task is empty for the variables of the
+	// Taskfile.
+	Kind string `json:"kind"`
+	// Cmd is the shell command, with the variables replaced. It's empty for
+	// downloads of "fetch", which set URL instead.
+	Cmd        string `json:"cmd,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Dir        string `json:"dir"`
+	Privileged bool   `json:"privileged,omitempty"`
+}
+
+// policyDeniedError is returned when the Policy denies a command
+type policyDeniedError struct {
+	cmd    *PolicyCommand
+	reason string
+}
+
+func (err *policyDeniedError) Error() string {
+	what := err.cmd.Cmd
+	if what == "" {
+		what = err.cmd.URL
+	}
+	if err.cmd.Task == "" {
+		return fmt.Sprintf(`task: Policy denied the %s %q: %s`, err.cmd.Kind, what, err.reason)
+	}
+	return fmt.Sprintf(`task: Policy denied the %s %q of task "%s": %s`, err.cmd.Kind, what, err.cmd.Task, err.reason)
+}
+
+// setupPolicy uses the command in TASK_POLICY as the Policy, unless one is
+// already set
+func (e *Executor) setupPolicy() {
+	if e.Policy != nil {
+		return
+	}
+	if command := os.Getenv("TASK_POLICY"); command != "" {
+		e.Policy = commandPolicy(command)
+	}
+}
+
+// commandPolicy returns a Policy running the given shell command, with the
+// command to approve given as JSON to its STDIN. Exiting non-zero denies the
+// command, with the output of the policy as the reason.
+func commandPolicy(command string) func(context.Context, *PolicyCommand) error {
+	return func(ctx context.Context, c *PolicyCommand) error {
+		input, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		var output bytes.Buffer
+		err = execext.RunCommand(ctx, &execext.RunCommandOptions{
+			Command: command,
+			Stdin:   bytes.NewReader(input),
+			Stdout:  &output,
+			Stderr:  &output,
+		})
+		if execext.IsExitError(err) {
+			reason := strings.TrimSpace(output.String())
+			if reason == "" {
+				reason = err.Error()
+			}
+			return errors.New(reason)
+		}
+		if err != nil {
+			return fmt.Errorf("could not run the policy: %w", err)
+		}
+		return nil
+	}
+}
+
+// checkPolicy asks the Policy, if any, to approve a command of the task.
+// Commands of dry runs are not checked, since they don't run.
+func (e *Executor) checkPolicy(ctx context.Context, t *taskfile.Task, c *PolicyCommand) error {
+	if e.Policy == nil {
+		return nil
+	}
+	c.Task = t.Name()
+	c.Dir = t.Dir
+	return e.askPolicy(ctx, c)
+}
+
+// checkVarPolicy asks the Policy, if any, to approve the command of a
+// dynamic variable. It's given to the compiler, which doesn't know the task
+// of the variable.
+func (e *Executor) checkVarPolicy(cmd, dir string) error {
+	if e.Policy == nil {
+		return nil
+	}
+	return e.askPolicy(context.Background(), &PolicyCommand{Kind: "var", Cmd: cmd, Dir: dir})
+}
+
+func (e *Executor) askPolicy(ctx context.Context, c *PolicyCommand) error {
+	// Errors running the policy deny the command too, so guardrails can't be
+	// bypassed by breaking it
+	if err := e.Policy(ctx, c); err != nil {
+		return &policyDeniedError{cmd: c, reason: e.redact(err.Error())}
+	}
+	return nil
+}
@@ -16,14 +16,18 @@ var (
 
 func (e *Executor) areTaskPreconditionsMet(ctx context.Context, t *taskfile.Task) (bool, error) {
 	for _, p := range t.Preconditions {
+		if err := e.checkPolicy(ctx, t, &PolicyCommand{Kind: "precondition", Cmd: p.Sh}); err != nil {
+			return false, err
+		}
 		err := execext.RunCommand(ctx, &execext.RunCommandOptions{
 			Command: p.Sh,
 			Dir:     t.Dir,
-			Env:     getEnviron(t),
+			Env:     e.getEnviron(t),
 		})
 
 		if err != nil {
-			e.Logger.Errf(logger.Magenta, "task: %s", p.Msg)
+			e.Logger.Debugf("[%s] precondition %q failed: %v", e.redact(t.Name()), e.redact(p.Sh), err)
+			e.Logger.Errf(logger.Magenta, "task: %s", e.redact(p.Msg))
 			return false, ErrPreconditionFailed
 		}
 	}
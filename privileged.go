@@ -0,0 +1,19 @@
+package task
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-task/task/v3/internal/execext"
+)
+
+// elevate asks for the credentials needed by privileged commands the first
+// time one runs, so tasks don't prompt for them again
+func (e *Executor) elevate(ctx context.Context) error {
+	e.elevateOnce.Do(func() {
+		if err := execext.Elevate(ctx, e.Stdin, e.Stderr); err != nil {
+			e.elevateErr = fmt.Errorf("task: Could not elevate privileges: %w", err)
+		}
+	})
+	return e.elevateErr
+}
@@ -0,0 +1,19 @@
+package task
+
+import (
+	"encoding/json"
+
+	"github.com/go-task/task/v3/taskfile/read"
+)
+
+// PromptInfo prints a line of JSON with the path of the Taskfile, its number
+// of tasks and whether its cached merged version is stale, for shell prompts
+// to show. It's meant to be fast, so Setup doesn't need to be called first
+// and the Taskfile is only partially read.
+func (e *Executor) PromptInfo() error {
+	info, err := read.Info(e.Dir, e.Entrypoint, taskfileCacheDir())
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(e.Stdout).Encode(info)
+}
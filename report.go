@@ -0,0 +1,253 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+type reportStatus string
+
+const (
+	reportRan      reportStatus = "ran"
+	reportUpToDate reportStatus = "up to date"
+	reportReused   reportStatus = "reused"
+	reportFailed   reportStatus = "failed"
+)
+
+// reportWriters are the supported formats of run reports
+var reportWriters = map[string]func(w io.Writer, entries []reportEntry, total time.Duration) error{
+	"markdown":   writeMarkdownReport,
+	"manifest":   writeManifestReport,
+	"provenance": writeProvenanceReport,
+}
+
+// runReport records the executions of tasks during a run, to write a report
+// of them when it finishes
+type runReport struct {
+	files []reportFile
+	start time.Time
+	// manifest is set when a manifest or a provenance was requested, so the
+	// generated files are recorded
+	manifest bool
+	// provenance is set when a provenance was requested, so the commands,
+	// environment and sources of the tasks are recorded
+	provenance bool
+
+	mutex   sync.Mutex
+	entries []reportEntry
+}
+
+type reportFile struct {
+	format string
+	path   string
+}
+
+type reportEntry struct {
+	task     string
+	status   reportStatus
+	start    time.Time
+	duration time.Duration
+	err      string
+	// generated are the files matched by the generates of the task, only
+	// recorded for manifests and provenances
+	generated []generatedFile
+	// cmds, env and sources are how the task generated its files, only
+	// recorded for provenances
+	cmds    []string
+	env     map[string]string
+	sources []generatedFile
+}
+
+// generatedFile is a file generated or used by a task, with its path relative
+// to the root Taskfile when it's inside of its directory
+type generatedFile struct {
+	path string
+	abs  string
+}
+
+func (e *Executor) setupReports() error {
+	if len(e.Reports) == 0 {
+		return nil
+	}
+
+	// The executions recorded before the Taskfile was reloaded are kept
+	report := &runReport{}
+	if e.report != nil {
+		report.start = e.report.start
+		report.entries = e.report.entries
+	}
+	e.report = report
+	for _, r := range e.Reports {
+		format, path, ok := strings.Cut(r, "=")
+		if !ok || path == "" {
+			return fmt.Errorf(`task: Invalid report %q. It should be in the "format=path" form, like "markdown=report.md"`, r)
+		}
+		if _, ok := reportWriters[format]; !ok {
+			return fmt.Errorf(`task: Unsupported report format %q. Available options: "markdown", "manifest" and "provenance"`, format)
+		}
+		if format == "manifest" || format == "provenance" {
+			e.report.manifest = true
+		}
+		if format == "provenance" {
+			e.report.provenance = true
+		}
+		e.report.files = append(e.report.files, reportFile{
+			format: format,
+			path:   filepathext.SmartJoin(e.Dir, path),
+		})
+	}
+	return nil
+}
+
+// recordExecution records the execution of a task, unless no report was
+// requested
+func (e *Executor) recordExecution(t *taskfile.Task, status reportStatus, start time.Time, err error) {
+	if e.report == nil {
+		return
+	}
+
+	entry := reportEntry{task: e.redact(t.Name()), status: status, start: start}
+	if !start.IsZero() {
+		entry.duration = time.Since(start)
+	}
+	if err != nil {
+		entry.status = reportFailed
+		entry.err = e.redact(err.Error())
+	}
+	if e.report.manifest && entry.status != reportFailed {
+		for _, f := range e.generatedFiles(t) {
+			entry.generated = append(entry.generated, e.reportedFile(t, f))
+		}
+	}
+	if e.report.provenance && entry.status != reportFailed {
+		for _, cmd := range t.Cmds {
+			if cmd.Cmd != "" {
+				entry.cmds = append(entry.cmds, e.redact(cmd.Cmd))
+			}
+		}
+		entry.env = e.provenanceEnv(t)
+		for _, f := range e.sourceFiles(t) {
+			entry.sources = append(entry.sources, e.reportedFile(t, f))
+		}
+	}
+
+	e.report.mutex.Lock()
+	defer e.report.mutex.Unlock()
+	e.report.entries = append(e.report.entries, entry)
+}
+
+// reportedFile returns the given file of the task, with its path relative to
+// the root Taskfile when it's inside of its directory
+func (e *Executor) reportedFile(t *taskfile.Task, f string) generatedFile {
+	abs := filepathext.SmartJoin(t.Dir, f)
+	path := abs
+	if rel, err := filepath.Rel(e.Dir, abs); err == nil && !strings.HasPrefix(rel, "..") {
+		path = filepath.ToSlash(rel)
+	}
+	return generatedFile{path: path, abs: abs}
+}
+
+// writeReports writes the report of the run to the requested files
+func (e *Executor) writeReports() error {
+	if e.report == nil {
+		return nil
+	}
+
+	e.report.mutex.Lock()
+	defer e.report.mutex.Unlock()
+	total := time.Since(e.report.start)
+
+	for _, file := range e.report.files {
+		f, err := os.Create(file.path)
+		if err != nil {
+			return fmt.Errorf("task: Failed to write the report: %w", err)
+		}
+		err = reportWriters[file.format](f, e.report.entries, total)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("task: Failed to write the report: %w", err)
+		}
+		if file.format == "provenance" && e.ProvenanceKey != "" {
+			if err := signProvenance(file.path, e.ProvenanceKey); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var reportIcons = map[reportStatus]string{
+	reportRan:      "✅",
+	reportUpToDate: "⚡",
+	reportReused:   "♻️",
+	reportFailed:   "❌",
+}
+
+// writeMarkdownReport writes a table of the executed tasks, followed by the
+// errors of the failed ones. It's meant to be posted as a comment on pull
+// requests or as the job summary of GitHub Actions.
+func writeMarkdownReport(w io.Writer, entries []reportEntry, total time.Duration) error {
+	var b strings.Builder
+	b.WriteString("## Task run report\n\n")
+	if len(entries) == 0 {
+		b.WriteString("No tasks were executed.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	counts := make(map[reportStatus]int)
+	b.WriteString("| Task | Status | Duration |\n")
+	b.WriteString("| --- | --- | --- |\n")
+	for _, entry := range entries {
+		counts[entry.status]++
+		duration := "-"
+		if entry.status != reportReused {
+			duration = formatReportDuration(entry.duration)
+		}
+		fmt.Fprintf(&b, "| %s | %s %s | %s |\n", markdownCode(entry.task), reportIcons[entry.status], entry.status, duration)
+	}
+
+	var summary []string
+	for _, status := range []reportStatus{reportRan, reportUpToDate, reportReused, reportFailed} {
+		if counts[status] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	fmt.Fprintf(&b, "\n%d tasks in %s: %s.\n", len(entries), formatReportDuration(total), strings.Join(summary, ", "))
+
+	if counts[reportFailed] > 0 {
+		b.WriteString("\n### Failures\n")
+		for _, entry := range entries {
+			if entry.status != reportFailed {
+				continue
+			}
+			fmt.Fprintf(&b, "\n#### %s\n\n```\n%s\n```\n", markdownCode(entry.task), strings.TrimRight(entry.err, "\n"))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func formatReportDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
+
+// markdownCode formats the given string as inline code that can be used in a
+// table cell
+func markdownCode(s string) string {
+	return "`" + strings.NewReplacer("`", "'", "|", `\|`).Replace(s) + "`"
+}
@@ -0,0 +1,75 @@
+package task
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// manifest lists the files generated by the tasks of a run, for release
+// tooling to build SBOMs or provenance attestations from
+type manifest struct {
+	Files []manifestFile `json:"files"`
+}
+
+type manifestFile struct {
+	Path   string            `json:"path"`
+	Task   string            `json:"task"`
+	Size   int64             `json:"size"`
+	Digest map[string]string `json:"digest"`
+}
+
+// writeManifestReport writes the files matched by the generates of the tasks
+// that ran or were up to date, with their hashes and the task that generated
+// them. Files generated by more than one task are attributed to the last one.
+func writeManifestReport(w io.Writer, entries []reportEntry, _ time.Duration) error {
+	files := make(map[string]manifestFile)
+	for _, entry := range entries {
+		for _, f := range entry.generated {
+			size, digest, err := hashFile(f.abs)
+			if os.IsNotExist(err) {
+				// Removed by a task that ran later
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			files[f.path] = manifestFile{
+				Path:   f.path,
+				Task:   entry.task,
+				Size:   size,
+				Digest: map[string]string{"sha256": digest},
+			}
+		}
+	}
+
+	m := manifest{Files: make([]manifestFile, 0, len(files))}
+	for _, f := range files {
+		m.Files = append(m.Files, f)
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// hashFile returns the size and the SHA-256 hash of the file
+func hashFile(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,179 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+const (
+	inTotoStatementType = "https://in-toto.io/Statement/v1"
+	slsaProvenanceType  = "https://slsa.dev/provenance/v1"
+	provenanceBuildType = "https://taskfile.dev/provenance/v1"
+	provenanceBuilderID = "https://taskfile.dev"
+)
+
+// provenanceStatement is an in-toto statement with a SLSA provenance
+// predicate, attesting how a task generated its files
+type provenanceStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []provenanceResource `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     provenancePredicate  `json:"predicate"`
+}
+
+type provenanceResource struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type provenancePredicate struct {
+	BuildDefinition provenanceBuildDefinition `json:"buildDefinition"`
+	RunDetails      provenanceRunDetails      `json:"runDetails"`
+}
+
+type provenanceBuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   provenanceParameters `json:"externalParameters"`
+	ResolvedDependencies []provenanceResource `json:"resolvedDependencies"`
+}
+
+type provenanceParameters struct {
+	Task string            `json:"task"`
+	Cmds []string          `json:"cmds"`
+	Env  map[string]string `json:"env"`
+}
+
+type provenanceRunDetails struct {
+	Builder  provenanceBuilder  `json:"builder"`
+	Metadata provenanceMetadata `json:"metadata"`
+}
+
+type provenanceBuilder struct {
+	ID string `json:"id"`
+}
+
+type provenanceMetadata struct {
+	StartedOn  string `json:"startedOn,omitempty"`
+	FinishedOn string `json:"finishedOn,omitempty"`
+}
+
+// writeProvenanceReport writes an in-toto statement for each task that ran or
+// was up to date and generated files, one per line. The statements record the
+// commands, the environment set by the Taskfile and the hashes of the sources
+// of the task.
+func writeProvenanceReport(w io.Writer, entries []reportEntry, _ time.Duration) error {
+	enc := json.NewEncoder(w)
+	for _, entry := range entries {
+		if entry.status == reportReused || len(entry.generated) == 0 {
+			continue
+		}
+
+		subjects, err := provenanceResources(entry.generated)
+		if err != nil {
+			return err
+		}
+		if len(subjects) == 0 {
+			continue
+		}
+		dependencies, err := provenanceResources(entry.sources)
+		if err != nil {
+			return err
+		}
+
+		cmds := entry.cmds
+		if cmds == nil {
+			cmds = []string{}
+		}
+		var metadata provenanceMetadata
+		if !entry.start.IsZero() {
+			metadata.StartedOn = entry.start.UTC().Format(time.RFC3339)
+			metadata.FinishedOn = entry.start.Add(entry.duration).UTC().Format(time.RFC3339)
+		}
+
+		statement := provenanceStatement{
+			Type:          inTotoStatementType,
+			Subject:       subjects,
+			PredicateType: slsaProvenanceType,
+			Predicate: provenancePredicate{
+				BuildDefinition: provenanceBuildDefinition{
+					BuildType: provenanceBuildType,
+					ExternalParameters: provenanceParameters{
+						Task: entry.task,
+						Cmds: cmds,
+						Env:  entry.env,
+					},
+					ResolvedDependencies: dependencies,
+				},
+				RunDetails: provenanceRunDetails{
+					Builder:  provenanceBuilder{ID: provenanceBuilderID},
+					Metadata: metadata,
+				},
+			},
+		}
+		if err := enc.Encode(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// provenanceResources hashes the given files, skipping the ones that don't
+// exist anymore
+func provenanceResources(files []generatedFile) ([]provenanceResource, error) {
+	resources := []provenanceResource{}
+	for _, f := range files {
+		_, digest, err := hashFile(f.abs)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, provenanceResource{
+			Name:   f.path,
+			Digest: map[string]string{"sha256": digest},
+		})
+	}
+	return resources, nil
+}
+
+// provenanceEnv returns the environment variables set by the Taskfile for the
+// task, redacting the secrets and the values of variables with sensitive
+// names. The environment Task inherits isn't included, since it may hold
+// credentials.
+func (e *Executor) provenanceEnv(t *taskfile.Task) map[string]string {
+	env := make(map[string]string)
+	for k, v := range t.Env.ToCacheMap() {
+		str, isString := v.(string)
+		if !isString {
+			continue
+		}
+		if sensitiveEnvName.MatchString(k) {
+			str = taskfile.RedactedValue
+		}
+		env[k] = e.redact(str)
+	}
+	return env
+}
+
+// signProvenance signs the provenance report with cosign, writing the
+// signature bundle next to it, with a ".bundle" extension
+func signProvenance(path, key string) error {
+	if _, err := exec.LookPath("cosign"); err != nil {
+		return fmt.Errorf("task: cosign is needed to sign the provenance: %w", err)
+	}
+
+	cmd := exec.Command("cosign", "sign-blob", "--yes", "--key", key, "--bundle", path+".bundle", path)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("task: Failed to sign the provenance: %w\n%s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
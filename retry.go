@@ -0,0 +1,154 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-task/task/v3/internal/execext"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+type attemptKey struct{}
+
+// attempt is the attempt of running the commands of a task, when it's run
+// again after failing
+type attempt struct {
+	task   string
+	number int
+	total  int
+}
+
+// taskLogName returns the name of the task shown when logging its commands,
+// with the attempt when it's run again after failing
+func (e *Executor) taskLogName(ctx context.Context, t *taskfile.Task) string {
+	name := e.redact(t.Name())
+	if a, ok := ctx.Value(attemptKey{}).(attempt); ok && a.task == t.Task {
+		name = fmt.Sprintf("%s (attempt %d/%d)", name, a.number, a.total)
+	}
+	return name
+}
+
+// runCmds runs the commands of a task. With a retry, they run again when
+// they fail, and with a timeout, each attempt is canceled when it takes too
+// long.
+func (e *Executor) runCmds(ctx context.Context, t *taskfile.Task, call taskfile.Call) error {
+	timeout, err := taskTimeout(t)
+	if err != nil {
+		return err
+	}
+	delay, err := taskRetryDelay(t)
+	if err != nil {
+		return err
+	}
+	total := 1
+	if t.Retry != nil {
+		total += t.Retry.Count
+	}
+
+	for n := 1; ; n++ {
+		attemptCtx := ctx
+		if n > 1 {
+			attemptCtx = context.WithValue(ctx, attemptKey{}, attempt{task: t.Task, number: n, total: total})
+		}
+		err := e.runAttempt(attemptCtx, t, call, timeout)
+		if err == nil || n == total || ctx.Err() != nil {
+			return err
+		}
+
+		if delay > 0 {
+			e.Logger.Errf(logger.Yellow, "task: [%s] Attempt %d/%d failed, retrying in %s: %v", e.taskLogName(attemptCtx, t), n, total, delay, err)
+		} else {
+			e.Logger.Errf(logger.Yellow, "task: [%s] Attempt %d/%d failed, retrying: %v", e.taskLogName(attemptCtx, t), n, total, err)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+		delay = time.Duration(float64(delay) * t.Retry.Backoff)
+	}
+}
+
+// taskTimeout returns the timeout of a task, if any
+func taskTimeout(t *taskfile.Task) (time.Duration, error) {
+	if t.Timeout == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(t.Timeout)
+	if err != nil {
+		err := fmt.Errorf(`task: Invalid timeout %q of task "%s": %w`, t.Timeout, t.Name(), err)
+		return 0, taskfile.WithLocation(err, t.Locations["timeout"])
+	}
+	return timeout, nil
+}
+
+// taskRetryDelay returns the delay before running a task again, if any
+func taskRetryDelay(t *taskfile.Task) (time.Duration, error) {
+	if t.Retry == nil || t.Retry.Delay == "" {
+		return 0, nil
+	}
+	delay, err := time.ParseDuration(t.Retry.Delay)
+	if err != nil {
+		err := fmt.Errorf(`task: Invalid retry delay %q of task "%s": %w`, t.Retry.Delay, t.Name(), err)
+		return 0, taskfile.WithLocation(err, t.Locations["retry"])
+	}
+	return delay, nil
+}
+
+// validateDurations checks the timeouts and the retry delays of the tasks,
+// except the templated ones, which are only known once they're compiled
+func (e *Executor) validateDurations() error {
+	for _, t := range e.Taskfile.Tasks {
+		if !strings.Contains(t.Timeout, "{{") {
+			if _, err := taskTimeout(t); err != nil {
+				return err
+			}
+		}
+		if t.Retry != nil && !strings.Contains(t.Retry.Delay, "{{") {
+			if _, err := taskRetryDelay(t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runAttempt runs the commands of a task once, canceling them after the
+// given timeout, if any
+func (e *Executor) runAttempt(ctx context.Context, t *taskfile.Task, call taskfile.Call, timeout time.Duration) (err error) {
+	if timeout > 0 {
+		parent := ctx
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+		defer func() {
+			if err != nil && ctx.Err() != nil && parent.Err() == nil {
+				err = &TaskRunError{t.Task, fmt.Errorf("timed out after %s", timeout)}
+			}
+		}()
+	}
+
+	for i := range t.Cmds {
+		if t.Cmds[i].Defer {
+			defer e.runDeferred(t, call, i)
+			continue
+		}
+
+		if err := e.runCommand(ctx, t, call, i); err != nil {
+			if err2 := e.statusOnError(t); err2 != nil {
+				e.Logger.VerboseErrf(logger.Yellow, "task: error cleaning status on error: %v", err2)
+			}
+
+			if execext.IsExitError(err) && t.IgnoreError {
+				e.Logger.VerboseErrf(logger.Yellow, "task: task error ignored: %v", err)
+				continue
+			}
+
+			return &TaskRunError{t.Task, err}
+		}
+	}
+	return nil
+}
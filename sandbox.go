@@ -0,0 +1,73 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/execext"
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// sandbox returns the sandbox the commands of the task run in, if any. They
+// may only write to the sources and generates of the task, to devices and to
+// a temporary directory of their own.
+func (e *Executor) sandbox(t *taskfile.Task) (*execext.Sandbox, error) {
+	if t.Sandbox == nil {
+		return nil, nil
+	}
+
+	s := &execext.Sandbox{Network: t.Sandbox.Network}
+	add := func(path string) {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		s.Writable = append(s.Writable, path)
+	}
+	for _, g := range t.Generates {
+		path, err := sandboxPath(t.Dir, g, true)
+		if err != nil {
+			return nil, err
+		}
+		add(path)
+	}
+	for _, src := range t.Sources {
+		path, err := sandboxPath(t.Dir, src, false)
+		if err != nil {
+			return nil, err
+		}
+		add(path)
+	}
+	if runtime.GOOS != "windows" {
+		add("/dev")
+	}
+	return s, nil
+}
+
+// sandboxPath returns the path a glob of sources or generates allows writing
+// to: the directory before its first wildcard, or the file itself. Missing
+// files allow writing to their directory instead. Since only existing paths
+// can be allowed, the missing directories of generates are created.
+func sandboxPath(dir, glob string, create bool) (string, error) {
+	path, err := execext.Expand(filepathext.SmartJoin(dir, glob))
+	if err != nil {
+		return "", err
+	}
+
+	if i := strings.IndexAny(path, "*?[{"); i >= 0 {
+		path = filepath.Dir(path[:i+1])
+	} else if _, err := os.Stat(path); err == nil {
+		return path, nil
+	} else {
+		path = filepath.Dir(path)
+	}
+
+	if create {
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			return "", err
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,135 @@
+package task
+
+import (
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// registerSecrets keeps track of the values of the secret variables, so
+// they can be redacted from the output of Task.
+func (e *Executor) registerSecrets(vars *taskfile.Vars) {
+	_ = vars.Range(func(_ string, v taskfile.Var) error {
+		if !v.Secret || v.Static == "" {
+			return nil
+		}
+
+		e.secretsMutex.Lock()
+		defer e.secretsMutex.Unlock()
+
+		for _, s := range e.secrets {
+			if s == v.Static {
+				return nil
+			}
+		}
+		e.secrets = append(e.secrets, v.Static)
+		return nil
+	})
+}
+
+// redact replaces the values of known secret variables in the given string
+func (e *Executor) redact(s string) string {
+	e.secretsMutex.RLock()
+	defer e.secretsMutex.RUnlock()
+
+	for _, secret := range e.secrets {
+		s = strings.ReplaceAll(s, secret, taskfile.RedactedValue)
+	}
+	return s
+}
+
+// redactedTask returns a copy of the given compiled task with the values of
+// secret variables redacted, so it can be displayed.
+func (e *Executor) redactedTask(t *taskfile.Task) *taskfile.Task {
+	c := t.DeepCopy()
+	c.Label = e.redact(c.Label)
+	c.Desc = e.redact(c.Desc)
+	c.Summary = e.redact(c.Summary)
+	for i, cmd := range c.Cmds {
+		if cmd == nil {
+			continue
+		}
+		redacted := *cmd
+		redacted.Cmd = e.redact(cmd.Cmd)
+		c.Cmds[i] = &redacted
+	}
+	return c
+}
+
+// hasSecrets returns true if the values of some secret variables are known
+func (e *Executor) hasSecrets() bool {
+	e.secretsMutex.RLock()
+	defer e.secretsMutex.RUnlock()
+	return len(e.secrets) > 0
+}
+
+// redactWriter redacts the values of known secret variables from what is
+// written to it, like CI services mask secrets in logs. The end of a write
+// that may be the start of a secret is held back until the next write, or
+// until the writer is closed.
+type redactWriter struct {
+	e       *Executor
+	w       io.Writer
+	mutex   sync.Mutex
+	pending string
+}
+
+// redactWriters wraps the given STDOUT and STDERR of a command with
+// redactWriter when the values of some secrets are known. The returned
+// function writes what was held back once the command is done.
+func (e *Executor) redactWriters(stdout, stderr io.Writer) (io.Writer, io.Writer, func()) {
+	if !e.hasSecrets() {
+		return stdout, stderr, func() {}
+	}
+	redactOut := &redactWriter{e: e, w: stdout}
+	redactErr := &redactWriter{e: e, w: stderr}
+	return redactOut, redactErr, func() {
+		_ = redactOut.Close()
+		_ = redactErr.Close()
+	}
+}
+
+// Write implements io.Writer interface
+func (rw *redactWriter) Write(p []byte) (int, error) {
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
+
+	s := rw.e.redact(rw.pending + string(p))
+	n := rw.secretPrefixLen(s)
+	rw.pending = s[len(s)-n:]
+	if _, err := io.WriteString(rw.w, s[:len(s)-n]); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close writes what was held back
+func (rw *redactWriter) Close() error {
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
+
+	s := rw.pending
+	rw.pending = ""
+	_, err := io.WriteString(rw.w, s)
+	return err
+}
+
+// secretPrefixLen returns the length of the longest end of the given string
+// that is the start of a secret
+func (rw *redactWriter) secretPrefixLen(s string) int {
+	rw.e.secretsMutex.RLock()
+	defer rw.e.secretsMutex.RUnlock()
+
+	var n int
+	for _, secret := range rw.e.secrets {
+		for i := len(secret) - 1; i > n; i-- {
+			if strings.HasSuffix(s, secret[:i]) {
+				n = i
+				break
+			}
+		}
+	}
+	return n
+}
@@ -1,11 +1,11 @@
 package task
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -15,6 +15,8 @@ import (
 	"github.com/go-task/task/v3/internal/filepathext"
 	"github.com/go-task/task/v3/internal/logger"
 	"github.com/go-task/task/v3/internal/output"
+	"github.com/go-task/task/v3/internal/prompt"
+	"github.com/go-task/task/v3/internal/toolversions"
 	"github.com/go-task/task/v3/taskfile"
 	"github.com/go-task/task/v3/taskfile/read"
 
@@ -25,12 +27,39 @@ func (e *Executor) Setup() error {
 	if err := e.setCurrentDir(); err != nil {
 		return err
 	}
+	e.setupStdFiles()
+	e.setupLogger()
+	e.setupPolicy()
+
+	if err := e.setupTaskfile(); err != nil {
+		return err
+	}
+	e.setupConcurrencyState()
+
+	return nil
+}
+
+// setupTaskfile reads the Taskfile and runs the steps of Setup that depend
+// on it. It's also run when the Taskfile is reloaded while watching, so the
+// state given by the previous Taskfile is reset first.
+func (e *Executor) setupTaskfile() error {
+	e.foldedTaskNames = nil
+	e.envOverrides = nil
+	e.pinnedTools = nil
+	e.shimsPath = ""
+	e.checksumScope = ""
 
 	if err := e.readTaskfile(); err != nil {
 		return err
 	}
 
 	e.setupFuzzyModel()
+	if err := e.setupSort(); err != nil {
+		return err
+	}
+	if err := e.setupTaskNameCase(); err != nil {
+		return err
+	}
 
 	v, err := e.Taskfile.ParsedVersion()
 	if err != nil {
@@ -40,25 +69,42 @@ func (e *Executor) Setup() error {
 	if err := e.setupTempDir(); err != nil {
 		return err
 	}
-	e.setupStdFiles()
-	e.setupLogger()
 	if err := e.setupOutput(); err != nil {
 		return err
 	}
+	if err := e.setupOverrides(); err != nil {
+		return err
+	}
+	if err := e.setupChecksumScope(); err != nil {
+		return err
+	}
 	if err := e.setupCompiler(v); err != nil {
 		return err
 	}
 	if err := e.readDotEnvFiles(v); err != nil {
 		return err
 	}
+	if err := e.setupToolVersions(); err != nil {
+		return err
+	}
 
 	if err := e.doVersionChecks(v); err != nil {
 		return err
 	}
 	e.setupDefaults(v)
-	e.setupConcurrencyState()
-
-	return nil
+	if err := e.setupReports(); err != nil {
+		return err
+	}
+	if err := e.setupOnInterrupt(); err != nil {
+		return err
+	}
+	if err := e.validateUmasks(); err != nil {
+		return err
+	}
+	if err := e.validateDurations(); err != nil {
+		return err
+	}
+	return e.validateSnippets()
 }
 
 func (e *Executor) setCurrentDir() error {
@@ -79,16 +125,53 @@ func (e *Executor) setCurrentDir() error {
 }
 
 func (e *Executor) readTaskfile() error {
+	// Git isn't run in inspect mode, so only the cached remote Taskfiles are
+	// included
+	readerNode := &read.ReaderNode{
+		Dir:         e.Dir,
+		Entrypoint:  e.Entrypoint,
+		Parent:      nil,
+		Optional:    false,
+		Logger:      e.Logger,
+		Lenient:     e.Lenient,
+		Offline:     e.Offline || e.Inspect,
+		Concurrency: includeConcurrency(),
+		Strict:      e.Validate,
+	}
+
 	var err error
-	e.Taskfile, e.Dir, err = read.Taskfile(&read.ReaderNode{
-		Dir:        e.Dir,
-		Entrypoint: e.Entrypoint,
-		Parent:     nil,
-		Optional:   false,
-	})
+	if cacheDir := taskfileCacheDir(); cacheDir != "" && !e.Validate {
+		e.Taskfile, e.Dir, err = read.CachedTaskfile(readerNode, cacheDir)
+	} else {
+		e.Taskfile, e.Dir, err = read.Taskfile(readerNode)
+	}
 	return err
 }
 
+// includeConcurrency returns how many Taskfiles are read at the same time,
+// as set by TASK_INCLUDE_CONCURRENCY, or 0 for the default, including when
+// it's invalid
+func includeConcurrency() int {
+	n, _ := strconv.Atoi(os.Getenv("TASK_INCLUDE_CONCURRENCY"))
+	return n
+}
+
+// taskfileCacheDir returns where merged Taskfiles are cached, or an empty
+// string if caching wasn't enabled with TASK_TASKFILE_CACHE
+func taskfileCacheDir() string {
+	if enabled, _ := strconv.ParseBool(os.Getenv("TASK_TASKFILE_CACHE")); !enabled {
+		return ""
+	}
+	if dir := os.Getenv("TASK_TASKFILE_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, "task", "taskfiles")
+}
+
 func (e *Executor) setupFuzzyModel() {
 	if e.Taskfile != nil {
 		return
@@ -110,6 +193,93 @@ func (e *Executor) setupFuzzyModel() {
 	e.fuzzyModel = model
 }
 
+func (e *Executor) setupTaskNameCase() error {
+	switch e.Taskfile.TaskNameCase {
+	case "", "sensitive":
+		return nil
+	case "insensitive":
+	default:
+		err := fmt.Errorf(`task: invalid task_name_case "%s". Available options: "sensitive" and "insensitive"`, e.Taskfile.TaskNameCase)
+		return taskfile.WithLocation(err, e.Taskfile.Locations["task_name_case"])
+	}
+
+	e.foldedTaskNames = make(map[string]string, len(e.Taskfile.Tasks))
+	add := func(name string) error {
+		folded := strings.ToLower(name)
+		if other, ok := e.foldedTaskNames[folded]; ok && other != name {
+			return fmt.Errorf(`task: Task names %q and %q only differ by case, which is not allowed with "task_name_case: insensitive"`, other, name)
+		}
+		e.foldedTaskNames[folded] = name
+		return nil
+	}
+	for _, task := range e.sortedTasks() {
+		if err := add(task.Task); err != nil {
+			return err
+		}
+		for _, alias := range task.Aliases {
+			if err := add(alias); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *Executor) setupToolVersions() error {
+	switch e.Taskfile.ToolVersions {
+	case "":
+		return nil
+	case "verify", "shims":
+	default:
+		err := fmt.Errorf(`task: invalid tool_versions "%s". Available options: "verify" and "shims"`, e.Taskfile.ToolVersions)
+		return taskfile.WithLocation(err, e.Taskfile.Locations["tool_versions"])
+	}
+
+	tools, err := toolversions.Read(e.Dir)
+	if err != nil {
+		return err
+	}
+	if len(tools) == 0 {
+		e.Logger.VerboseErrf(logger.Magenta, `task: "tool_versions" is set, but no ".tool-versions" or "mise.toml" was found`)
+		return nil
+	}
+
+	if e.Taskfile.ToolVersions == "verify" {
+		e.pinnedTools = tools
+		return nil
+	}
+
+	shims := toolversions.ShimDirs()
+	if len(shims) == 0 {
+		e.Logger.VerboseErrf(logger.Magenta, `task: No asdf or mise shims found, so tools are used from PATH`)
+		return nil
+	}
+	e.shimsPath = strings.Join(append(shims, os.Getenv("PATH")), string(os.PathListSeparator))
+	return nil
+}
+
+func (e *Executor) setupOnInterrupt() error {
+	if e.Taskfile.OnInterrupt == "" {
+		return nil
+	}
+	if _, err := e.GetTask(taskfile.Call{Task: e.Taskfile.OnInterrupt}); err != nil {
+		err := fmt.Errorf(`task: on_interrupt task "%s" does not exist`, e.Taskfile.OnInterrupt)
+		return taskfile.WithLocation(err, e.Taskfile.Locations["on_interrupt"])
+	}
+	return nil
+}
+
+// validateUmasks checks the umask of every task, so invalid ones are found
+// before anything runs
+func (e *Executor) validateUmasks() error {
+	for _, t := range e.Taskfile.Tasks {
+		if _, _, err := taskUmask(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (e *Executor) setupTempDir() error {
 	if e.TempDir != "" {
 		return nil
@@ -150,17 +320,43 @@ func (e *Executor) setupLogger() {
 		Stderr:  e.Stderr,
 		Verbose: e.Verbose,
 		Color:   e.Color,
+		Debug:   e.Debug,
+		Redact:  e.redact,
 	}
 }
 
 func (e *Executor) setupOutput() error {
-	if !e.OutputStyle.IsSet() {
+	// The output style of the Taskfile is followed when it's reloaded, unlike
+	// the one given
+	if !e.OutputStyle.IsSet() || e.taskfileOutput {
 		e.OutputStyle = e.Taskfile.Output
+		e.taskfileOutput = true
 	}
 
 	var err error
 	e.Output, err = output.BuildFor(&e.OutputStyle)
-	return err
+	if err != nil {
+		return err
+	}
+	e.Logger.Stdout, e.Logger.Stderr = e.Stdout, e.Stderr
+
+	// On a terminal, the last line of output of the running commands is
+	// shown while they're grouped, and cleared before Task logs anything
+	if group, ok := e.Output.(output.Group); ok && isTerminal(e.Stdout) {
+		group.Tail = true
+		e.Output = group
+		e.Logger.Stdout = output.TailSafe(e.Stdout)
+		e.Logger.Stderr = output.TailSafe(e.Stderr)
+	}
+
+	if e.OutputStyle.Name == "plain" {
+		// Colors, the logo and emojis would be noise for screen readers
+		e.Color = false
+		e.Logger.Color = false
+		e.NoLogo = true
+		e.ASCII = true
+	}
+	return nil
 }
 
 func (e *Executor) setupCompiler(v float64) error {
@@ -177,13 +373,25 @@ func (e *Executor) setupCompiler(v float64) error {
 			TaskfileVars: e.Taskfile.Vars,
 			Expansions:   e.Taskfile.Expansions,
 			Logger:       e.Logger,
+			Inspect:      e.Inspect,
+			Policy:       e.checkVarPolicy,
 		}
 	} else {
+		var prompter *prompt.Prompter
+		if !e.NoPrompt {
+			prompter = &prompt.Prompter{
+				Stdin:  e.Stdin,
+				Stderr: e.Stderr,
+			}
+		}
 		e.Compiler = &compilerv3.CompilerV3{
 			Dir:          e.Dir,
 			TaskfileEnv:  e.Taskfile.Env,
 			TaskfileVars: e.Taskfile.Vars,
 			Logger:       e.Logger,
+			Prompter:     prompter,
+			Inspect:      e.Inspect,
+			Policy:       e.checkVarPolicy,
 		}
 	}
 
@@ -191,18 +399,25 @@ func (e *Executor) setupCompiler(v float64) error {
 }
 
 func (e *Executor) readDotEnvFiles(v float64) error {
-	if v < 3.0 {
+	if v < 3.0 || e.Inspect {
 		return nil
 	}
 
-	env, err := read.Dotenv(e.Compiler, e.Taskfile, e.Dir)
+	dotenv, err := read.Dotenv(e.Compiler, e.Taskfile, e.Dir)
 	if err != nil {
 		return err
 	}
+	e.dotenvFiles = dotenv.Files
 
-	err = env.Range(func(key string, value taskfile.Var) error {
+	err = dotenv.Env.Range(func(key string, value taskfile.Var) error {
 		if _, ok := e.Taskfile.Env.Mapping[key]; !ok {
 			e.Taskfile.Env.Set(key, value)
+			if dotenv.Overrides[key] {
+				if e.envOverrides == nil {
+					e.envOverrides = make(map[string]bool)
+				}
+				e.envOverrides[key] = true
+			}
 		}
 		return nil
 	})
@@ -229,7 +444,7 @@ func (e *Executor) setupDefaults(v float64) {
 }
 
 func (e *Executor) setupConcurrencyState() {
-	e.executionHashes = make(map[string]context.Context)
+	e.executionHashes = make(map[string]*execution)
 
 	e.taskCallCount = make(map[string]*int32, len(e.Taskfile.Tasks))
 	e.mkdirMutexMap = make(map[string]*sync.Mutex, len(e.Taskfile.Tasks))
@@ -241,6 +456,7 @@ func (e *Executor) setupConcurrencyState() {
 	if e.Concurrency > 0 {
 		e.concurrencySemaphore = make(chan struct{}, e.Concurrency)
 	}
+	e.stdinOwner = make(chan struct{}, 1)
 }
 
 func (e *Executor) doVersionChecks(v float64) error {
@@ -0,0 +1,18 @@
+package task
+
+import "math/rand"
+
+// shuffle randomizes the order of n tasks with the given swap function, like
+// rand.Shuffle, when Shuffle is set. Runs with the same Seed shuffle the same
+// tasks the same way, as long as they're shuffled in the same order.
+func (e *Executor) shuffle(n int, swap func(i, j int)) {
+	if !e.Shuffle {
+		return
+	}
+	e.shuffleMutex.Lock()
+	defer e.shuffleMutex.Unlock()
+	if e.shuffleRand == nil {
+		e.shuffleRand = rand.New(rand.NewSource(e.Seed))
+	}
+	e.shuffleRand.Shuffle(n, swap)
+}
@@ -1,11 +1,15 @@
 package task
 
 import (
+	"context"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 
 	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/output"
+	"github.com/go-task/task/v3/taskfile"
 )
 
 // NOTE(@andreynering): This function intercepts SIGINT and SIGTERM signals
@@ -18,6 +22,7 @@ func (e *Executor) InterceptInterruptSignals() {
 	go func() {
 		for i := 1; i <= 3; i++ {
 			sig := <-ch
+			atomic.StoreInt32(&e.interrupted, 1)
 
 			if i < 3 {
 				e.Logger.Outf(logger.Yellow, `task: Signal received: "%s"`, sig)
@@ -25,7 +30,24 @@ func (e *Executor) InterceptInterruptSignals() {
 			}
 
 			e.Logger.Errf(logger.Red, `task: Signal received for the third time: "%s". Forcing shutdown`, sig)
+			// The commands still running are left behind, so their
+			// grouped output is written now, or it would be lost
+			output.Flush()
 			os.Exit(1)
 		}
 	}()
 }
+
+// runOnInterrupt runs the "on_interrupt" task of the Taskfile when a signal
+// was received during the run, so it can tear down what was left half
+// created. It gets a new context, since the one of the run may be canceled.
+func (e *Executor) runOnInterrupt() {
+	if atomic.LoadInt32(&e.interrupted) == 0 {
+		return
+	}
+
+	e.Logger.Errf(logger.Yellow, `task: Running "%s" after the interrupt`, e.Taskfile.OnInterrupt)
+	if err := e.RunTask(context.Background(), taskfile.Call{Task: e.Taskfile.OnInterrupt}); err != nil {
+		e.Logger.Errf(logger.Red, "%v", err)
+	}
+}
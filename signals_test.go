@@ -32,6 +32,7 @@ func TestSignalSentToProcessGroup(t *testing.T) {
 
 	testCases := map[string]struct {
 		args     []string
+		dir      string
 		sendSigs int
 		want     []string
 		notWant  []string
@@ -95,6 +96,18 @@ func TestSignalSentToProcessGroup(t *testing.T) {
 				"task: Failed to run task \"default\": exit status 4\n",
 			},
 		},
+		"on_interrupt task runs after the interrupted run": {
+			args:     []string{task, "--taskfile", "Taskfile.yml", "--", SLEEPIT, "default", "-sleep=10s"},
+			dir:      "testdata/on_interrupt",
+			sendSigs: 1,
+			want: []string{
+				"sleepit: ready\n",
+				"task: Signal received: \"interrupt\"\n",
+				"task: Running \"cleanup\" after the interrupt\n",
+				"tearing down after the interrupt\n",
+				"task: Failed to run task \"default\": exit status 130\n",
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -104,6 +117,9 @@ func TestSignalSentToProcessGroup(t *testing.T) {
 			sut.Stdout = &out
 			sut.Stderr = &out
 			sut.Dir = "testdata/ignore_signals"
+			if tc.dir != "" {
+				sut.Dir = tc.dir
+			}
 			// Create a new process group by setting the process group ID of the child
 			// to the child PID.
 			// By default, the child would inherit the process group of the parent, but
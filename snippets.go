@@ -0,0 +1,62 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// snippetCmds returns the compiled commands of the snippet used by cmd. They
+// have access to the variables of the task, as well as to the ones given to
+// the snippet, which win.
+func (e *Executor) snippetCmds(cmd *taskfile.Cmd, namespace string, vars *taskfile.Vars, r *templater.Templater) ([]*taskfile.Cmd, error) {
+	snippet, ok := e.Taskfile.Snippets[taskfile.ResolveReference(cmd.Use, namespace)]
+	if !ok {
+		return nil, taskfile.WithLocation(fmt.Errorf(`task: Snippet "%s" does not exist`, cmd.Use), cmd.Location)
+	}
+
+	snippetVars := vars.DeepCopy()
+	if snippetVars == nil {
+		snippetVars = &taskfile.Vars{}
+	}
+	snippetVars.Merge(r.ReplaceVars(cmd.Vars))
+	sr := templater.Templater{Vars: snippetVars, RemoveNoValue: r.RemoveNoValue, Funcs: r.Funcs}
+
+	cmds := make([]*taskfile.Cmd, 0, len(snippet))
+	for _, c := range snippet {
+		if c != nil {
+			cmds = append(cmds, e.compiledCmd(c, namespace, &sr))
+		}
+	}
+	return cmds, sr.Err()
+}
+
+// validateSnippets checks that the snippets used by tasks exist, and that
+// snippets don't use other snippets
+func (e *Executor) validateSnippets() error {
+	names := make([]string, 0, len(e.Taskfile.Snippets))
+	for name := range e.Taskfile.Snippets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, cmd := range e.Taskfile.Snippets[name] {
+			if cmd != nil && cmd.Use != "" {
+				return taskfile.WithLocation(fmt.Errorf(`task: Snippet "%s" can't use other snippets`, name), cmd.Location)
+			}
+		}
+	}
+	for _, t := range e.Taskfile.Tasks {
+		for _, cmd := range t.Cmds {
+			if cmd == nil || cmd.Use == "" {
+				continue
+			}
+			if _, ok := e.Taskfile.Snippets[taskfile.ResolveReference(cmd.Use, t.Namespace)]; !ok {
+				return taskfile.WithLocation(fmt.Errorf(`task: Snippet "%s" does not exist`, cmd.Use), cmd.Location)
+			}
+		}
+	}
+	return nil
+}
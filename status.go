@@ -12,6 +12,9 @@ import (
 
 // Status returns an error if any the of given tasks is not up-to-date
 func (e *Executor) Status(ctx context.Context, calls ...taskfile.Call) error {
+	if err := e.checkNotInspecting("check the status of tasks"); err != nil {
+		return err
+	}
 	for _, call := range calls {
 		t, err := e.CompiledTask(call)
 		if err != nil {
@@ -30,6 +33,7 @@ func (e *Executor) Status(ctx context.Context, calls ...taskfile.Call) error {
 
 func (e *Executor) isTaskUpToDate(ctx context.Context, t *taskfile.Task) (bool, error) {
 	if len(t.Status) == 0 && len(t.Sources) == 0 {
+		e.Logger.Debugf("[%s] has no sources or status, so it is never up to date", e.redact(t.Name()))
 		return false, nil
 	}
 
@@ -57,7 +61,7 @@ func (e *Executor) isTaskUpToDate(ctx context.Context, t *taskfile.Task) (bool,
 		}
 	}
 
-	return true, nil
+	return e.isTaskUpToDateUmask(t)
 }
 
 func (e *Executor) statusOnError(t *taskfile.Task) error {
@@ -69,9 +73,9 @@ func (e *Executor) statusOnError(t *taskfile.Task) error {
 }
 
 func (e *Executor) getStatusChecker(t *taskfile.Task) (status.Checker, error) {
-	method := t.Method
+	method, location := t.Method, t.Locations["method"]
 	if method == "" {
-		method = e.Taskfile.Method
+		method, location = e.Taskfile.Method, e.Taskfile.Locations["method"]
 	}
 	switch method {
 	case "timestamp":
@@ -81,38 +85,46 @@ func (e *Executor) getStatusChecker(t *taskfile.Task) (status.Checker, error) {
 	case "none":
 		return status.None{}, nil
 	default:
-		return nil, fmt.Errorf(`task: invalid method "%s"`, method)
+		return nil, taskfile.WithLocation(fmt.Errorf(`task: invalid method "%s"`, method), location)
 	}
 }
 
 func (e *Executor) timestampChecker(t *taskfile.Task) status.Checker {
 	return &status.Timestamp{
 		Dir:       t.Dir,
+		Task:      e.redact(t.Name()),
 		Sources:   t.Sources,
 		Generates: t.Generates,
+		Logger:    e.Logger,
 	}
 }
 
 func (e *Executor) checksumChecker(t *taskfile.Task) status.Checker {
 	return &status.Checksum{
 		TempDir:   e.TempDir,
+		Scope:     e.checksumScope,
 		TaskDir:   t.Dir,
-		Task:      t.Name(),
+		Task:      e.redact(t.Name()),
 		Sources:   t.Sources,
 		Generates: t.Generates,
 		Dry:       e.Dry,
+		Logger:    e.Logger,
 	}
 }
 
 func (e *Executor) isTaskUpToDateStatus(ctx context.Context, t *taskfile.Task) (bool, error) {
 	for _, s := range t.Status {
+		if err := e.checkPolicy(ctx, t, &PolicyCommand{Kind: "status", Cmd: s}); err != nil {
+			return false, err
+		}
 		err := execext.RunCommand(ctx, &execext.RunCommandOptions{
 			Command: s,
 			Dir:     t.Dir,
-			Env:     getEnviron(t),
+			Env:     e.getEnviron(t),
 		})
 		if err != nil {
 			e.Logger.VerboseOutf(logger.Yellow, "task: status command %s exited non-zero: %s", s, err)
+			e.Logger.Debugf("[%s] status command %q exited non-zero: %v", e.redact(t.Name()), e.redact(s), err)
 			return false, nil
 		}
 		e.Logger.VerboseOutf(logger.Yellow, "task: status command %s exited zero", s)
@@ -0,0 +1,172 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-task/task/v3/internal/execext"
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/status"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// statusReport is the state of a task printed by "--status --json"
+type statusReport struct {
+	Task     string `json:"task"`
+	UpToDate bool   `json:"up_to_date"`
+	Reason   string `json:"reason"`
+	Method   string `json:"method,omitempty"`
+	// SourcesModified is when the newest source was modified
+	SourcesModified *time.Time `json:"sources_modified,omitempty"`
+	// GeneratesModified is when the oldest generated file was modified
+	GeneratesModified *time.Time `json:"generates_modified,omitempty"`
+	// MissingGenerates are the "generates" that match no files
+	MissingGenerates []string `json:"missing_generates,omitempty"`
+}
+
+// StatusJSON prints the state of each of the given tasks as a JSON document
+// per line, like whether it's up to date and why. Unlike Status, every task
+// is checked, but an error is still returned if any isn't up to date.
+func (e *Executor) StatusJSON(ctx context.Context, calls ...taskfile.Call) error {
+	if err := e.checkNotInspecting("check the status of tasks"); err != nil {
+		return err
+	}
+	var notUpToDate []string
+	enc := json.NewEncoder(e.Stdout)
+	for _, call := range calls {
+		t, err := e.CompiledTask(call)
+		if err != nil {
+			return err
+		}
+		report, err := e.statusReport(ctx, t)
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+		if !report.UpToDate {
+			notUpToDate = append(notUpToDate, t.Name())
+		}
+	}
+
+	if len(notUpToDate) > 0 {
+		return fmt.Errorf(`task: Task "%s" is not up-to-date`, notUpToDate[0])
+	}
+	return nil
+}
+
+// statusReport goes through the same checks as isTaskUpToDate, recording
+// why the task isn't up to date. The stored checksums are left untouched.
+func (e *Executor) statusReport(ctx context.Context, t *taskfile.Task) (*statusReport, error) {
+	report := &statusReport{Task: e.redact(t.Name())}
+
+	sources, generates := e.fingerprintFiles(t, report)
+	if _, newest := modTimeRange(sources); !newest.IsZero() {
+		report.SourcesModified = &newest
+	}
+	if oldest, _ := modTimeRange(generates); !oldest.IsZero() {
+		report.GeneratesModified = &oldest
+	}
+
+	if len(t.Status) == 0 && len(t.Sources) == 0 {
+		report.Reason = "no sources or status"
+		return report, nil
+	}
+
+	for _, s := range t.Status {
+		if err := e.checkPolicy(ctx, t, &PolicyCommand{Kind: "status", Cmd: s}); err != nil {
+			return nil, err
+		}
+		err := execext.RunCommand(ctx, &execext.RunCommandOptions{
+			Command: s,
+			Dir:     t.Dir,
+			Env:     e.getEnviron(t),
+		})
+		if err != nil {
+			report.Reason = fmt.Sprintf("status command %q exited non-zero", e.redact(s))
+			return report, nil
+		}
+	}
+
+	if len(t.Sources) > 0 {
+		checker, err := e.getStatusChecker(t)
+		if err != nil {
+			return nil, err
+		}
+		if checksum, ok := checker.(*status.Checksum); ok {
+			checksum.Dry = true
+		}
+		report.Method = checker.Kind()
+
+		upToDate, err := checker.IsUpToDate()
+		if err != nil {
+			return nil, err
+		}
+		if !upToDate {
+			switch {
+			case report.Method == "none":
+				report.Reason = `method is "none"`
+			case len(report.MissingGenerates) > 0:
+				report.Reason = "generated files are missing"
+			case report.Method == "timestamp":
+				report.Reason = "sources are newer than the generated files"
+			default:
+				report.Reason = "sources changed"
+			}
+			return report, nil
+		}
+	}
+
+	upToDate, err := e.isTaskUpToDateUmask(t)
+	if err != nil {
+		return nil, err
+	}
+	if !upToDate {
+		report.Reason = "permissions of the generated files don't match the umask"
+		return report, nil
+	}
+
+	report.UpToDate = true
+	report.Reason = "up to date"
+	return report, nil
+}
+
+// fingerprintFiles returns the files matched by the sources and generates of
+// the task, recording the generates that match nothing in the report
+func (e *Executor) fingerprintFiles(t *taskfile.Task, report *statusReport) (sources, generates []string) {
+	for _, g := range t.Sources {
+		files, _ := status.Glob(t.Dir, g)
+		sources = append(sources, files...)
+	}
+	for _, g := range t.Generates {
+		files, _ := status.Glob(t.Dir, g)
+		if len(files) == 0 {
+			report.MissingGenerates = append(report.MissingGenerates, g)
+		}
+		generates = append(generates, files...)
+	}
+	return sources, generates
+}
+
+// modTimeRange returns the oldest and newest modification times of the
+// files. Files that can't be read are ignored.
+func modTimeRange(files []string) (oldest, newest time.Time) {
+	for _, f := range files {
+		info, err := os.Stat(filepathext.LongPath(f))
+		if err != nil {
+			continue
+		}
+		modified := info.ModTime().UTC()
+		if oldest.IsZero() || modified.Before(oldest) {
+			oldest = modified
+		}
+		if modified.After(newest) {
+			newest = modified
+		}
+	}
+	return oldest, newest
+}
@@ -0,0 +1,76 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// stdinOwnerKey is set in the context of the interactive task owning the
+// input of Task, so the tasks it calls don't wait for it
+type stdinOwnerKey struct{}
+
+// acquireStdin waits for no other interactive task to be reading the input
+// of Task, when t is interactive and reads it. It returns the context of the
+// task, marked as owning the input, and the func releasing it.
+func (e *Executor) acquireStdin(ctx context.Context, t *taskfile.Task) (context.Context, func(), error) {
+	if !readsStdin(t) || !isInteractive(t) || ctx.Value(stdinOwnerKey{}) != nil || e.stdinOwner == nil {
+		return ctx, emptyFunc, nil
+	}
+
+	select {
+	case e.stdinOwner <- struct{}{}:
+	default:
+		e.Logger.Debugf("[%s] waiting for another interactive task to release the input", e.redact(t.Name()))
+		select {
+		case e.stdinOwner <- struct{}{}:
+		case <-ctx.Done():
+			return ctx, emptyFunc, ctx.Err()
+		}
+	}
+	return context.WithValue(ctx, stdinOwnerKey{}, true), func() { <-e.stdinOwner }, nil
+}
+
+// taskStdin returns the input of the commands of the task, given its stdin
+// policy, and the func closing it
+func (e *Executor) taskStdin(t *taskfile.Task) (io.Reader, func(), error) {
+	if t.Stdin == nil {
+		return e.Stdin, emptyFunc, nil
+	}
+	switch t.Stdin.Policy {
+	case taskfile.StdinNull:
+		return nil, emptyFunc, nil
+	case taskfile.StdinFile:
+		f, err := os.Open(filepathext.SmartJoin(t.Dir, t.Stdin.File))
+		if err != nil {
+			return nil, nil, fmt.Errorf("task: Could not open the stdin of task %q: %w", t.Name(), err)
+		}
+		return f, func() { f.Close() }, nil
+	default:
+		return e.Stdin, emptyFunc, nil
+	}
+}
+
+// readsStdin returns true if the commands of the task read the input of
+// Task
+func readsStdin(t *taskfile.Task) bool {
+	return t.Stdin == nil || t.Stdin.Policy == taskfile.StdinInherit
+}
+
+// isInteractive returns true if the task is interactive or has commands
+// run in a pseudo-terminal
+func isInteractive(t *taskfile.Task) bool {
+	if t.Interactive {
+		return true
+	}
+	for _, cmd := range t.Cmds {
+		if cmd.TTY {
+			return true
+		}
+	}
+	return false
+}
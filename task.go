@@ -4,18 +4,23 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
-	"path"
-	"sort"
+	"regexp"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/go-task/task/v3/internal/codeowners"
 	"github.com/go-task/task/v3/internal/compiler"
 	"github.com/go-task/task/v3/internal/execext"
+	"github.com/go-task/task/v3/internal/hash"
 	"github.com/go-task/task/v3/internal/logger"
 	"github.com/go-task/task/v3/internal/output"
 	"github.com/go-task/task/v3/internal/summary"
 	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/internal/toolversions"
 	"github.com/go-task/task/v3/taskfile"
 
 	"github.com/sajari/fuzzy"
@@ -46,10 +51,60 @@ type Executor struct {
 	Color       bool
 	Concurrency int
 	Interval    string
+	// Dashboard shows the status and output of the watched tasks in a
+	// dashboard, when watching on a terminal
+	Dashboard bool
+	// NoPrompt disables prompting for variables, so missing ones are an
+	// error instead
+	NoPrompt bool
+	// NoLogo disables the logo printed when listing tasks
+	NoLogo bool
+	// Reports are the files to write a report of the run to, as
+	// "format=path", like "markdown=report.md"
+	Reports []string
+	// ProvenanceKey is the key cosign signs the provenance reports with
+	ProvenanceKey string
+	// Sort is the order of the listed tasks, overriding the "sort" of the
+	// Taskfile. Available options: "default", "alphanumeric", "definition",
+	// "none" and "topological".
+	Sort string
+	// ASCII shows the ASCII fallback of the icons of the tasks, for terminals
+	// that don't support Unicode
+	ASCII bool
+	// Policy approves the commands of the tasks before they run, returning
+	// an error to deny them. Defaults to the command in TASK_POLICY, when set.
+	Policy func(ctx context.Context, cmd *PolicyCommand) error
+	// Notify sends a desktop notification when the run finishes
+	Notify bool
+	// Notifier sends the desktop notifications of Notify and of the tasks
+	// with "notify: true". Defaults to the native notifications of the
+	// operating system.
+	Notifier func(title, message string) error
+	// Lenient skips the included Taskfiles that can't be read, instead of
+	// failing, so the other tasks can still be listed and run
+	Lenient bool
+	// Offline only includes the remote Taskfiles already cached, instead of
+	// fetching them
+	Offline bool
+	// Inspect guarantees no command of the Taskfile runs, not even the ones
+	// of dynamic variables, and that its dotenv files aren't loaded, so
+	// untrusted Taskfiles can still be listed and summarized
+	Inspect bool
+	// Validate reports the unknown keys of the Taskfiles as errors, besides
+	// the values of the wrong type, and skips the Taskfile cache so all
+	// Taskfiles are read
+	Validate bool
+	// Shuffle runs the tasks given to Run and the ones matched by wildcard
+	// patterns in a random order given by Seed, to find hidden dependencies
+	// between them
+	Shuffle bool
+	Seed    int64
 
 	Stdin  io.Reader
 	Stdout io.Writer
 	Stderr io.Writer
+	// Debug receives a trace of the decisions made by Task when set
+	Debug io.Writer
 
 	Logger      *logger.Logger
 	Compiler    compiler.Compiler
@@ -58,18 +113,89 @@ type Executor struct {
 
 	taskvars   *taskfile.Vars
 	fuzzyModel *fuzzy.Model
+	// taskfileOutput is set when OutputStyle was taken from the Taskfile
+	taskfileOutput bool
+	// foldedTaskNames maps lower case task names and aliases to the actual
+	// ones when "task_name_case: insensitive" is set
+	foldedTaskNames map[string]string
+	// envOverrides are the keys of the Taskfile env whose values replace the
+	// environment variables already set, like the ones of dotenv files set
+	// to override them
+	envOverrides map[string]bool
+	// dotenvFiles are the paths of the dotenv files of the Taskfile
+	dotenvFiles []string
+	// pinnedTools are the tool versions to verify before running tasks, with
+	// "tool_versions: verify"
+	pinnedTools []toolversions.Tool
+	// report records the executions of tasks when Reports are requested
+	report *runReport
+	// shimsPath is the PATH of the commands, with the asdf and mise shims
+	// first, with "tool_versions: shims"
+	shimsPath string
+	// interrupted is set to 1 once a signal is received, to run the
+	// "on_interrupt" task
+	interrupted int32
+	// producedTasks are the tasks whose outputs can be used, since they ran
+	// or were up to date during the run
+	producedTasks      map[string]bool
+	producedTasksMutex sync.Mutex
+	// elevateOnce asks for the credentials of privileged commands once per
+	// run, keeping the error in elevateErr
+	elevateOnce sync.Once
+	elevateErr  error
+	// checksumScope is the directory of the checksums within the temp dir,
+	// given by the "checksum_scope" of the Taskfile
+	checksumScope string
+	// codeowners is the CODEOWNERS file of the repository found at
+	// repositoryRoot, read once the owners of a task are needed
+	codeowners     *codeowners.File
+	codeownersOnce sync.Once
+	repositoryRoot string
 
 	concurrencySemaphore chan struct{}
 	taskCallCount        map[string]*int32
 	mkdirMutexMap        map[string]*sync.Mutex
-	executionHashes      map[string]context.Context
+	executionHashes      map[string]*execution
 	executionHashesMutex sync.Mutex
+
+	secrets      []string
+	secretsMutex sync.RWMutex
+	// shuffleRand gives the order of the tasks when shuffling
+	shuffleRand  *rand.Rand
+	shuffleMutex sync.Mutex
+	// stdinOwner is held by the interactive task reading the input of Task,
+	// so interactive tasks running in parallel don't read it at once
+	stdinOwner chan struct{}
+}
+
+// execution holds the result of a task execution that may be shared
+// with identical calls made during the same run
+type execution struct {
+	done chan struct{}
+	err  error
 }
 
 // Run runs Task
-func (e *Executor) Run(ctx context.Context, calls ...taskfile.Call) error {
+func (e *Executor) Run(ctx context.Context, calls ...taskfile.Call) (err error) {
+	if e.report != nil {
+		e.report.start = time.Now()
+		defer func() {
+			if reportErr := e.writeReports(); err == nil {
+				err = reportErr
+			}
+		}()
+	}
+
 	// check if given tasks exist
 	for _, call := range calls {
+		if isTaskPattern(call.Task) {
+			if _, err := e.matchingTasks(call.Task); err != nil {
+				e.ListTasks(FilterOutInternal(), FilterOutNoDesc())
+				return err
+			}
+			continue
+		}
+
 		task, err := e.GetTask(call)
 		if err != nil {
 			e.ListTasks(FilterOutInternal(), FilterOutNoDesc())
@@ -82,6 +208,13 @@ func (e *Executor) Run(ctx context.Context, calls ...taskfile.Call) error {
 		}
 	}
 
+	if e.Summary || e.Watch {
+		var err error
+		if calls, err = e.expandCalls(calls...); err != nil {
+			return err
+		}
+	}
+
 	if e.Summary {
 		for i, c := range calls {
 			compiledTask, err := e.FastCompiledTask(c)
@@ -89,15 +222,40 @@ func (e *Executor) Run(ctx context.Context, calls ...taskfile.Call) error {
 				return nil
 			}
 			summary.PrintSpaceBetweenSummaries(e.Logger, i)
-			summary.PrintTask(e.Logger, compiledTask)
+			summary.PrintTask(e.Logger, e.redactedTask(compiledTask))
 		}
 		return nil
 	}
 
+	if err := e.checkNotInspecting("run tasks"); err != nil {
+		return err
+	}
+	if err := e.parseCliFlags(calls); err != nil {
+		return err
+	}
+
+	if err := toolversions.Verify(ctx, e.Dir, e.pinnedTools); err != nil {
+		return err
+	}
+
 	if e.Watch {
 		return e.watchTasks(calls...)
 	}
 
+	if !e.Dry && (e.Notify || len(e.Taskfile.Notifications) > 0) {
+		start := time.Now()
+		defer func() { e.notifyRun(calls, start, err) }()
+	}
+	if e.Taskfile.OnInterrupt != "" {
+		defer e.runOnInterrupt()
+	}
+
+	if e.Shuffle {
+		e.Logger.Errf(logger.Yellow, "task: Shuffling the order of the tasks with seed %d", e.Seed)
+		calls = slices.Clone(calls)
+		e.shuffle(len(calls), func(i, j int) { calls[i], calls[j] = calls[j], calls[i] })
+	}
+
 	g, ctx := errgroup.WithContext(ctx)
 	for _, c := range calls {
 		c := c
@@ -114,6 +272,24 @@ func (e *Executor) Run(ctx context.Context, calls ...taskfile.Call) error {
 
 // RunTask runs a task by its name
 func (e *Executor) RunTask(ctx context.Context, call taskfile.Call) error {
+	if err := e.checkNotInspecting("run tasks"); err != nil {
+		return err
+	}
+	return e.runTask(ctx, call, false)
+}
+
+// runTask runs a task by its name. Dependencies are memoized by default, so
+// identical calls (same task and vars) made by overlapping deps only run once.
+func (e *Executor) runTask(ctx context.Context, call taskfile.Call, isDep bool) error {
+	if isTaskPattern(call.Task) {
+		return e.runMatchingTasks(ctx, call, isDep)
+	}
+	if calls, serial, err := e.matrixCalls(call); err != nil {
+		return err
+	} else if calls != nil {
+		return e.runMatrixTask(ctx, calls, serial, isDep)
+	}
+
 	t, err := e.CompiledTask(call)
 	if err != nil {
 		return err
@@ -125,17 +301,62 @@ func (e *Executor) RunTask(ctx context.Context, call taskfile.Call) error {
 	release := e.acquireConcurrencyLimit()
 	defer release()
 
-	return e.startExecution(ctx, t, func(ctx context.Context) error {
+	// An explicit "run: always" asks for every call to be executed, so it
+	// isn't memoized unless "memoize" says otherwise
+	memoize := isDep && t.Run != "always"
+	if t.Memoize != nil {
+		memoize = *t.Memoize
+	}
+
+	return e.startExecution(ctx, t, memoize, func(ctx context.Context) (err error) {
+		defer func() { e.printTaskMessage(t, err) }()
 		e.Logger.VerboseErrf(logger.Magenta, `task: "%s" started`, call.Task)
+		if len(t.Deps) > 0 {
+			e.Logger.Debugf("[%s] waiting for %d dependencies", e.redact(t.Name()), len(t.Deps))
+		}
 		if err := e.runDeps(ctx, t); err != nil {
 			return err
 		}
 
-		if !e.Force {
+		if t.Tmpdir {
+			tt, remove, err := e.withTmpdir(t, call)
+			if err != nil {
+				return err
+			}
+			defer remove()
+			t = tt
+		}
+		if err := e.checkInputs(t); err != nil {
+			return err
+		}
+
+		start := time.Now()
+		status := reportRan
+		defer func() { e.recordExecution(t, status, start, err) }()
+		defer func() { e.recordHistory(t, status, start, err) }()
+		defer func() { e.notifyTask(t, status, start, err) }()
+
+		receipt, err := e.receipt(ctx, t)
+		if err != nil {
+			return err
+		}
+
+		if e.Force {
+			e.Logger.Debugf("[%s] running because of --force", e.redact(t.Name()))
+		} else {
 			if err := ctx.Err(); err != nil {
 				return err
 			}
 
+			if receipt != "" && e.hasReceipt(t, receipt) {
+				status = reportUpToDate
+				e.Logger.Debugf("[%s] skipped because it already ran once per %s", e.redact(t.Name()), strings.Join(t.OncePer, ", "))
+				if !e.Silent {
+					e.Logger.Errf(logger.Magenta, `task: Task "%s" already ran once per %s`, t.Name(), strings.Join(t.OncePer, ", "))
+				}
+				return nil
+			}
+
 			preCondMet, err := e.areTaskPreconditionsMet(ctx, t)
 			if err != nil {
 				return err
@@ -147,33 +368,47 @@ func (e *Executor) RunTask(ctx context.Context, call taskfile.Call) error {
 			}
 
 			if upToDate && preCondMet {
+				status = reportUpToDate
+				e.markProduced(t)
+				e.Logger.Debugf("[%s] skipped because it is up to date", e.redact(t.Name()))
 				if !e.Silent {
 					e.Logger.Errf(logger.Magenta, `task: Task "%s" is up to date`, t.Name())
 				}
 				return nil
 			}
+			e.Logger.Debugf("[%s] running because it is not up to date", e.redact(t.Name()))
 		}
 
 		if err := e.mkdir(t); err != nil {
 			e.Logger.Errf(logger.Red, "task: cannot make directory %q: %v", t.Dir, err)
 		}
 
-		for i := range t.Cmds {
-			if t.Cmds[i].Defer {
-				defer e.runDeferred(t, call, i)
-				continue
-			}
+		if err := e.checkNodeVersions(ctx, t); err != nil {
+			return err
+		}
 
-			if err := e.runCommand(ctx, t, call, i); err != nil {
-				if err2 := e.statusOnError(t); err2 != nil {
-					e.Logger.VerboseErrf(logger.Yellow, "task: error cleaning status on error: %v", err2)
-				}
+		ctx, release, err := e.acquireStdin(ctx, t)
+		if err != nil {
+			return err
+		}
+		defer release()
 
-				if execext.IsExitError(err) && t.IgnoreError {
-					e.Logger.VerboseErrf(logger.Yellow, "task: task error ignored: %v", err)
-					continue
-				}
+		if err := e.runCmds(ctx, t, call); err != nil {
+			return err
+		}
 
+		if err := e.checkOutputs(t); err != nil {
+			return &TaskRunError{t.Task, err}
+		}
+		e.markProduced(t)
+		if err := e.applyUmask(t); err != nil {
+			return &TaskRunError{t.Task, err}
+		}
+		if err := e.publishArtifacts(ctx, t, call); err != nil {
+			return &TaskRunError{t.Task, err}
+		}
+		if receipt != "" && !e.Dry {
+			if err := e.writeReceipt(t, receipt); err != nil {
 				return &TaskRunError{t.Task, err}
 			}
 		}
@@ -182,6 +417,19 @@ func (e *Executor) RunTask(ctx context.Context, call taskfile.Call) error {
 	})
 }
 
+// printTaskMessage prints the message set for when a task succeeds or fails
+func (e *Executor) printTaskMessage(t *taskfile.Task, err error) {
+	if e.Dry {
+		return
+	}
+	switch {
+	case err == nil && t.MsgSuccess != "":
+		e.Logger.Errf(logger.Green, "%s", e.redact(t.MsgSuccess))
+	case err != nil && t.MsgFailure != "":
+		e.Logger.Errf(logger.Red, "%s", e.redact(t.MsgFailure))
+	}
+}
+
 func (e *Executor) mkdir(t *taskfile.Task) error {
 	if t.Dir == "" {
 		return nil
@@ -209,17 +457,34 @@ func (e *Executor) runDeps(ctx context.Context, t *taskfile.Task) error {
 		d := d
 
 		g.Go(func() error {
-			err := e.RunTask(ctx, taskfile.Call{Task: d.Task, Vars: d.Vars})
-			if err != nil {
-				return err
-			}
-			return nil
+			return e.runDep(ctx, d)
 		})
 	}
 
 	return g.Wait()
 }
 
+// runDep runs a dependency, or each dependency of a serial group in order.
+// The failures of optional dependencies are only logged as warnings.
+func (e *Executor) runDep(ctx context.Context, d *taskfile.Dep) error {
+	var err error
+	if d.Serial != nil {
+		for _, sd := range d.Serial {
+			if err = e.runDep(ctx, sd); err != nil {
+				break
+			}
+		}
+	} else if err = e.runTask(ctx, taskfile.Call{Task: d.Task, Vars: d.Vars}, true); err != nil {
+		err = withCallLocation(err, d.Location)
+	}
+
+	if err != nil && d.Optional && ctx.Err() == nil {
+		e.Logger.Errf(logger.Yellow, "task: Optional dependency failed: %s", strings.TrimPrefix(err.Error(), "task: "))
+		return nil
+	}
+	return err
+}
+
 func (e *Executor) runDeferred(t *taskfile.Task, call taskfile.Call, i int) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -239,20 +504,26 @@ func (e *Executor) runCommand(ctx context.Context, t *taskfile.Task, call taskfi
 
 		err := e.RunTask(ctx, taskfile.Call{Task: cmd.Task, Vars: cmd.Vars})
 		if err != nil {
-			return err
+			return withCallLocation(err, cmd.Location)
 		}
 		return nil
 	case cmd.Cmd != "":
 		if e.Verbose || (!cmd.Silent && !t.Silent && !e.Taskfile.Silent && !e.Silent) {
-			e.Logger.Errf(logger.Green, "task: [%s] %s", t.Name(), cmd.Cmd)
+			e.Logger.Errf(logger.Green, "task: [%s] %s", e.taskLogName(ctx, t), e.redact(cmd.Cmd))
 		}
 
+		environ := e.getEnviron(t)
+		e.logEnviron(t, environ)
+
 		if e.Dry {
 			return nil
 		}
+		if err := e.checkPolicy(ctx, t, &PolicyCommand{Kind: "cmd", Cmd: cmd.Cmd, Privileged: cmd.Privileged || t.Privileged}); err != nil {
+			return err
+		}
 
 		outputWrapper := e.Output
-		if t.Interactive {
+		if t.Interactive || cmd.TTY {
 			outputWrapper = output.Interleaved{}
 		}
 		vars, err := e.Compiler.FastGetVariables(t, call)
@@ -267,30 +538,73 @@ func (e *Executor) runCommand(ctx context.Context, t *taskfile.Task, call taskfi
 			}
 		}()
 
-		err = execext.RunCommand(ctx, &execext.RunCommandOptions{
+		// Interactive tasks are given the terminal as is, so they can't
+		// be redacted
+		if !t.Interactive {
+			var flush func()
+			stdOut, stdErr, flush = e.redactWriters(stdOut, stdErr)
+			defer flush()
+		}
+
+		stdin, closeStdin, err := e.taskStdin(t)
+		if err != nil {
+			return err
+		}
+		defer closeStdin()
+
+		opts := &execext.RunCommandOptions{
 			Command: cmd.Cmd,
 			Dir:     t.Dir,
-			Env:     getEnviron(t),
-			Stdin:   e.Stdin,
+			Env:     environ,
+			WSL:     t.WSL,
+			Stdin:   stdin,
 			Stdout:  stdOut,
 			Stderr:  stdErr,
-		})
+			TTY:     cmd.TTY,
+			// Timed out commands shouldn't leave processes behind
+			KillTree: t.Timeout != "",
+		}
+		if t.Nix != nil {
+			opts.Nix = true
+			opts.NixShell = t.Nix.Shell
+		}
+		if opts.Sandbox, err = e.sandbox(t); err != nil {
+			return err
+		}
+		if cmd.Privileged || t.Privileged {
+			if err := e.elevate(ctx); err != nil {
+				return err
+			}
+			opts.Privileged = true
+		}
+		err = execext.RunCommand(ctx, opts)
 		if execext.IsExitError(err) && cmd.IgnoreError {
-			e.Logger.VerboseErrf(logger.Yellow, "task: [%s] command error ignored: %v", t.Name(), err)
+			e.Logger.VerboseErrf(logger.Yellow, "task: [%s] command error ignored: %v", e.redact(t.Name()), err)
 			return nil
 		}
 		return err
+	case cmd.Fetch != nil:
+		return e.runFetch(ctx, t, cmd)
+	case cmd.FileOp != nil:
+		return e.runFileOp(t, call, cmd)
+	case cmd.Verify != nil:
+		return e.runVerify(ctx, t, cmd)
+	case cmd.WaitFor != nil:
+		return e.runWaitFor(ctx, t, cmd)
 	default:
 		return nil
 	}
 }
 
-func getEnviron(t *taskfile.Task) []string {
-	if t.Env == nil {
+func (e *Executor) getEnviron(t *taskfile.Task) []string {
+	if t.Env == nil && e.shimsPath == "" && t.EnvPassthrough == nil && t.EnvBlock == nil {
 		return nil
 	}
 
-	environ := os.Environ()
+	environ := filterEnviron(t, os.Environ())
+	if e.shimsPath != "" {
+		environ = append(environ, "PATH="+e.shimsPath)
+	}
 
 	for k, v := range t.Env.ToCacheMap() {
 		str, isString := v.(string)
@@ -298,7 +612,7 @@ func getEnviron(t *taskfile.Task) []string {
 			continue
 		}
 
-		if _, alreadySet := os.LookupEnv(k); alreadySet {
+		if _, alreadySet := os.LookupEnv(k); alreadySet && !e.envOverrides[k] && envPassedThrough(t, k) {
 			continue
 		}
 
@@ -308,37 +622,66 @@ func getEnviron(t *taskfile.Task) []string {
 	return environ
 }
 
-func (e *Executor) startExecution(ctx context.Context, t *taskfile.Task, execute func(ctx context.Context) error) error {
+func (e *Executor) startExecution(ctx context.Context, t *taskfile.Task, memoize bool, execute func(ctx context.Context) error) error {
 	h, err := e.GetHash(t)
 	if err != nil {
 		return err
 	}
 
+	// NOTE: memoized calls are identified the same way as "when_changed",
+	// that is, by the task name and the hash of the compiled task
+	if h == "" && memoize {
+		if h, err = hash.Hash(t); err != nil {
+			return err
+		}
+	}
+
 	if h == "" {
 		return execute(ctx)
 	}
+	e.Logger.Debugf("[%s] identified by %s", e.redact(t.Name()), h)
 
 	e.executionHashesMutex.Lock()
-	otherExecutionCtx, ok := e.executionHashes[h]
+	otherExecution, ok := e.executionHashes[h]
 
 	if ok {
 		e.executionHashesMutex.Unlock()
 		e.Logger.VerboseErrf(logger.Magenta, "task: skipping execution of task: %s", h)
-		<-otherExecutionCtx.Done()
-		return nil
+		e.Logger.Debugf("[%s] skipped because an identical call already ran or is running, waiting for its result", e.redact(t.Name()))
+		<-otherExecution.done
+		// Failures are only reported for the execution that failed
+		if otherExecution.err == nil {
+			e.recordExecution(t, reportReused, time.Time{}, nil)
+		}
+		return otherExecution.err
 	}
 
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
-
-	e.executionHashes[h] = ctx
+	current := &execution{done: make(chan struct{})}
+	e.executionHashes[h] = current
 	e.executionHashesMutex.Unlock()
 
-	return execute(ctx)
+	defer close(current.done)
+	current.err = execute(ctx)
+	return current.err
+}
+
+// resetExecutions forgets the results of previous executions, so tasks
+// run again on a new run (e.g. when watching).
+func (e *Executor) resetExecutions() {
+	e.executionHashesMutex.Lock()
+	defer e.executionHashesMutex.Unlock()
+
+	e.executionHashes = make(map[string]*execution)
+
+	e.producedTasksMutex.Lock()
+	defer e.producedTasksMutex.Unlock()
+
+	e.producedTasks = nil
 }
 
 // GetTask will return the task with the name matching the given call from the taskfile.
-// If no task is found, it will search for tasks with a matching alias.
+// If no task is found, it will search for tasks with a matching alias, and then
+// for a matching catch-all task.
 // If multiple tasks contain the same alias or no matches are found an error is returned.
 func (e *Executor) GetTask(call taskfile.Call) (*taskfile.Task, error) {
 	// Search for a matching task
@@ -364,6 +707,21 @@ func (e *Executor) GetTask(call taskfile.Call) (*taskfile.Task, error) {
 	}
 	// If we found no tasks
 	if len(aliasedTasks) == 0 {
+		// The task may be one of an included Taskfile skipped by Lenient
+		if broken := e.brokenInclude(call.Task); broken != nil {
+			return nil, &brokenIncludeError{taskName: call.Task, include: broken}
+		}
+		// Ignore the case of the name, if enabled
+		if name, ok := e.foldedTaskNames[strings.ToLower(call.Task)]; ok && name != call.Task {
+			call.Task = name
+			return e.GetTask(call)
+		}
+
+		// Fallback to a catch-all task, like "*" or "deploy-*"
+		if catchAll := e.getCatchAllTask(call.Task); catchAll != nil {
+			return catchAll, nil
+		}
+
 		didYouMean := ""
 		if e.fuzzyModel != nil {
 			didYouMean = e.fuzzyModel.SpellCheck(call.Task)
@@ -379,42 +737,66 @@ func (e *Executor) GetTask(call taskfile.Call) (*taskfile.Task, error) {
 
 type FilterFunc func(tasks []*taskfile.Task) []*taskfile.Task
 
+// GetTaskList returns the compiled tasks, in the order they're listed,
+// excluding the ones removed by the given filters.
 func (e *Executor) GetTaskList(filters ...FilterFunc) []*taskfile.Task {
-	tasks := make([]*taskfile.Task, 0, len(e.Taskfile.Tasks))
+	tasks := e.sortedTasks()
 
 	// Fetch and compile the list of tasks
-	for _, task := range e.Taskfile.Tasks {
-		compiledTask, err := e.FastCompiledTask(taskfile.Call{Task: task.Task})
-		if err == nil {
-			task = compiledTask
+	for i, task := range tasks {
+		if compiledTask, err := e.FastCompiledTask(taskfile.Call{Task: task.Task}); err == nil {
+			tasks[i] = compiledTask
 		}
-		tasks = append(tasks, task)
-
 	}
 
 	// Filter the tasks
 	for _, filter := range filters {
 		tasks = filter(tasks)
 	}
+	return tasks
+}
 
-	sortKey := func(t *taskfile.Task) (key string) {
-		if t.Taskfile == path.Join(e.Dir, "Taskfile.yml") {
-			key += "0"
-		} else {
-			key += "1"
+// RangeTaskList calls fn for each task, in the order they're listed,
+// excluding the ones removed by the given filters, which are applied to each
+// task individually. Unlike GetTaskList, only tasks with a templated
+// description, summary or sources are compiled, so the tasks given to fn are
+// only meant to be listed and must not be modified. It stops at the first
+// error returned by fn.
+func (e *Executor) RangeTaskList(fn func(t *taskfile.Task) error, filters ...FilterFunc) error {
+	buf := make([]*taskfile.Task, 1)
+	for _, task := range e.sortedTasks() {
+		if isListTemplated(task) {
+			if compiledTask, err := e.FastCompiledTask(taskfile.Call{Task: task.Task}); err == nil {
+				task = compiledTask
+			}
 		}
 
-		return key + ":" + t.Task
+		buf[0] = task
+		tasks := buf[:1]
+		for _, filter := range filters {
+			tasks = filter(tasks)
+		}
+		for _, task := range tasks {
+			if err := fn(task); err != nil {
+				return err
+			}
+		}
 	}
+	return nil
+}
 
-	// Sort the tasks
-	// Tasks in the root taskfile go first
-	// Then everything else
-	sort.Slice(tasks, func(i, j int) bool {
-		return sortKey(tasks[i]) < sortKey(tasks[j])
-	})
-
-	return tasks
+// isListTemplated tells whether any of the fields of the task shown when
+// listing it has templates to replace
+func isListTemplated(t *taskfile.Task) bool {
+	if strings.Contains(t.Desc, "{{") || strings.Contains(t.Summary, "{{") {
+		return true
+	}
+	for _, s := range t.Sources {
+		if strings.Contains(s, "{{") {
+			return true
+		}
+	}
+	return false
 }
 
 // Filter is a generic task filtering function. It will remove each task in the
@@ -443,6 +825,39 @@ func FilterOutNoDesc() FilterFunc {
 	})
 }
 
+// FilterOutUntagged removes all tasks that have none of the given tags.
+func FilterOutUntagged(tags ...string) FilterFunc {
+	return Filter(func(task *taskfile.Task) bool {
+		for _, tag := range tags {
+			if slices.Contains(task.Tags, tag) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// FilterOutUnmatched removes all tasks whose name, aliases, description and
+// tags don't match the given regular expression.
+func FilterOutUnmatched(re *regexp.Regexp) FilterFunc {
+	return Filter(func(task *taskfile.Task) bool {
+		if re.MatchString(task.Task) || re.MatchString(task.Desc) {
+			return false
+		}
+		for _, alias := range task.Aliases {
+			if re.MatchString(alias) {
+				return false
+			}
+		}
+		for _, tag := range task.Tags {
+			if re.MatchString(tag) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
 // FilterOutInternal removes all tasks that are marked as internal.
 func FilterOutInternal() FilterFunc {
 	return Filter(func(task *taskfile.Task) bool {
@@ -0,0 +1,151 @@
+package task
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// taskSorts are the available orders of the listed tasks
+var taskSorts = []string{"default", "alphanumeric", "definition", "none", "topological"}
+
+func (e *Executor) setupSort() error {
+	if e.Sort != "" && !isTaskSort(e.Sort) {
+		return fmt.Errorf(`task: Invalid sort "%s". Available options: "%s"`, e.Sort, strings.Join(taskSorts, `", "`))
+	}
+	if e.Taskfile.Sort != "" && !isTaskSort(e.Taskfile.Sort) {
+		err := fmt.Errorf(`task: invalid sort "%s". Available options: "%s"`, e.Taskfile.Sort, strings.Join(taskSorts, `", "`))
+		return taskfile.WithLocation(err, e.Taskfile.Locations["sort"])
+	}
+	return nil
+}
+
+func isTaskSort(s string) bool {
+	for _, option := range taskSorts {
+		if s == option {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedTasks returns the tasks in the order they're listed, given by Sort
+// or by the "sort" of the Taskfile. By default, tasks in the root Taskfile go
+// first, then everything else, sorted by name.
+func (e *Executor) sortedTasks() []*taskfile.Task {
+	tasks := make([]*taskfile.Task, 0, len(e.Taskfile.Tasks))
+	for _, task := range e.Taskfile.Tasks {
+		tasks = append(tasks, task)
+	}
+
+	switch e.taskSort() {
+	case "alphanumeric":
+		sort.Slice(tasks, func(i, j int) bool {
+			return tasks[i].Task < tasks[j].Task
+		})
+	case "definition", "none":
+		e.sortByDefinition(tasks)
+	case "topological":
+		e.sortByDefault(tasks)
+		tasks = sortTopologically(tasks)
+	default:
+		e.sortByDefault(tasks)
+	}
+	return tasks
+}
+
+// taskSort returns the order of the listed tasks: Sort, the "sort" of the
+// Taskfile or "default"
+func (e *Executor) taskSort() string {
+	if e.Sort != "" {
+		return e.Sort
+	}
+	if e.Taskfile.Sort != "" {
+		return e.Taskfile.Sort
+	}
+	return "default"
+}
+
+func (e *Executor) sortByDefault(tasks []*taskfile.Task) {
+	rootTaskfile := path.Join(e.Dir, "Taskfile.yml")
+	sort.Slice(tasks, func(i, j int) bool {
+		iRoot, jRoot := tasks[i].Taskfile == rootTaskfile, tasks[j].Taskfile == rootTaskfile
+		if iRoot != jRoot {
+			return iRoot
+		}
+		return tasks[i].Task < tasks[j].Task
+	})
+}
+
+// sortByDefinition sorts the tasks in the order they're defined: tasks in
+// the root Taskfile go first, then the ones of each included Taskfile, in
+// the order they're included
+func (e *Executor) sortByDefinition(tasks []*taskfile.Task) {
+	includes := make(map[string]int)
+	if e.Taskfile.Includes != nil {
+		for i, namespace := range e.Taskfile.Includes.Keys {
+			includes[namespace] = i + 1
+		}
+	}
+	includeIndex := func(t *taskfile.Task) int {
+		if t.Namespace == "" {
+			return 0
+		}
+		namespace, _, _ := strings.Cut(t.Namespace, taskfile.NamespaceSeparator)
+		if i, ok := includes[namespace]; ok {
+			return i
+		}
+		return len(includes) + 1
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if ai, bi := includeIndex(a), includeIndex(b); ai != bi {
+			return ai < bi
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Location.Line != b.Location.Line {
+			return a.Location.Line < b.Location.Line
+		}
+		return a.Task < b.Task
+	})
+}
+
+// sortTopologically moves the tasks after the ones they depend on or call,
+// keeping the given order otherwise. Tasks in a cycle keep the given order.
+func sortTopologically(tasks []*taskfile.Task) []*taskfile.Task {
+	byName := make(map[string]*taskfile.Task, len(tasks))
+	for _, t := range tasks {
+		byName[t.Task] = t
+	}
+
+	sorted := make([]*taskfile.Task, 0, len(tasks))
+	visited := make(map[*taskfile.Task]bool, len(tasks))
+	var visit func(t *taskfile.Task)
+	visit = func(t *taskfile.Task) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		for _, dep := range taskfile.AllDeps(t.Deps) {
+			if byName[dep.Task] != nil {
+				visit(byName[dep.Task])
+			}
+		}
+		for _, cmd := range t.Cmds {
+			if cmd != nil && cmd.Task != "" && byName[cmd.Task] != nil {
+				visit(byName[cmd.Task])
+			}
+		}
+		sorted = append(sorted, t)
+	}
+	for _, t := range tasks {
+		visit(t)
+	}
+	return sorted
+}
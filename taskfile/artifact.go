@@ -0,0 +1,55 @@
+package taskfile
+
+import (
+	"errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidArtifact is returned when an artifact destination doesn't set
+// exactly one of "dir", "s3" and "cmd"
+var ErrInvalidArtifact = errors.New(`task: Artifacts must set exactly one of "dir", "s3" and "cmd"`)
+
+// Artifact is a destination for the files generated by a task, where they
+// are put after it succeeds
+type Artifact struct {
+	// Dir is a directory to copy the files to
+	Dir string
+	// S3 is an S3 URL, like "s3://bucket/prefix", to upload the files to
+	S3 string
+	// Cmd is a command run for each file, available as {{.ARTIFACT}}
+	Cmd string
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (a *Artifact) UnmarshalYAML(node *yaml.Node) error {
+	var dir string
+	if err := node.Decode(&dir); err == nil {
+		a.Dir = dir
+		return nil
+	}
+
+	var artifact struct {
+		Dir string
+		S3  string `yaml:"s3"`
+		Cmd string
+	}
+	if err := node.Decode(&artifact); err != nil {
+		return err
+	}
+
+	set := 0
+	for _, v := range []string{artifact.Dir, artifact.S3, artifact.Cmd} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return ErrInvalidArtifact
+	}
+
+	a.Dir = artifact.Dir
+	a.S3 = artifact.S3
+	a.Cmd = artifact.Cmd
+	return nil
+}
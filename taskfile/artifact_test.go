@@ -0,0 +1,34 @@
+package taskfile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+func TestArtifactParse(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected *taskfile.Artifact
+	}{
+		{"dist", &taskfile.Artifact{Dir: "dist"}},
+		{"dir: dist", &taskfile.Artifact{Dir: "dist"}},
+		{"s3: s3://bucket/prefix", &taskfile.Artifact{S3: "s3://bucket/prefix"}},
+		{`cmd: "scp {{.ARTIFACT}} host:"`, &taskfile.Artifact{Cmd: "scp {{.ARTIFACT}} host:"}},
+	}
+	for _, test := range tests {
+		var artifact taskfile.Artifact
+		err := yaml.Unmarshal([]byte(test.content), &artifact)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, &artifact)
+	}
+
+	for _, content := range []string{"{}", "{dir: dist, cmd: echo}"} {
+		var artifact taskfile.Artifact
+		err := yaml.Unmarshal([]byte(content), &artifact)
+		assert.ErrorIs(t, err, taskfile.ErrInvalidArtifact, content)
+	}
+}
@@ -1,43 +1,98 @@
 package taskfile
 
+import (
+	"errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidSerialDep is returned when a serial group of dependencies also
+// sets "task" or "vars"
+var ErrInvalidSerialDep = errors.New(`task: A serial group of dependencies can't set "task" or "vars"`)
+
 // Cmd is a task command
 type Cmd struct {
 	Cmd         string
 	Silent      bool
+	Privileged  bool
 	Task        string
 	Vars        *Vars
 	IgnoreError bool
 	Defer       bool
+	// Use is the name of a snippet whose commands replace this one, with
+	// Vars available to them
+	Use string
+	// Fetch is a file downloaded instead of running a command
+	Fetch *Fetch
+	// FileOp is a file operation run instead of a command
+	FileOp *FileOp
+	// Verify is a check of a file run instead of a command
+	Verify *Verify
+	// WaitFor is a condition waited for instead of running a command
+	WaitFor *WaitFor
+	// TTY runs the command in a pseudo-terminal, for programs requiring
+	// one. It's set by "tty" or "interactive".
+	TTY      bool
+	Location Location
 }
 
 // Dep is a task dependency
 type Dep struct {
 	Task string
 	Vars *Vars
+	// Serial is a group of dependencies run one after the other, in
+	// parallel with the other dependencies. Task is empty when it's set.
+	Serial []*Dep
+	// Optional makes a failure of the dependency a warning, instead of
+	// failing the task depending on it
+	Optional bool
+	Location Location
+}
+
+// AllDeps returns the given dependencies, with the ones of serial groups in
+// place of the groups
+func AllDeps(deps []*Dep) []*Dep {
+	all := make([]*Dep, 0, len(deps))
+	for _, dep := range deps {
+		switch {
+		case dep == nil:
+		case dep.Serial != nil:
+			all = append(all, AllDeps(dep.Serial)...)
+		default:
+			all = append(all, dep)
+		}
+	}
+	return all
 }
 
 // UnmarshalYAML implements yaml.Unmarshaler interface
-func (c *Cmd) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (c *Cmd) UnmarshalYAML(node *yaml.Node) error {
+	c.Location = locationOf(node)
 	var cmd string
-	if err := unmarshal(&cmd); err == nil {
+	if err := node.Decode(&cmd); err == nil {
 		c.Cmd = cmd
 		return nil
 	}
 	var cmdStruct struct {
 		Cmd         string
 		Silent      bool
+		Privileged  bool
+		TTY         bool
+		Interactive bool
 		IgnoreError bool `yaml:"ignore_error"`
 	}
-	if err := unmarshal(&cmdStruct); err == nil && cmdStruct.Cmd != "" {
+	if err := node.Decode(&cmdStruct); err == nil && cmdStruct.Cmd != "" {
 		c.Cmd = cmdStruct.Cmd
 		c.Silent = cmdStruct.Silent
+		c.Privileged = cmdStruct.Privileged
+		c.TTY = cmdStruct.TTY || cmdStruct.Interactive
 		c.IgnoreError = cmdStruct.IgnoreError
 		return nil
 	}
 	var deferredCmd struct {
 		Defer string
 	}
-	if err := unmarshal(&deferredCmd); err == nil && deferredCmd.Defer != "" {
+	if err := node.Decode(&deferredCmd); err == nil && deferredCmd.Defer != "" {
 		c.Defer = true
 		c.Cmd = deferredCmd.Defer
 		return nil
@@ -45,17 +100,51 @@ func (c *Cmd) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var deferredCall struct {
 		Defer Call
 	}
-	if err := unmarshal(&deferredCall); err == nil && deferredCall.Defer.Task != "" {
+	if err := node.Decode(&deferredCall); err == nil && deferredCall.Defer.Task != "" {
 		c.Defer = true
 		c.Task = deferredCall.Defer.Task
 		c.Vars = deferredCall.Defer.Vars
 		return nil
 	}
+	var fetchCmd struct {
+		Fetch *Fetch
+	}
+	if err := node.Decode(&fetchCmd); err == nil && fetchCmd.Fetch != nil {
+		c.Fetch = fetchCmd.Fetch
+		return nil
+	}
+	var verifyCmd struct {
+		Verify *Verify
+	}
+	if err := node.Decode(&verifyCmd); err == nil && verifyCmd.Verify != nil {
+		c.Verify = verifyCmd.Verify
+		return nil
+	}
+	var waitForCmd struct {
+		WaitFor *WaitFor `yaml:"wait_for"`
+	}
+	if err := node.Decode(&waitForCmd); err == nil && waitForCmd.WaitFor != nil {
+		c.WaitFor = waitForCmd.WaitFor
+		return nil
+	}
+	if fileOp := fileOpOf(node); fileOp != nil {
+		c.FileOp = fileOp
+		return nil
+	}
+	var snippetUse struct {
+		Use  string
+		Vars *Vars
+	}
+	if err := node.Decode(&snippetUse); err == nil && snippetUse.Use != "" {
+		c.Use = snippetUse.Use
+		c.Vars = snippetUse.Vars
+		return nil
+	}
 	var taskCall struct {
 		Task string
 		Vars *Vars
 	}
-	if err := unmarshal(&taskCall); err != nil {
+	if err := node.Decode(&taskCall); err != nil {
 		return err
 	}
 	c.Task = taskCall.Task
@@ -64,20 +153,28 @@ func (c *Cmd) UnmarshalYAML(unmarshal func(interface{}) error) error {
 }
 
 // UnmarshalYAML implements yaml.Unmarshaler interface
-func (d *Dep) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (d *Dep) UnmarshalYAML(node *yaml.Node) error {
+	d.Location = locationOf(node)
 	var task string
-	if err := unmarshal(&task); err == nil {
+	if err := node.Decode(&task); err == nil {
 		d.Task = task
 		return nil
 	}
 	var taskCall struct {
-		Task string
-		Vars *Vars
+		Task     string
+		Vars     *Vars
+		Serial   []*Dep
+		Optional bool
 	}
-	if err := unmarshal(&taskCall); err != nil {
+	if err := node.Decode(&taskCall); err != nil {
 		return err
 	}
+	if taskCall.Serial != nil && (taskCall.Task != "" || taskCall.Vars != nil) {
+		return ErrInvalidSerialDep
+	}
 	d.Task = taskCall.Task
 	d.Vars = taskCall.Vars
+	d.Serial = taskCall.Serial
+	d.Optional = taskCall.Optional
 	return nil
 }
@@ -0,0 +1,58 @@
+package taskfile
+
+import (
+	"errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DotenvFile is a dotenv file to be read
+type DotenvFile struct {
+	Path string
+	// Override is whether the values of the file replace the environment
+	// variables already set. When nil, the "dotenv_override" setting is used.
+	Override *bool
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (d *DotenvFile) UnmarshalYAML(node *yaml.Node) error {
+	var path string
+	if err := node.Decode(&path); err == nil {
+		d.Path = path
+		return nil
+	}
+
+	var dotenv struct {
+		Path     string
+		Override *bool
+	}
+	if err := node.Decode(&dotenv); err != nil {
+		return err
+	}
+	if dotenv.Path == "" {
+		return errors.New(`task: dotenv files must have a "path"`)
+	}
+	d.Path = dotenv.Path
+	d.Override = dotenv.Override
+	return nil
+}
+
+// EnvFiles are the dotenv files loaded for the commands of a task. It may be
+// declared as a single path or as a list of paths.
+type EnvFiles []string
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (ef *EnvFiles) UnmarshalYAML(node *yaml.Node) error {
+	var path string
+	if err := node.Decode(&path); err == nil {
+		*ef = EnvFiles{path}
+		return nil
+	}
+
+	var paths []string
+	if err := node.Decode(&paths); err != nil {
+		return err
+	}
+	*ef = paths
+	return nil
+}
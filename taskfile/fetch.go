@@ -0,0 +1,11 @@
+package taskfile
+
+// Fetch is a file downloaded by a command, instead of running one
+type Fetch struct {
+	URL  string
+	Dest string
+	// Checksum is the expected checksum of the file, like "sha256:<hex>".
+	// When set, the file isn't downloaded again if it already matches.
+	Checksum   string
+	Executable bool
+}
@@ -0,0 +1,54 @@
+package taskfile
+
+import "gopkg.in/yaml.v3"
+
+// The operations of a FileOp
+const (
+	FileOpCopy     = "copy"
+	FileOpMkdir    = "mkdir"
+	FileOpRm       = "rm"
+	FileOpTemplate = "template"
+)
+
+// FileOp is a file operation run by Task itself instead of the shell, so it
+// works the same way on every OS
+type FileOp struct {
+	Op string
+	// Path is the file or directory operated on. It's the source of copies
+	// and templates.
+	Path string
+	// Dest is the destination of copies and templates
+	Dest string
+}
+
+// fileOpOf returns the file operation declared in the given command node,
+// if any
+func fileOpOf(node *yaml.Node) *FileOp {
+	var fileOp struct {
+		Copy *struct {
+			From string
+			To   string
+		}
+		Mkdir    string
+		Rm       string
+		Template *struct {
+			Src  string
+			Dest string
+		}
+	}
+	if err := node.Decode(&fileOp); err != nil {
+		return nil
+	}
+	switch {
+	case fileOp.Copy != nil:
+		return &FileOp{Op: FileOpCopy, Path: fileOp.Copy.From, Dest: fileOp.Copy.To}
+	case fileOp.Mkdir != "":
+		return &FileOp{Op: FileOpMkdir, Path: fileOp.Mkdir}
+	case fileOp.Rm != "":
+		return &FileOp{Op: FileOpRm, Path: fileOp.Rm}
+	case fileOp.Template != nil:
+		return &FileOp{Op: FileOpTemplate, Path: fileOp.Template.Src, Dest: fileOp.Template.Dest}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,219 @@
+package taskfile
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// Flags is an ordered map of flags a task accepts after "--"
+type Flags struct {
+	Keys    []string
+	Mapping map[string]*Flag
+}
+
+// Flag is a flag a task accepts after "--" on the command line
+type Flag struct {
+	Var      string
+	Desc     string
+	Type     string
+	Default  string
+	Enum     []string
+	Required bool
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (fs *Flags) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return errors.New("task: flags is not a map")
+	}
+
+	content, err := mappingContent(node)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(content); i += 2 {
+		keyNode := content[i]
+		valueNode := content[i+1]
+
+		var f Flag
+		if err := valueNode.Decode(&f); err != nil {
+			return err
+		}
+		switch f.Type {
+		case "", "string", "bool":
+		default:
+			return fmt.Errorf(`task: invalid type %q for flag "--%s". Available options: "string" and "bool"`, f.Type, keyNode.Value)
+		}
+		fs.Set(keyNode.Value, &f)
+	}
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (f *Flag) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&f.Desc)
+	}
+
+	var flag struct {
+		Var      string
+		Desc     string
+		Type     string
+		Default  string
+		Enum     []string
+		Required bool
+	}
+	if err := node.Decode(&flag); err != nil {
+		return err
+	}
+	f.Var = flag.Var
+	f.Desc = flag.Desc
+	f.Type = flag.Type
+	f.Default = flag.Default
+	f.Enum = flag.Enum
+	f.Required = flag.Required
+	return nil
+}
+
+// Set sets a value to a given key
+func (fs *Flags) Set(key string, f *Flag) {
+	if fs.Mapping == nil {
+		fs.Mapping = make(map[string]*Flag, 1)
+	}
+	if !slices.Contains(fs.Keys, key) {
+		fs.Keys = append(fs.Keys, key)
+	}
+	fs.Mapping[key] = f
+}
+
+// Range allows you to loop into the flags in its right order
+func (fs *Flags) Range(yield func(key string, f *Flag) error) error {
+	if fs == nil {
+		return nil
+	}
+	for _, k := range fs.Keys {
+		if err := yield(k, fs.Mapping[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Len returns the size of the map
+func (fs *Flags) Len() int {
+	if fs == nil {
+		return 0
+	}
+	return len(fs.Keys)
+}
+
+// DeepCopy creates a new instance of Flags and copies
+// data by value from the source struct.
+func (fs *Flags) DeepCopy() *Flags {
+	if fs == nil {
+		return nil
+	}
+	c := &Flags{Keys: deepCopySlice(fs.Keys), Mapping: make(map[string]*Flag, len(fs.Mapping))}
+	for k, f := range fs.Mapping {
+		c.Mapping[k] = &Flag{
+			Var:      f.Var,
+			Desc:     f.Desc,
+			Type:     f.Type,
+			Default:  f.Default,
+			Enum:     deepCopySlice(f.Enum),
+			Required: f.Required,
+		}
+	}
+	return c
+}
+
+// IsBool returns true if the flag doesn't take a value
+func (f *Flag) IsBool() bool {
+	return f.Type == "bool"
+}
+
+// VarName returns the name of the variable the flag value is assigned to.
+// Unless set explicitly, it's the upper cased flag name, with dashes
+// replaced by underscores. E.g. "--dry-run" is assigned to "DRY_RUN".
+func (f *Flag) VarName(name string) string {
+	if f.Var != "" {
+		return f.Var
+	}
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// ParseFlags parses the given arguments according to the flags, and returns
+// the resulting variables. Flags that were not given and whose variables are
+// not already set in the given vars are set to their default values.
+func ParseFlags(flags *Flags, args []string, given *Vars) (*Vars, error) {
+	values := make(map[string]string, flags.Len())
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") || arg == "--" {
+			return nil, fmt.Errorf(`task: Unexpected argument %q. Expected a flag like "--name"`, arg)
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		f, ok := flags.Mapping[name]
+		if !ok {
+			return nil, fmt.Errorf(`task: Unknown flag "--%s". Available flags: %s`, name, flagNames(flags))
+		}
+
+		switch {
+		case hasValue:
+		case f.IsBool():
+			value = "true"
+		case i+1 < len(args):
+			i++
+			value = args[i]
+		default:
+			return nil, fmt.Errorf(`task: Flag "--%s" requires a value`, name)
+		}
+
+		if f.IsBool() && value != "true" && value != "false" {
+			return nil, fmt.Errorf(`task: Flag "--%s" expects "true" or "false", got %q`, name, value)
+		}
+		if len(f.Enum) > 0 && !slices.Contains(f.Enum, value) {
+			return nil, fmt.Errorf(`task: Invalid value %q for flag "--%s". Allowed values: %s`, value, name, strings.Join(f.Enum, ", "))
+		}
+		values[name] = value
+	}
+
+	vars := &Vars{}
+	err := flags.Range(func(name string, f *Flag) error {
+		value, ok := values[name]
+		if !ok {
+			if given != nil {
+				if _, isSet := given.Mapping[f.VarName(name)]; isSet {
+					return nil
+				}
+			}
+			if f.Required {
+				return fmt.Errorf(`task: Missing required flag "--%s"`, name)
+			}
+			value = f.Default
+			if value == "" && f.IsBool() {
+				value = "false"
+			}
+		}
+		vars.Set(f.VarName(name), Var{Static: value})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+func flagNames(flags *Flags) string {
+	names := make([]string, 0, flags.Len())
+	for _, name := range flags.Keys {
+		names = append(names, "--"+name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,120 @@
+package taskfile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+const yamlFlags = `
+env:
+  desc: The environment to deploy to
+  default: staging
+  enum: [staging, prod]
+version:
+  required: true
+dry-run:
+  type: bool
+target:
+  var: DEPLOY_TARGET
+`
+
+func TestFlagsParse(t *testing.T) {
+	var flags taskfile.Flags
+	assert.NoError(t, yaml.Unmarshal([]byte(yamlFlags), &flags))
+	assert.Equal(t, []string{"env", "version", "dry-run", "target"}, flags.Keys)
+	assert.Equal(t, &taskfile.Flag{
+		Desc:    "The environment to deploy to",
+		Default: "staging",
+		Enum:    []string{"staging", "prod"},
+	}, flags.Mapping["env"])
+
+	var invalid taskfile.Flags
+	assert.EqualError(
+		t,
+		yaml.Unmarshal([]byte("foo:\n  type: int\n"), &invalid),
+		`task: invalid type "int" for flag "--foo". Available options: "string" and "bool"`,
+	)
+}
+
+func TestParseFlags(t *testing.T) {
+	var flags taskfile.Flags
+	assert.NoError(t, yaml.Unmarshal([]byte(yamlFlags), &flags))
+
+	tests := []struct {
+		args     []string
+		given    *taskfile.Vars
+		expected map[string]string
+		err      string
+	}{
+		{
+			args: []string{"--version", "1.0"},
+			expected: map[string]string{
+				"ENV":           "staging",
+				"VERSION":       "1.0",
+				"DRY_RUN":       "false",
+				"DEPLOY_TARGET": "",
+			},
+		},
+		{
+			args: []string{"--env=prod", "--version=2.0", "--dry-run", "--target", "api"},
+			expected: map[string]string{
+				"ENV":           "prod",
+				"VERSION":       "2.0",
+				"DRY_RUN":       "true",
+				"DEPLOY_TARGET": "api",
+			},
+		},
+		{
+			given: &taskfile.Vars{
+				Keys:    []string{"VERSION"},
+				Mapping: map[string]taskfile.Var{"VERSION": {Static: "3.0"}},
+			},
+			expected: map[string]string{
+				"ENV":           "staging",
+				"DRY_RUN":       "false",
+				"DEPLOY_TARGET": "",
+			},
+		},
+		{
+			args: []string{},
+			err:  `task: Missing required flag "--version"`,
+		},
+		{
+			args: []string{"--version", "1.0", "--env", "dev"},
+			err:  `task: Invalid value "dev" for flag "--env". Allowed values: staging, prod`,
+		},
+		{
+			args: []string{"--version", "1.0", "--foo"},
+			err:  `task: Unknown flag "--foo". Available flags: --env, --version, --dry-run, --target`,
+		},
+		{
+			args: []string{"--version"},
+			err:  `task: Flag "--version" requires a value`,
+		},
+		{
+			args: []string{"--version", "1.0", "--dry-run=yes"},
+			err:  `task: Flag "--dry-run" expects "true" or "false", got "yes"`,
+		},
+		{
+			args: []string{"1.0"},
+			err:  `task: Unexpected argument "1.0". Expected a flag like "--name"`,
+		},
+	}
+	for _, test := range tests {
+		vars, err := taskfile.ParseFlags(&flags, test.args, test.given)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err)
+			continue
+		}
+		assert.NoError(t, err)
+		actual := make(map[string]string, vars.Len())
+		for k, v := range vars.Mapping {
+			actual[k] = v.Static
+		}
+		assert.Equal(t, test.expected, actual)
+	}
+}
@@ -0,0 +1,60 @@
+package taskfile
+
+import (
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Icon is shown before the name of a task when listing tasks
+type Icon struct {
+	// Emoji is the icon shown on terminals that support Unicode
+	Emoji string
+	// ASCII is shown instead of Emoji on terminals that don't
+	ASCII string
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (i *Icon) UnmarshalYAML(node *yaml.Node) error {
+	var emoji string
+	if err := node.Decode(&emoji); err == nil {
+		i.Emoji = emoji
+		return nil
+	}
+
+	var icon struct {
+		Emoji string
+		ASCII string `yaml:"ascii"`
+	}
+	if err := node.Decode(&icon); err != nil {
+		return err
+	}
+	i.Emoji = icon.Emoji
+	i.ASCII = icon.ASCII
+	return nil
+}
+
+// String returns the icon to show, or an empty string if there's none.
+// When ascii is true, the ASCII fallback is preferred, and an Emoji is only
+// shown if it's made of ASCII characters.
+func (i Icon) String(ascii bool) string {
+	if ascii {
+		if i.ASCII == "" && isASCII(i.Emoji) {
+			return i.Emoji
+		}
+		return i.ASCII
+	}
+	if i.Emoji == "" {
+		return i.ASCII
+	}
+	return i.Emoji
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
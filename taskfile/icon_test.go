@@ -0,0 +1,30 @@
+package taskfile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+func TestIcon(t *testing.T) {
+	tests := []struct {
+		content string
+		unicode string
+		ascii   string
+	}{
+		{"🚀", "🚀", ""},
+		{"'>>'", ">>", ">>"},
+		{"{emoji: 🚀, ascii: '[D]'}", "🚀", "[D]"},
+		{"{ascii: '[D]'}", "[D]", "[D]"},
+	}
+	for _, test := range tests {
+		var icon taskfile.Icon
+		err := yaml.Unmarshal([]byte(test.content), &icon)
+		assert.NoError(t, err)
+		assert.Equal(t, test.unicode, icon.String(false), test.content)
+		assert.Equal(t, test.ascii, icon.String(true), test.content)
+	}
+}
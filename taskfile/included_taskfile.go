@@ -22,6 +22,36 @@ type IncludedTaskfile struct {
 	AdvancedImport bool
 	Vars           *Vars
 	BaseDir        string // The directory from which the including taskfile was loaded; used to resolve relative paths
+	// Checksum pins the content of the included Taskfile, like
+	// "sha256:<hex>", mostly for the ones included from URLs
+	Checksum string
+}
+
+// BrokenInclude is an included Taskfile that couldn't be read, with the
+// error it failed with
+type BrokenInclude struct {
+	Namespace string
+	Err       string
+}
+
+// IncludeNode is a Taskfile as it was read, with the Taskfiles it includes,
+// so what got merged can be shown
+type IncludeNode struct {
+	// Namespace is empty for the entrypoint
+	Namespace string
+	// Taskfile is the path of the Taskfile read, or the declared one if the
+	// include was skipped
+	Taskfile string
+	Optional bool
+	// Origin is the URL a remote Taskfile was fetched from, or the archive
+	// a packaged one was extracted from
+	Origin  string
+	Version string
+	// Err is why the include was skipped, if it was. Optional includes that
+	// don't exist are skipped without one.
+	Skipped  bool
+	Err      string
+	Includes []*IncludeNode
 }
 
 // IncludedTaskfiles represents information about included tasksfiles
@@ -39,9 +69,13 @@ func (tfs *IncludedTaskfiles) UnmarshalYAML(node *yaml.Node) error {
 	// NOTE(@andreynering): on this style of custom unmarsheling,
 	// even number contains the keys, while odd numbers contains
 	// the values.
-	for i := 0; i < len(node.Content); i += 2 {
-		keyNode := node.Content[i]
-		valueNode := node.Content[i+1]
+	content, err := mappingContent(node)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(content); i += 2 {
+		keyNode := content[i]
+		valueNode := content[i+1]
 
 		var v IncludedTaskfile
 		if err := valueNode.Decode(&v); err != nil {
@@ -107,6 +141,7 @@ func (it *IncludedTaskfile) UnmarshalYAML(unmarshal func(interface{}) error) err
 		Internal bool
 		Aliases  []string
 		Vars     *Vars
+		Checksum string
 	}
 	if err := unmarshal(&includedTaskfile); err != nil {
 		return err
@@ -118,6 +153,7 @@ func (it *IncludedTaskfile) UnmarshalYAML(unmarshal func(interface{}) error) err
 	it.Aliases = includedTaskfile.Aliases
 	it.AdvancedImport = true
 	it.Vars = includedTaskfile.Vars
+	it.Checksum = includedTaskfile.Checksum
 	return nil
 }
 
@@ -135,6 +171,7 @@ func (it *IncludedTaskfile) DeepCopy() *IncludedTaskfile {
 		AdvancedImport: it.AdvancedImport,
 		Vars:           it.Vars.DeepCopy(),
 		BaseDir:        it.BaseDir,
+		Checksum:       it.Checksum,
 	}
 }
 
@@ -153,6 +190,7 @@ func (it *IncludedTaskfile) resolvePath(path string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	path = filepathext.Normalize(path)
 
 	if filepath.IsAbs(path) {
 		return path, nil
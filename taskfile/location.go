@@ -0,0 +1,78 @@
+package taskfile
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+)
+
+// Location is the position of a value in a Taskfile
+type Location struct {
+	Taskfile string
+	Line     int
+	Column   int
+}
+
+func locationOf(node *yaml.Node) Location {
+	return Location{Line: node.Line, Column: node.Column}
+}
+
+// IsSet returns true if the location is known
+func (l Location) IsSet() bool {
+	return l.Line > 0
+}
+
+// String returns the location like "Taskfile.yml:42:7"
+func (l Location) String() string {
+	return fmt.Sprintf("%s:%d:%d", filepathext.TryAbsToRel(l.Taskfile), l.Line, l.Column)
+}
+
+// Locations keeps the location of the values of a mapping, by key
+type Locations map[string]Location
+
+// SetTaskfile sets the path of the Taskfile of all locations
+func (ls Locations) SetTaskfile(taskfile string) {
+	for k, l := range ls {
+		l.Taskfile = taskfile
+		ls[k] = l
+	}
+}
+
+// LocationError is an error caused by the value at the given location of a
+// Taskfile
+type LocationError struct {
+	Location Location
+	Err      error
+}
+
+func (err *LocationError) Error() string {
+	return fmt.Sprintf("task: %s: %s", err.Location, strings.TrimPrefix(err.Err.Error(), "task: "))
+}
+
+func (err *LocationError) Unwrap() error {
+	return err.Err
+}
+
+// WithLocation annotates the error with the given location, if it's known
+func WithLocation(err error, l Location) error {
+	if err == nil || !l.IsSet() {
+		return err
+	}
+	return &LocationError{Location: l, Err: err}
+}
+
+// keyLocations returns the locations of the values of a mapping node, by key
+func keyLocations(node *yaml.Node) Locations {
+	content, err := mappingContent(node)
+	if err != nil {
+		return nil
+	}
+	ls := make(Locations, len(content)/2)
+	for i := 0; i < len(content); i += 2 {
+		ls[content[i].Value] = locationOf(resolveAlias(content[i+1]))
+	}
+	return ls
+}
@@ -0,0 +1,113 @@
+package taskfile
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Matrix expands a task into an instance for each combination of the values
+// of some variables. It may be declared as a map of the variables to their
+// values, or with "vars" and "serial".
+type Matrix struct {
+	// Keys are the names of the variables, in the order they were declared
+	Keys   []string
+	Values map[string][]string
+	// Serial runs the instances one after the other instead of in parallel
+	Serial bool
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (m *Matrix) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return errors.New("task: matrix is not a map")
+	}
+
+	varsNode := node
+	if vars := mappingValue(node, "vars"); vars != nil && vars.Kind == yaml.MappingNode {
+		var matrix struct {
+			Vars   yaml.Node
+			Serial bool
+		}
+		if err := node.Decode(&matrix); err != nil {
+			return err
+		}
+		m.Serial = matrix.Serial
+		varsNode = vars
+	}
+
+	content, err := mappingContent(varsNode)
+	if err != nil {
+		return err
+	}
+	m.Keys = nil
+	m.Values = make(map[string][]string, len(content)/2)
+	for i := 0; i < len(content); i += 2 {
+		key := content[i].Value
+		var values []string
+		if err := content[i+1].Decode(&values); err != nil {
+			return fmt.Errorf("task: The values of the matrix var %q must be a list", key)
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("task: The matrix var %q has no values", key)
+		}
+		m.Keys = append(m.Keys, key)
+		m.Values[key] = values
+	}
+	if len(m.Keys) == 0 {
+		return errors.New("task: matrix has no vars")
+	}
+	return nil
+}
+
+// mappingValue returns the value of the given key of a YAML map, if any
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	content, err := mappingContent(node)
+	if err != nil {
+		return nil
+	}
+	for i := 0; i < len(content); i += 2 {
+		if content[i].Value == key {
+			return resolveAlias(content[i+1])
+		}
+	}
+	return nil
+}
+
+// Combinations returns the vars of each instance of the matrix, in the order
+// the values were declared, with the first variable changing the slowest.
+// The variables that are already set by the given vars aren't expanded.
+func (m *Matrix) Combinations(set *Vars) []*Vars {
+	combinations := []*Vars{{}}
+	for _, key := range m.Keys {
+		if set != nil {
+			if _, ok := set.Mapping[key]; ok {
+				continue
+			}
+		}
+		next := make([]*Vars, 0, len(combinations)*len(m.Values[key]))
+		for _, c := range combinations {
+			for _, value := range m.Values[key] {
+				vars := c.DeepCopy()
+				vars.Set(key, Var{Static: value})
+				next = append(next, vars)
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// DeepCopy creates a new instance of Matrix and copies
+// data by value from the source struct.
+func (m *Matrix) DeepCopy() *Matrix {
+	if m == nil {
+		return nil
+	}
+	values := make(map[string][]string, len(m.Values))
+	for k, v := range m.Values {
+		values[k] = deepCopySlice(v)
+	}
+	return &Matrix{Keys: deepCopySlice(m.Keys), Values: values, Serial: m.Serial}
+}
@@ -0,0 +1,51 @@
+package taskfile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+func TestMatrixParse(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected *taskfile.Matrix
+	}{
+		{
+			"{OS: [linux, darwin], GO: [1.21]}",
+			&taskfile.Matrix{Keys: []string{"OS", "GO"}, Values: map[string][]string{"OS": {"linux", "darwin"}, "GO": {"1.21"}}},
+		},
+		{
+			"{vars: {OS: [linux]}, serial: true}",
+			&taskfile.Matrix{Keys: []string{"OS"}, Values: map[string][]string{"OS": {"linux"}}, Serial: true},
+		},
+	}
+	for _, test := range tests {
+		var m taskfile.Matrix
+		assert.NoError(t, yaml.Unmarshal([]byte(test.content), &m))
+		assert.Equal(t, test.expected, &m)
+	}
+
+	var m taskfile.Matrix
+	assert.EqualError(t, yaml.Unmarshal([]byte("{OS: linux}"), &m), `task: The values of the matrix var "OS" must be a list`)
+	assert.EqualError(t, yaml.Unmarshal([]byte("{OS: []}"), &m), `task: The matrix var "OS" has no values`)
+}
+
+func TestMatrixCombinations(t *testing.T) {
+	m := &taskfile.Matrix{Keys: []string{"OS", "ARCH"}, Values: map[string][]string{"OS": {"linux", "darwin"}, "ARCH": {"amd64", "arm64"}}}
+
+	var names []string
+	for _, vars := range m.Combinations(nil) {
+		names = append(names, vars.Mapping["OS"].Static+"/"+vars.Mapping["ARCH"].Static)
+	}
+	assert.Equal(t, []string{"linux/amd64", "linux/arm64", "darwin/amd64", "darwin/arm64"}, names)
+
+	set := &taskfile.Vars{}
+	set.Set("OS", taskfile.Var{Static: "windows"})
+	combinations := m.Combinations(set)
+	assert.Len(t, combinations, 2)
+	assert.Equal(t, []string{"ARCH"}, combinations[0].Keys)
+}
@@ -47,14 +47,27 @@ func Merge(t1, t2 *Taskfile, includedTaskfile *IncludedTaskfile, namespaces ...s
 		// taskfile are marked as internal
 		task.Internal = task.Internal || includedTaskfile.Internal
 
-		// Add namespaces to dependencies, commands and aliases
-		for _, dep := range task.Deps {
-			dep.Task = taskNameWithNamespace(dep.Task, namespaces...)
+		// Keep track of the namespace the task was included under, so
+		// relative references can be resolved later
+		if len(namespaces) > 0 {
+			task.Namespace = strings.Join(append(namespaces, task.Namespace), NamespaceSeparator)
+			task.Namespace = strings.TrimSuffix(task.Namespace, NamespaceSeparator)
+		}
+
+		// Add namespaces to dependencies, commands and aliases.
+		// Relative references (starting with ":") are left untouched.
+		for _, dep := range AllDeps(task.Deps) {
+			if !IsRelativeReference(dep.Task) {
+				dep.Task = taskNameWithNamespace(dep.Task, namespaces...)
+			}
 		}
 		for _, cmd := range task.Cmds {
-			if cmd != nil && cmd.Task != "" {
+			if cmd != nil && cmd.Task != "" && !IsRelativeReference(cmd.Task) {
 				cmd.Task = taskNameWithNamespace(cmd.Task, namespaces...)
 			}
+			if cmd != nil && cmd.Use != "" && !IsRelativeReference(cmd.Use) {
+				cmd.Use = taskNameWithNamespace(cmd.Use, namespaces...)
+			}
 		}
 		for i, alias := range task.Aliases {
 			task.Aliases[i] = taskNameWithNamespace(alias, namespaces...)
@@ -73,6 +86,46 @@ func Merge(t1, t2 *Taskfile, includedTaskfile *IncludedTaskfile, namespaces ...s
 		t1.Tasks[taskNameWithNamespace(k, namespaces...)] = task
 	}
 
+	// Snippets are namespaced like tasks, and so are the tasks they call
+	if len(t2.Snippets) > 0 && t1.Snippets == nil {
+		t1.Snippets = make(map[string][]*Cmd, len(t2.Snippets))
+	}
+	for name, cmds := range t2.Snippets {
+		snippet := make([]*Cmd, 0, len(cmds))
+		for _, cmd := range cmds {
+			if cmd == nil {
+				continue
+			}
+			c := *cmd
+			if c.Task != "" && !IsRelativeReference(c.Task) {
+				c.Task = taskNameWithNamespace(c.Task, namespaces...)
+			}
+			snippet = append(snippet, &c)
+		}
+		t1.Snippets[taskNameWithNamespace(name, namespaces...)] = snippet
+	}
+
+	for _, broken := range t2.BrokenIncludes {
+		t1.BrokenIncludes = append(t1.BrokenIncludes, &BrokenInclude{
+			Namespace: taskNameWithNamespace(broken.Namespace, namespaces...),
+			Err:       broken.Err,
+		})
+	}
+
+	// Params are kept by the namespace the Taskfile was included under, so
+	// they can be explained later
+	if len(namespaces) > 0 && (t2.Params.Len() > 0 || len(t2.IncludedParams) > 0) {
+		if t1.IncludedParams == nil {
+			t1.IncludedParams = make(map[string]*Params)
+		}
+		if t2.Params.Len() > 0 {
+			t1.IncludedParams[strings.Join(namespaces, NamespaceSeparator)] = t2.Params
+		}
+		for ns, params := range t2.IncludedParams {
+			t1.IncludedParams[taskNameWithNamespace(ns, namespaces...)] = params
+		}
+	}
+
 	return nil
 }
 
@@ -82,3 +135,34 @@ func taskNameWithNamespace(taskName string, namespaces ...string) string {
 	}
 	return strings.Join(append(namespaces, taskName), NamespaceSeparator)
 }
+
+// SiblingPrefix starts the name of a task called by a task of the same
+// Taskfile, whatever the namespace it was included under, like ".:build"
+const SiblingPrefix = "." + NamespaceSeparator
+
+// IsRelativeReference returns true if the given task name is a relative
+// reference, that is, a ".:sibling", a ":root" or a "::root" reference.
+func IsRelativeReference(taskName string) bool {
+	return strings.HasPrefix(taskName, SiblingPrefix) || strings.HasPrefix(taskName, NamespaceSeparator)
+}
+
+// ResolveReference resolves a task reference made by a task included under
+// the given namespace. ".:task" references a task in the same namespace as
+// the caller, while ":task" and "::task" reference a task of the root
+// Taskfile. Snippets are referenced the same way.
+func ResolveReference(taskName, namespace string) string {
+	switch {
+	case strings.HasPrefix(taskName, SiblingPrefix):
+		taskName = strings.TrimPrefix(taskName, SiblingPrefix)
+		if namespace == "" {
+			return taskName
+		}
+		return taskNameWithNamespace(taskName, namespace)
+	case strings.HasPrefix(taskName, NamespaceSeparator+NamespaceSeparator):
+		return strings.TrimPrefix(taskName, NamespaceSeparator+NamespaceSeparator)
+	case strings.HasPrefix(taskName, NamespaceSeparator):
+		return strings.TrimPrefix(taskName, NamespaceSeparator)
+	default:
+		return taskName
+	}
+}
@@ -0,0 +1,38 @@
+package taskfile
+
+import "gopkg.in/yaml.v3"
+
+// Nix is a Nix development shell the commands of a task are run inside of
+type Nix struct {
+	// Shell is a flake output, like ".#devshell", run with "nix develop",
+	// or a file, like "shell.nix", run with "nix-shell". When empty, the
+	// default development shell of the flake in the task directory is used.
+	Shell string
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (n *Nix) UnmarshalYAML(node *yaml.Node) error {
+	var shell string
+	if err := node.Decode(&shell); err == nil {
+		n.Shell = shell
+		return nil
+	}
+
+	var nix struct {
+		Shell string
+	}
+	if err := node.Decode(&nix); err != nil {
+		return err
+	}
+	n.Shell = nix.Shell
+	return nil
+}
+
+// DeepCopy creates a new instance of Nix and copies
+// data by value from the source struct.
+func (n *Nix) DeepCopy() *Nix {
+	if n == nil {
+		return nil
+	}
+	return &Nix{Shell: n.Shell}
+}
@@ -0,0 +1,71 @@
+package taskfile
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidNotification is returned when a notification doesn't set exactly
+// one of "slack" and "url"
+var ErrInvalidNotification = errors.New(`task: Notifications must set exactly one of "slack" and "url"`)
+
+// Notification is a webhook called when a run finishes
+type Notification struct {
+	// Slack is the URL of a Slack incoming webhook
+	Slack string
+	// URL receives a POST request with the payload
+	URL string
+	// Payload is the body of the request. Defaults to a JSON summary of the
+	// run, or to a message for Slack.
+	Payload string
+	Headers map[string]string
+	// On are the results of the run that trigger the notification:
+	// "success" and "failure". Defaults to both.
+	On []string
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (n *Notification) UnmarshalYAML(node *yaml.Node) error {
+	var notification struct {
+		Slack   string
+		URL     string `yaml:"url"`
+		Payload string
+		Headers map[string]string
+		On      []string
+	}
+	if err := node.Decode(&notification); err != nil {
+		return err
+	}
+
+	if (notification.Slack == "") == (notification.URL == "") {
+		return ErrInvalidNotification
+	}
+	for _, on := range notification.On {
+		if on != "success" && on != "failure" {
+			return fmt.Errorf(`task: Invalid notification trigger "%s". Available options: "success" and "failure"`, on)
+		}
+	}
+
+	n.Slack = notification.Slack
+	n.URL = notification.URL
+	n.Payload = notification.Payload
+	n.Headers = notification.Headers
+	n.On = notification.On
+	return nil
+}
+
+// Triggers reports whether the notification is sent for a run with the given
+// result
+func (n *Notification) Triggers(failed bool) bool {
+	if len(n.On) == 0 {
+		return true
+	}
+	for _, on := range n.On {
+		if (on == "failure") == failed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package taskfile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+func TestNotificationParse(t *testing.T) {
+	var n taskfile.Notification
+	err := yaml.Unmarshal([]byte(`
+url: https://example.com/hook
+on: [failure]
+headers:
+  Authorization: Bearer token
+payload: '{"status": "{{.STATUS}}"}'
+`), &n)
+	assert.NoError(t, err)
+	assert.Equal(t, taskfile.Notification{
+		URL:     "https://example.com/hook",
+		Payload: `{"status": "{{.STATUS}}"}`,
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		On:      []string{"failure"},
+	}, n)
+	assert.True(t, n.Triggers(true))
+	assert.False(t, n.Triggers(false))
+
+	n = taskfile.Notification{}
+	assert.NoError(t, yaml.Unmarshal([]byte("slack: https://hooks.slack.com/services/x"), &n))
+	assert.True(t, n.Triggers(true))
+	assert.True(t, n.Triggers(false))
+
+	assert.ErrorIs(t, yaml.Unmarshal([]byte("{}"), &n), taskfile.ErrInvalidNotification)
+	assert.ErrorIs(t, yaml.Unmarshal([]byte("{slack: a, url: b}"), &n), taskfile.ErrInvalidNotification)
+	assert.EqualError(t, yaml.Unmarshal([]byte("{url: a, on: [always]}"), &n), `task: Invalid notification trigger "always". Available options: "success" and "failure"`)
+}
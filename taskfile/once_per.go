@@ -0,0 +1,50 @@
+package taskfile
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OncePer are the scopes a task runs at most once in, like "day". It may be
+// declared as a single scope or as a list of scopes, in which case the task
+// runs again when any of them changes.
+type OncePer []string
+
+// The scopes of once_per
+const (
+	OncePerDay    = "day"
+	OncePerCommit = "commit"
+	OncePerVars   = "vars"
+)
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (op *OncePer) UnmarshalYAML(node *yaml.Node) error {
+	var scopes []string
+	var scope string
+	if err := node.Decode(&scope); err == nil {
+		scopes = []string{scope}
+	} else if err := node.Decode(&scopes); err != nil {
+		return err
+	}
+
+	for _, scope := range scopes {
+		switch scope {
+		case OncePerDay, OncePerCommit, OncePerVars:
+		default:
+			return fmt.Errorf(`task: invalid once_per "%s". Available options: "%s", "%s" and "%s"`, scope, OncePerDay, OncePerCommit, OncePerVars)
+		}
+	}
+	*op = scopes
+	return nil
+}
+
+// Has returns true if the task runs once per the given scope
+func (op OncePer) Has(scope string) bool {
+	for _, s := range op {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
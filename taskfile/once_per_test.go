@@ -0,0 +1,28 @@
+package taskfile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+func TestOncePerParse(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected taskfile.OncePer
+	}{
+		{"day", taskfile.OncePer{"day"}},
+		{"[commit, vars]", taskfile.OncePer{"commit", "vars"}},
+	}
+	for _, test := range tests {
+		var op taskfile.OncePer
+		assert.NoError(t, yaml.Unmarshal([]byte(test.content), &op))
+		assert.Equal(t, test.expected, op)
+	}
+
+	var op taskfile.OncePer
+	assert.EqualError(t, yaml.Unmarshal([]byte("[day, week]"), &op), `task: invalid once_per "week". Available options: "day", "commit" and "vars"`)
+}
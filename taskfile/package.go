@@ -0,0 +1,10 @@
+package taskfile
+
+// Package describes how a Taskfile is bundled by "task --package"
+type Package struct {
+	Name    string
+	Version string
+	// Files are globs of the files bundled along with the ones the Taskfile
+	// references
+	Files []string
+}
@@ -0,0 +1,179 @@
+package taskfile
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// Params is an ordered map of the variables a Taskfile expects to be given
+// when included
+type Params struct {
+	Keys    []string
+	Mapping map[string]*Param
+}
+
+// Param is a variable a Taskfile expects to be given when included
+type Param struct {
+	Desc     string
+	Type     string
+	Default  string
+	Enum     []string
+	Required bool
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (ps *Params) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return errors.New("task: params is not a map")
+	}
+
+	content, err := mappingContent(node)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(content); i += 2 {
+		keyNode := content[i]
+		valueNode := content[i+1]
+
+		var p Param
+		if err := valueNode.Decode(&p); err != nil {
+			return err
+		}
+		switch p.Type {
+		case "", "string", "bool", "int":
+		default:
+			return fmt.Errorf(`task: invalid type %q for param "%s". Available options: "string", "bool" and "int"`, p.Type, keyNode.Value)
+		}
+		ps.Set(keyNode.Value, &p)
+	}
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (p *Param) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&p.Desc)
+	}
+
+	var param struct {
+		Desc     string
+		Type     string
+		Default  string
+		Enum     []string
+		Required bool
+	}
+	if err := node.Decode(&param); err != nil {
+		return err
+	}
+	p.Desc = param.Desc
+	p.Type = param.Type
+	p.Default = param.Default
+	p.Enum = param.Enum
+	p.Required = param.Required
+	return nil
+}
+
+// Set sets a value to a given key
+func (ps *Params) Set(key string, p *Param) {
+	if ps.Mapping == nil {
+		ps.Mapping = make(map[string]*Param, 1)
+	}
+	if !slices.Contains(ps.Keys, key) {
+		ps.Keys = append(ps.Keys, key)
+	}
+	ps.Mapping[key] = p
+}
+
+// Range allows you to loop into the params in its right order
+func (ps *Params) Range(yield func(key string, p *Param) error) error {
+	if ps == nil {
+		return nil
+	}
+	for _, k := range ps.Keys {
+		if err := yield(k, ps.Mapping[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Len returns the size of the map
+func (ps *Params) Len() int {
+	if ps == nil {
+		return 0
+	}
+	return len(ps.Keys)
+}
+
+// Apply checks the vars given to a Taskfile included under namespace against
+// its params, and returns them along with the defaults of the params that
+// weren't given. Only values known at this point are checked: templated and
+// dynamic vars are left to be resolved when tasks run.
+func (ps *Params) Apply(namespace string, vars *Vars) (*Vars, error) {
+	if vars == nil {
+		vars = &Vars{}
+	}
+	result := &Vars{}
+	err := ps.Range(func(name string, p *Param) error {
+		v, ok := vars.Mapping[name]
+		if !ok {
+			if p.Required {
+				return fmt.Errorf(`task: Included Taskfile "%s" requires the var "%s"`, namespace, name)
+			}
+			if p.Default != "" {
+				result.Set(name, Var{Static: p.Default})
+			}
+			return nil
+		}
+		if v.Sh != "" || v.Live != nil || strings.Contains(v.Static, "{{") {
+			return nil
+		}
+		return p.check(namespace, name, v.Static)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Merge(vars)
+	return result, nil
+}
+
+func (p *Param) check(namespace, name, value string) error {
+	var err error
+	switch p.Type {
+	case "bool":
+		_, err = strconv.ParseBool(value)
+	case "int":
+		_, err = strconv.Atoi(value)
+	}
+	if err != nil {
+		return fmt.Errorf(`task: Var "%s" of included Taskfile "%s" should be a %s, but got %q`, name, namespace, p.Type, value)
+	}
+	if len(p.Enum) > 0 && !slices.Contains(p.Enum, value) {
+		return fmt.Errorf(`task: Var "%s" of included Taskfile "%s" should be one of [%s], but got %q`, name, namespace, strings.Join(p.Enum, ", "), value)
+	}
+	return nil
+}
+
+// DeepCopy creates a new instance of Params and copies
+// data by value from the source struct.
+func (ps *Params) DeepCopy() *Params {
+	if ps == nil {
+		return nil
+	}
+	c := &Params{Keys: deepCopySlice(ps.Keys), Mapping: make(map[string]*Param, len(ps.Mapping))}
+	for k, p := range ps.Mapping {
+		c.Mapping[k] = &Param{
+			Desc:     p.Desc,
+			Type:     p.Type,
+			Default:  p.Default,
+			Enum:     deepCopySlice(p.Enum),
+			Required: p.Required,
+		}
+	}
+	return c
+}
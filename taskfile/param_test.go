@@ -0,0 +1,66 @@
+package taskfile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+const yamlParams = `
+REGISTRY:
+  desc: Where the images are pushed to
+  required: true
+PLATFORM:
+  default: linux
+  enum: [linux, windows]
+PUSH:
+  type: bool
+  default: "false"
+RETRIES: The number of retries
+`
+
+func TestParamsParse(t *testing.T) {
+	var params taskfile.Params
+	assert.NoError(t, yaml.Unmarshal([]byte(yamlParams), &params))
+	assert.Equal(t, []string{"REGISTRY", "PLATFORM", "PUSH", "RETRIES"}, params.Keys)
+	assert.Equal(t, &taskfile.Param{
+		Desc:     "Where the images are pushed to",
+		Required: true,
+	}, params.Mapping["REGISTRY"])
+	assert.Equal(t, &taskfile.Param{Desc: "The number of retries"}, params.Mapping["RETRIES"])
+
+	var invalid taskfile.Params
+	assert.EqualError(
+		t,
+		yaml.Unmarshal([]byte("FOO:\n  type: float\n"), &invalid),
+		`task: invalid type "float" for param "FOO". Available options: "string", "bool" and "int"`,
+	)
+}
+
+func TestParamsApply(t *testing.T) {
+	var params taskfile.Params
+	assert.NoError(t, yaml.Unmarshal([]byte(yamlParams), &params))
+
+	_, err := params.Apply("images", nil)
+	assert.EqualError(t, err, `task: Included Taskfile "images" requires the var "REGISTRY"`)
+
+	vars := &taskfile.Vars{}
+	vars.Set("REGISTRY", taskfile.Var{Static: "ghcr.io"})
+	vars.Set("PLATFORM", taskfile.Var{Static: "{{.OS}}"})
+	applied, err := params.Apply("images", vars)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PUSH", "REGISTRY", "PLATFORM"}, applied.Keys)
+	assert.Equal(t, "false", applied.Mapping["PUSH"].Static)
+
+	vars.Set("PUSH", taskfile.Var{Static: "maybe"})
+	_, err = params.Apply("images", vars)
+	assert.EqualError(t, err, `task: Var "PUSH" of included Taskfile "images" should be a bool, but got "maybe"`)
+
+	vars.Set("PUSH", taskfile.Var{Static: "true"})
+	vars.Set("PLATFORM", taskfile.Var{Static: "darwin"})
+	_, err = params.Apply("images", vars)
+	assert.EqualError(t, err, `task: Var "PLATFORM" of included Taskfile "images" should be one of [linux, windows], but got "darwin"`)
+}
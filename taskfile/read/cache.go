@@ -0,0 +1,323 @@
+package read
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-zglob"
+
+	"github.com/go-task/task/v3/internal/execext"
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/taskfile"
+
+	"golang.org/x/exp/slices"
+)
+
+// identifierRegexp matches the names of the variables a template may use
+var identifierRegexp = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// cacheFormat should be bumped whenever the format of the cached Taskfile
+// changes in an incompatible way
+const cacheFormat = 39
+
+// cachedFile is a file (or directory) consulted while reading a Taskfile.
+// Only the existence of directories is checked, since their modification
+// time changes whenever any file is written into them.
+type cachedFile struct {
+	Path    string
+	Exists  bool
+	Dir     bool
+	ModTime time.Time
+	Size    int64
+}
+
+// cachedExpansion is a path with shell expansions (like "~" or "$HOME")
+// resolved while reading a Taskfile
+type cachedExpansion struct {
+	Raw      string
+	Expanded string
+}
+
+// cachedGlob is a glob of included Taskfiles, with the paths it matched
+type cachedGlob struct {
+	Pattern string
+	Matches []string
+}
+
+// cachedEnv is an environment variable read by a "!env" tag or that may be
+// referenced by a templated path
+type cachedEnv struct {
+	Name  string
+	Value string
+	Set   bool
+}
+
+type taskfileCache struct {
+	Format     int
+	Dir        string
+	Files      []cachedFile
+	Expansions []cachedExpansion
+	Env        []cachedEnv
+	Globs      []cachedGlob
+	Taskfile   *taskfile.Taskfile
+}
+
+// fileRecorder keeps every file consulted and every path expanded while
+// reading a Taskfile, as well as the environment variables read by it, so a
+// cached result can be checked for staleness.
+// All methods are safe to call on a nil fileRecorder, in which case nothing
+// is recorded.
+type fileRecorder struct {
+	mutex      sync.Mutex
+	files      map[string]cachedFile
+	expansions map[string]string
+	env        map[string]cachedEnv
+	globs      map[string][]string
+}
+
+// stat works like os.Stat, but records the result
+func (r *fileRecorder) stat(path string) (os.FileInfo, error) {
+	fi, err := os.Stat(filepathext.LongPath(path))
+	if r == nil {
+		return fi, err
+	}
+
+	f := cachedFile{Path: path}
+	switch {
+	case err != nil:
+	case fi.IsDir():
+		f.Exists = true
+		f.Dir = true
+	default:
+		f.Exists = true
+		f.ModTime = fi.ModTime()
+		f.Size = fi.Size()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.files == nil {
+		r.files = make(map[string]cachedFile)
+	}
+	r.files[path] = f
+	return fi, err
+}
+
+// expand records a path that depends on the environment
+func (r *fileRecorder) expand(raw string) {
+	if r == nil || !strings.ContainsAny(raw, "$~") {
+		return
+	}
+	expanded, err := execext.Expand(raw)
+	if err != nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.expansions == nil {
+		r.expansions = make(map[string]string)
+	}
+	r.expansions[raw] = expanded
+}
+
+// glob returns the sorted paths matching the pattern, recording them
+func (r *fileRecorder) glob(pattern string) ([]string, error) {
+	matches, err := globPaths(pattern)
+	if r == nil || err != nil {
+		return matches, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.globs == nil {
+		r.globs = make(map[string][]string)
+	}
+	r.globs[pattern] = matches
+	return matches, nil
+}
+
+func globPaths(pattern string) ([]string, error) {
+	matches, err := zglob.GlobFollowSymlinks(pattern)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// lookupEnv works like os.LookupEnv, but records the result
+func (r *fileRecorder) lookupEnv(name string) (string, bool) {
+	value, set := os.LookupEnv(name)
+	if r == nil {
+		return value, set
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.env == nil {
+		r.env = make(map[string]cachedEnv)
+	}
+	r.env[name] = cachedEnv{Name: name, Value: value, Set: set}
+	return value, set
+}
+
+// template records the environment variables a template may reference
+func (r *fileRecorder) template(raw string) {
+	if r == nil || !strings.Contains(raw, "{{") {
+		return
+	}
+	for _, name := range identifierRegexp.FindAllString(raw, -1) {
+		r.lookupEnv(name)
+	}
+}
+
+// CachedTaskfile works like Taskfile, but stores the merged result in the
+// given cache directory, and reuses it on following calls as long as none
+// of the files (including the missing ones) consulted to build it changed.
+// Problems with the cache itself are not errors, the Taskfile is just read
+// again in this case.
+func CachedTaskfile(readerNode *ReaderNode, cacheDir string) (*taskfile.Taskfile, string, error) {
+	if readerNode.Dir == "" {
+		d, err := os.Getwd()
+		if err != nil {
+			return nil, "", err
+		}
+		readerNode.Dir = d
+	}
+	dir, err := filepath.Abs(readerNode.Dir)
+	if err != nil {
+		return Taskfile(readerNode)
+	}
+	cacheFile := filepath.Join(cacheDir, cacheKey(dir, readerNode.Entrypoint)+".gob")
+
+	if c := loadCache(cacheFile); c != nil {
+		readerNode.Logger.Debugf("using cached Taskfile %s", cacheFile)
+		return c.Taskfile, c.Dir, nil
+	}
+
+	readerNode.files = &fileRecorder{}
+	t, taskfileDir, err := Taskfile(readerNode)
+	if err != nil {
+		return nil, "", err
+	}
+	// A Taskfile read leniently is only cached if nothing was skipped
+	if len(t.BrokenIncludes) > 0 {
+		return t, taskfileDir, nil
+	}
+	if err := saveCache(cacheFile, taskfileDir, readerNode.files, t); err != nil {
+		readerNode.Logger.Debugf("could not cache Taskfile: %v", err)
+	}
+	return t, taskfileDir, nil
+}
+
+func cacheKey(dir, entrypoint string) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%s\x00%s", cacheFormat, dir, entrypoint)))
+	return fmt.Sprintf("%x", h[:16])
+}
+
+// loadCache returns the cached Taskfile, or nil if it's missing or stale
+func loadCache(cacheFile string) *taskfileCache {
+	f, err := os.Open(cacheFile)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var c taskfileCache
+	if err := gob.NewDecoder(f).Decode(&c); err != nil || c.Format != cacheFormat || c.Taskfile == nil {
+		return nil
+	}
+
+	for _, cf := range c.Files {
+		fi, err := os.Stat(filepathext.LongPath(cf.Path))
+		if (err == nil) != cf.Exists {
+			return nil
+		}
+		if err == nil && fi.IsDir() != cf.Dir {
+			return nil
+		}
+		if err == nil && !cf.Dir && (!fi.ModTime().Equal(cf.ModTime) || fi.Size() != cf.Size) {
+			return nil
+		}
+	}
+	for _, ce := range c.Expansions {
+		if expanded, err := execext.Expand(ce.Raw); err != nil || expanded != ce.Expanded {
+			return nil
+		}
+	}
+	for _, ce := range c.Env {
+		if value, set := os.LookupEnv(ce.Name); value != ce.Value || set != ce.Set {
+			return nil
+		}
+	}
+	for _, cg := range c.Globs {
+		if matches, err := globPaths(cg.Pattern); err != nil || !slices.Equal(matches, cg.Matches) {
+			return nil
+		}
+	}
+
+	// gob omits empty values, so restore what the YAML decoding guarantees
+	if c.Taskfile.Vars == nil {
+		c.Taskfile.Vars = &taskfile.Vars{}
+	}
+	if c.Taskfile.Env == nil {
+		c.Taskfile.Env = &taskfile.Vars{}
+	}
+	if c.Taskfile.Tasks == nil {
+		c.Taskfile.Tasks = taskfile.Tasks{}
+	}
+	return &c
+}
+
+func saveCache(cacheFile, dir string, r *fileRecorder, t *taskfile.Taskfile) error {
+	c := taskfileCache{
+		Format:   cacheFormat,
+		Dir:      dir,
+		Taskfile: t,
+	}
+	for _, f := range r.files {
+		c.Files = append(c.Files, f)
+	}
+	sort.Slice(c.Files, func(i, j int) bool { return c.Files[i].Path < c.Files[j].Path })
+	for raw, expanded := range r.expansions {
+		c.Expansions = append(c.Expansions, cachedExpansion{Raw: raw, Expanded: expanded})
+	}
+	for _, e := range r.env {
+		c.Env = append(c.Env, e)
+	}
+	for pattern, matches := range r.globs {
+		c.Globs = append(c.Globs, cachedGlob{Pattern: pattern, Matches: matches})
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first, so concurrent runs never read a
+	// partially written cache
+	f, err := os.CreateTemp(filepath.Dir(cacheFile), filepath.Base(cacheFile)+".*")
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(f).Encode(&c); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), cacheFile)
+}
@@ -2,18 +2,31 @@ package read
 
 import (
 	"os"
+	"sort"
 
 	"github.com/joho/godotenv"
 
 	"github.com/go-task/task/v3/internal/compiler"
+	"github.com/go-task/task/v3/internal/execext"
 	"github.com/go-task/task/v3/internal/filepathext"
 	"github.com/go-task/task/v3/internal/templater"
 	"github.com/go-task/task/v3/taskfile"
 )
 
-func Dotenv(c compiler.Compiler, tf *taskfile.Taskfile, dir string) (*taskfile.Vars, error) {
+// DotenvValues are the values read from the dotenv files of a Taskfile
+type DotenvValues struct {
+	Env *taskfile.Vars
+	// Overrides are the keys whose values should replace the environment
+	// variables already set
+	Overrides map[string]bool
+	// Files are the paths of the dotenv files, including the missing ones
+	Files []string
+}
+
+// Dotenv reads the dotenv files of the Taskfile
+func Dotenv(c compiler.Compiler, tf *taskfile.Taskfile, dir string) (*DotenvValues, error) {
 	if len(tf.Dotenv) == 0 {
-		return nil, nil
+		return &DotenvValues{Env: &taskfile.Vars{}}, nil
 	}
 
 	vars, err := c.GetTaskfileVariables()
@@ -21,31 +34,106 @@ func Dotenv(c compiler.Compiler, tf *taskfile.Taskfile, dir string) (*taskfile.V
 		return nil, err
 	}
 
-	env := &taskfile.Vars{}
+	return readDotenvFiles(nil, tf, vars, dir)
+}
 
-	tr := templater.Templater{Vars: vars, RemoveNoValue: true}
+// readDotenvFiles reads the dotenv files of the Taskfile in order. Each path
+// is templated with the given variables, plus the values of the files read
+// before it, which only override the given variables when the file is set to
+// override the environment. Values of the files read first take precedence.
+func readDotenvFiles(files *fileRecorder, tf *taskfile.Taskfile, vars *taskfile.Vars, dir string) (*DotenvValues, error) {
+	values := &DotenvValues{Env: &taskfile.Vars{}}
+	vars = vars.DeepCopy()
 
-	for _, dotEnvPath := range tf.Dotenv {
-		dotEnvPath = tr.Replace(dotEnvPath)
+	for _, dotenv := range tf.Dotenv {
+		override := tf.DotenvOverride
+		if dotenv.Override != nil {
+			override = *dotenv.Override
+		}
+
+		files.template(dotenv.Path)
+		tr := templater.Templater{Vars: vars, RemoveNoValue: true}
+		dotEnvPath := tr.Replace(dotenv.Path)
+		if err := tr.Err(); err != nil {
+			return nil, err
+		}
 		if dotEnvPath == "" {
 			continue
 		}
-		dotEnvPath = filepathext.SmartJoin(dir, dotEnvPath)
+		files.expand(dotEnvPath)
+		expanded, err := execext.Expand(dotEnvPath)
+		if err != nil {
+			return nil, err
+		}
+		dotEnvPath = filepathext.SmartJoin(dir, expanded)
+		values.Files = append(values.Files, dotEnvPath)
 
-		if _, err := os.Stat(dotEnvPath); os.IsNotExist(err) {
+		if _, err := files.stat(dotEnvPath); os.IsNotExist(err) {
 			continue
 		}
 
-		envs, err := godotenv.Read(dotEnvPath)
+		envs, err := godotenv.Read(filepathext.LongPath(dotEnvPath))
 		if err != nil {
 			return nil, err
 		}
-		for key, value := range envs {
-			if _, ok := env.Mapping[key]; !ok {
-				env.Set(key, taskfile.Var{Static: value})
+		keys := make([]string, 0, len(envs))
+		for key := range envs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if _, ok := values.Env.Mapping[key]; ok {
+				continue
+			}
+			value := taskfile.Var{Static: envs[key]}
+			values.Env.Set(key, value)
+			if override {
+				if values.Overrides == nil {
+					values.Overrides = make(map[string]bool)
+				}
+				values.Overrides[key] = true
+			}
+			if _, ok := vars.Mapping[key]; !ok || override {
+				vars.Set(key, value)
 			}
 		}
 	}
 
+	return values, nil
+}
+
+// EnvFiles reads the given dotenv files of a task, relative to its
+// directory. Missing files are ignored, and values of the files read first
+// take precedence.
+func EnvFiles(paths []string, dir string) (*taskfile.Vars, error) {
+	env := &taskfile.Vars{}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		expanded, err := execext.Expand(path)
+		if err != nil {
+			return nil, err
+		}
+		path = filepathext.SmartJoin(dir, expanded)
+		if _, err := os.Stat(filepathext.LongPath(path)); os.IsNotExist(err) {
+			continue
+		}
+
+		envs, err := godotenv.Read(filepathext.LongPath(path))
+		if err != nil {
+			return nil, err
+		}
+		keys := make([]string, 0, len(envs))
+		for key := range envs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if _, ok := env.Mapping[key]; !ok {
+				env.Set(key, taskfile.Var{Static: envs[key]})
+			}
+		}
+	}
 	return env, nil
 }
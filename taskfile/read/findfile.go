@@ -9,7 +9,7 @@ import (
 // find a configuration file with the default name.
 // This should be called when an explicit file is not passed in to determine
 // the full path to the relevant config file.
-func searchForFile(dirPath string, fileName string) (fullPath string, found bool, err error) {
+func searchForFile(files *fileRecorder, dirPath string, fileName string) (fullPath string, found bool, err error) {
 
 	if dirPath == "" {
 		dirPath, err = os.Getwd()
@@ -21,7 +21,7 @@ func searchForFile(dirPath string, fileName string) (fullPath string, found bool
 	lastFound := ""
 	prevPath := ""
 	for dirPath != prevPath {
-		fullPath, found, err = findFileInDir(fileName, dirPath)
+		fullPath, found, err = findFileInDir(files, fileName, dirPath)
 		if found {
 			lastFound = fullPath
 		}
@@ -34,9 +34,9 @@ func searchForFile(dirPath string, fileName string) (fullPath string, found bool
 	return lastFound, lastFound != "", nil
 }
 
-func findFileInDir(fileName string, dirPath string) (fullPath string, found bool, err error) {
+func findFileInDir(files *fileRecorder, fileName string, dirPath string) (fullPath string, found bool, err error) {
 
-	fullPath, found, err = findFileInDirByName(dirPath, fileName)
+	fullPath, found, err = findFileInDirByName(files, dirPath, fileName)
 	if err != nil || found {
 		return fullPath, found, err
 	}
@@ -44,10 +44,10 @@ func findFileInDir(fileName string, dirPath string) (fullPath string, found bool
 	return "", false, nil
 }
 
-func findFileInDirByName(dirPath, fileName string) (fullPath string, found bool, err error) {
+func findFileInDirByName(files *fileRecorder, dirPath, fileName string) (fullPath string, found bool, err error) {
 
 	fullPath = filepath.Join(dirPath, fileName)
-	if _, err := os.Stat(fullPath); err != nil {
+	if _, err := files.stat(fullPath); err != nil {
 		if os.IsNotExist(err) {
 			return "", false, nil
 		}
@@ -0,0 +1,72 @@
+package read
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+)
+
+// TaskfileInfo is what's known about a Taskfile without fully reading it
+type TaskfileInfo struct {
+	// Taskfile is the path of the Taskfile, empty if none was found
+	Taskfile string `json:"taskfile,omitempty"`
+	// Tasks is the number of tasks of the Taskfile, not counting the ones of
+	// its includes
+	Tasks int `json:"tasks"`
+	// Dirty is whether the cached merged Taskfile is missing or stale. It's
+	// nil when no cache directory is given.
+	Dirty *bool `json:"dirty,omitempty"`
+}
+
+// Info finds the Taskfile the same way Taskfile does, but only counts its
+// tasks, without decoding them or reading the Taskfiles it includes, so it's
+// fast enough to run on every shell prompt. The cached merged Taskfile is
+// checked if cacheDir is given.
+func Info(dir, entrypoint, cacheDir string) (*TaskfileInfo, error) {
+	if dir == "" {
+		d, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		dir = d
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var path string
+	if entrypoint == "" {
+		found := false
+		if path, found, err = searchForFile(nil, dir, "Taskfile.yml"); err != nil || !found {
+			return &TaskfileInfo{}, err
+		}
+	} else {
+		path = filepathext.SmartJoin(dir, entrypoint)
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &TaskfileInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var partial struct {
+		Tasks yaml.Node
+	}
+	if err := yaml.Unmarshal(data, &partial); err != nil {
+		return nil, err
+	}
+
+	info := &TaskfileInfo{Taskfile: path, Tasks: len(partial.Tasks.Content) / 2}
+	if cacheDir != "" {
+		// The cache is keyed by what was given, like CachedTaskfile does
+		dirty := loadCache(filepath.Join(cacheDir, cacheKey(dir, entrypoint)+".gob")) == nil
+		info.Dirty = &dirty
+	}
+	return info, nil
+}
@@ -0,0 +1,108 @@
+package read
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strings"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// readMakefile reads the top-level targets of a Makefile as tasks that run
+// "make" with the target. Descriptions are taken from a "## comment" at the
+// end of the line of the target, or on the line before it, following the
+// usual convention of self-documenting Makefiles, so only the documented
+// targets are listed by --list.
+func readMakefile(file string) (*taskfile.Taskfile, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	t := taskfile.Taskfile{
+		Version: "3",
+		Vars:    &taskfile.Vars{},
+		Env:     &taskfile.Vars{},
+		Tasks:   taskfile.Tasks{},
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	var (
+		line    int
+		comment string
+		define  bool
+	)
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+
+		// Multi-line variables may contain anything, including colons
+		if define {
+			define = strings.TrimSpace(text) != "endef"
+			continue
+		}
+		if strings.HasPrefix(text, "define ") {
+			define = true
+			continue
+		}
+
+		if strings.HasPrefix(text, "##") {
+			comment = strings.TrimSpace(strings.TrimPrefix(text, "##"))
+			continue
+		}
+		desc := comment
+		comment = ""
+
+		names, rest, ok := makefileTargets(text)
+		if !ok {
+			continue
+		}
+		if _, trailing, ok := strings.Cut(rest, "##"); ok {
+			desc = strings.TrimSpace(trailing)
+		}
+		for _, name := range names {
+			if _, ok := t.Tasks[name]; ok {
+				continue
+			}
+			t.Tasks[name] = &taskfile.Task{
+				Taskfile: file,
+				Desc:     desc,
+				Cmds: []*taskfile.Cmd{
+					{Cmd: "make " + name},
+				},
+				Location: taskfile.Location{Taskfile: file, Line: line, Column: 1},
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// makefileTargets returns the targets of a rule of a Makefile and what
+// follows the colon, if the line is one. Recipes, variable assignments and
+// special, hidden or pattern targets are ignored.
+func makefileTargets(text string) ([]string, string, bool) {
+	if text == "" || text[0] == '\t' || text[0] == ' ' || text[0] == '#' {
+		return nil, "", false
+	}
+	before, rest, ok := strings.Cut(text, ":")
+	if !ok || strings.ContainsAny(before, "=$%") {
+		return nil, "", false
+	}
+	// Double-colon rules, but not "::=" assignments
+	rest = strings.TrimPrefix(rest, ":")
+	if strings.HasPrefix(rest, "=") {
+		return nil, "", false
+	}
+
+	var names []string
+	for _, name := range strings.Fields(before) {
+		if !strings.HasPrefix(name, ".") {
+			names = append(names, name)
+		}
+	}
+	return names, rest, len(names) > 0
+}
@@ -0,0 +1,76 @@
+package read
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/internal/execext"
+	"github.com/go-task/task/v3/internal/filepathext"
+)
+
+const (
+	envTag  = "!env"
+	fileTag = "!file"
+)
+
+// resolveTags replaces the values tagged with "!env NAME" by the value of the
+// environment variable, and the ones tagged with "!file PATH" by the contents
+// of the file, relative to the directory of the Taskfile. Like with dynamic
+// variables, a single trailing newline is trimmed from the contents.
+func resolveTags(files *fileRecorder, node *yaml.Node, dir string) error {
+	switch node.Kind {
+	case yaml.AliasNode:
+		// The anchored node is resolved where it's defined
+		return nil
+	case yaml.ScalarNode:
+	default:
+		if node.Tag == envTag || node.Tag == fileTag {
+			return fmt.Errorf("line %d: the %s tag can only be used on strings", node.Line, node.Tag)
+		}
+		for _, n := range node.Content {
+			if err := resolveTags(files, n, dir); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var value string
+	switch node.Tag {
+	case envTag:
+		name := strings.TrimSpace(node.Value)
+		if name == "" {
+			return fmt.Errorf("line %d: the %s tag requires the name of an environment variable", node.Line, envTag)
+		}
+		value, _ = files.lookupEnv(name)
+
+	case fileTag:
+		raw := strings.TrimSpace(node.Value)
+		if raw == "" {
+			return fmt.Errorf("line %d: the %s tag requires the path of a file", node.Line, fileTag)
+		}
+		files.expand(raw)
+		path, err := execext.Expand(raw)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		path = filepathext.SmartJoin(dir, path)
+		_, _ = files.stat(path)
+		b, err := os.ReadFile(filepathext.LongPath(path))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		value = strings.TrimSuffix(string(b), "\r\n")
+		value = strings.TrimSuffix(value, "\n")
+
+	default:
+		return nil
+	}
+
+	node.Tag = "!!str"
+	node.Value = value
+	return nil
+}
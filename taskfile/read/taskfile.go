@@ -3,19 +3,26 @@ package read
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/go-task/task/v3/internal/bundle"
+	"github.com/go-task/task/v3/internal/compiler"
 	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/remote"
 	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/internal/verify"
 	"github.com/go-task/task/v3/taskfile"
 )
 
@@ -23,12 +30,15 @@ var (
 	// ErrIncludedTaskfilesCantHaveDotenvs is returned when a included Taskfile contains dotenvs
 	ErrIncludedTaskfilesCantHaveDotenvs = errors.New("task: Included Taskfiles can't have dotenv declarations. Please, move the dotenv declaration to the main Taskfile")
 
+	nvmrcVersionRegexp = regexp.MustCompile(`^v?\d+(\.\d+){0,2}$`)
+
 	defaultTaskfiles = []string{
 		"Taskfile.yml",
 		"Taskfile.yaml",
 		"Taskfile.dist.yml",
 		"Taskfile.dist.yaml",
 		"package.json",
+		"Makefile",
 	}
 )
 
@@ -37,6 +47,31 @@ type ReaderNode struct {
 	Entrypoint string
 	Optional   bool
 	Parent     *ReaderNode
+	Logger     *logger.Logger
+	// Lenient skips the included Taskfiles that can't be read, recording
+	// them as broken includes, instead of failing
+	Lenient bool
+	// Offline only includes the remote Taskfiles already cached, instead of
+	// fetching them
+	Offline bool
+	// Concurrency is how many Taskfiles are read or fetched at the same
+	// time, since includes are read concurrently. It defaults to twice the
+	// number of CPUs.
+	Concurrency int
+	// Strict reports the unknown keys of the Taskfiles as errors, besides
+	// the values of the wrong type
+	Strict bool
+
+	// ctx is canceled when reading an include failed, to stop reading the
+	// other ones
+	ctx context.Context
+	// readers bounds how many Taskfiles are read at the same time, for the
+	// whole tree of includes
+	readers chan struct{}
+	files   *fileRecorder
+	// vars are used to template the paths of includes: the environment and
+	// the values of the dotenv files of the root Taskfile
+	vars *taskfile.Vars
 }
 
 // Taskfile reads a Taskfile for a given directory
@@ -50,10 +85,20 @@ func Taskfile(readerNode *ReaderNode) (*taskfile.Taskfile, string, error) {
 		}
 		readerNode.Dir = d
 	}
+	if readerNode.ctx == nil {
+		readerNode.ctx = context.Background()
+	}
+	if readerNode.readers == nil {
+		n := readerNode.Concurrency
+		if n <= 0 {
+			n = runtime.NumCPU() * 2
+		}
+		readerNode.readers = make(chan struct{}, n)
+	}
 
 	if readerNode.Entrypoint == "" {
 
-		path, found, err := searchForFile(filepathext.SmartJoin(readerNode.Dir, readerNode.Entrypoint), "Taskfile.yml")
+		path, found, err := searchForFile(readerNode.files, filepathext.SmartJoin(readerNode.Dir, readerNode.Entrypoint), "Taskfile.yml")
 		if err != nil {
 			return nil, "", err
 		}
@@ -69,6 +114,7 @@ func Taskfile(readerNode *ReaderNode) (*taskfile.Taskfile, string, error) {
 		readerNode.Entrypoint = filepath.Base(path)
 	}
 	path := filepathext.SmartJoin(readerNode.Dir, readerNode.Entrypoint)
+	readerNode.Logger.Debugf("reading Taskfile %s", path)
 
 	// absolute path to the project root as an environment variable
 	projectRoot, err := filepath.Abs(readerNode.Dir)
@@ -82,16 +128,24 @@ func Taskfile(readerNode *ReaderNode) (*taskfile.Taskfile, string, error) {
 
 	var t *taskfile.Taskfile
 
+	// Recorded before reading, so changes made while reading invalidate
+	// the cache instead of being missed
+	_, _ = readerNode.files.stat(path)
+
+	release, err := readerNode.acquire(readerNode.ctx)
+	if err != nil {
+		return nil, "", err
+	}
 	if strings.HasSuffix(path, "package.json") {
-		t, err = readPackageJson(projectRoot, path)
-		if err != nil {
-			return nil, "", err
-		}
+		t, err = readPackageJson(readerNode.files, projectRoot, path)
+	} else if filepath.Base(path) == "Makefile" {
+		t, err = readMakefile(path)
 	} else {
-		t, err = readTaskfile(path)
-		if err != nil {
-			return nil, "", err
-		}
+		t, err = readTaskfile(readerNode.files, path, readerNode.Strict)
+	}
+	release()
+	if err != nil {
+		return nil, "", err
 	}
 
 	taskFileDir := filepath.Dir(path)
@@ -120,161 +174,575 @@ func Taskfile(readerNode *ReaderNode) (*taskfile.Taskfile, string, error) {
 		return nil
 	})
 
-	err = t.Includes.Range(func(namespace string, includedTask taskfile.IncludedTaskfile) error {
-		if v >= 3.0 {
-			tr := templater.Templater{Vars: &taskfile.Vars{}, RemoveNoValue: true}
-			includedTask = taskfile.IncludedTaskfile{
-				Taskfile:       tr.Replace(includedTask.Taskfile),
-				Dir:            tr.Replace(includedTask.Dir),
-				Optional:       includedTask.Optional,
-				Internal:       includedTask.Internal,
-				Aliases:        includedTask.Aliases,
-				AdvancedImport: includedTask.AdvancedImport,
-				Vars:           includedTask.Vars,
-				BaseDir:        includedTask.BaseDir,
+	if readerNode.Parent == nil {
+		if err := readIncludeVars(readerNode, t, v, taskFileDir); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := expandGlobIncludes(readerNode, t, v); err != nil {
+		return nil, "", err
+	}
+	includes, err := readIncludedTaskfiles(readerNode, t, v)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The includes merged below add their own broken includes
+	ownBrokenIncludes := t.BrokenIncludes[:len(t.BrokenIncludes):len(t.BrokenIncludes)]
+	brokenIncludes := make(map[string]*taskfile.BrokenInclude, len(ownBrokenIncludes))
+	for _, broken := range ownBrokenIncludes {
+		brokenIncludes[broken.Namespace] = broken
+	}
+	tree := &taskfile.IncludeNode{Taskfile: path, Version: t.Version}
+
+	// Merging is done sequentially, in the order the includes were declared,
+	// so the result doesn't depend on which include finished reading first
+	for i, include := range includes {
+		if include == nil {
+			namespace := t.Includes.Keys[i]
+			declared := t.Includes.Mapping[namespace]
+			node := &taskfile.IncludeNode{
+				Namespace: namespace,
+				Taskfile:  declared.Taskfile,
+				Optional:  declared.Optional,
+				Skipped:   true,
 			}
-			if err := tr.Err(); err != nil {
-				return err
+			if broken, ok := brokenIncludes[namespace]; ok {
+				node.Err = broken.Err
+				delete(brokenIncludes, namespace)
 			}
+			tree.Includes = append(tree.Includes, node)
+			continue
 		}
+		node := *include.taskfile.IncludeTree
+		node.Namespace = include.namespace
+		node.Optional = include.include.Optional
+		node.Origin = include.origin
+		tree.Includes = append(tree.Includes, &node)
 
-		path, err := includedTask.FullTaskfilePath()
-		if err != nil {
-			return err
+		if err = taskfile.Merge(t, include.taskfile, include.include, include.namespace); err != nil {
+			return nil, "", err
 		}
-		path, err = exists(path)
-		if err != nil {
-			if includedTask.Optional {
-				return nil
+
+		if include.taskfile.Tasks["default"] != nil && t.Tasks[include.namespace] == nil {
+			defaultTaskName := fmt.Sprintf("%s:default", include.namespace)
+			t.Tasks[defaultTaskName].Aliases = append(t.Tasks[defaultTaskName].Aliases, include.namespace)
+			t.Tasks[defaultTaskName].Aliases = append(t.Tasks[defaultTaskName].Aliases, include.include.Aliases...)
+		}
+	}
+
+	if v < 3.0 {
+		path := filepathext.SmartJoin(readerNode.Dir, fmt.Sprintf("Taskfile_%s.yml", runtime.GOOS))
+		if _, err = readerNode.files.stat(path); err == nil {
+			osTaskfile, err := readTaskfile(readerNode.files, path, readerNode.Strict)
+			if err != nil {
+				return nil, "", err
+			}
+			if err = taskfile.Merge(t, osTaskfile, nil); err != nil {
+				return nil, "", err
 			}
-			return err
 		}
+	}
 
-		includeReaderNode := &ReaderNode{
-			Dir:        filepath.Dir(path),
-			Entrypoint: filepath.Base(path),
-			Parent:     readerNode,
-			Optional:   includedTask.Optional,
+	for name, task := range t.Tasks {
+		if task == nil {
+			task = &taskfile.Task{}
+			t.Tasks[name] = task
 		}
+		task.Task = name
+	}
 
-		if err := checkCircularIncludes(includeReaderNode); err != nil {
-			return err
+	// The broken glob includes left are the ones that matched nothing
+	for _, broken := range ownBrokenIncludes {
+		if _, ok := brokenIncludes[broken.Namespace]; !ok {
+			continue
 		}
+		tree.Includes = append(tree.Includes, &taskfile.IncludeNode{
+			Namespace: broken.Namespace,
+			Skipped:   true,
+			Err:       broken.Err,
+		})
+	}
+	t.IncludeTree = tree
 
-		includedTaskfile, _, err := Taskfile(includeReaderNode)
-		if err != nil {
-			if includedTask.Optional {
-				return nil
+	return t, taskFileDir, nil
+}
+
+// loadedInclude is an included Taskfile read and ready to be merged
+type loadedInclude struct {
+	namespace string
+	include   *taskfile.IncludedTaskfile
+	taskfile  *taskfile.Taskfile
+	// origin is the URL or the archive the Taskfile was read from, if any
+	origin string
+}
+
+// acquire waits for a reader to be available, returning the func releasing
+// it. It fails if the context is canceled in the meantime.
+func (r *ReaderNode) acquire(ctx context.Context) (func(), error) {
+	select {
+	case r.readers <- struct{}{}:
+		return func() { <-r.readers }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// readIncludedTaskfiles reads all Taskfiles included by t concurrently.
+// The result has the same order as the includes were declared, with nil for
+// optional includes that couldn't be read. Unless reading leniently, a
+// failure stops reading the includes declared after the one failing, but not
+// the ones before it, so the error returned is always the one of the first
+// include declared that fails.
+func readIncludedTaskfiles(readerNode *ReaderNode, t *taskfile.Taskfile, v float64) ([]*loadedInclude, error) {
+	var (
+		includes = make([]*loadedInclude, t.Includes.Len())
+		errs     = make([]error, t.Includes.Len())
+		ctxs     = make([]context.Context, t.Includes.Len())
+		cancels  = make([]context.CancelFunc, t.Includes.Len())
+		wg       sync.WaitGroup
+		i        int
+	)
+	for j := range ctxs {
+		ctxs[j], cancels[j] = context.WithCancel(readerNode.ctx)
+		defer cancels[j]()
+	}
+
+	_ = t.Includes.Range(func(namespace string, includedTask taskfile.IncludedTaskfile) error {
+		idx := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			includes[idx], errs[idx] = readIncludedTaskfile(ctxs[idx], readerNode, namespace, includedTask, v)
+			if errs[idx] != nil && !readerNode.Lenient {
+				for _, cancel := range cancels[idx+1:] {
+					cancel()
+				}
+			}
+		}()
+		i++
+		return nil
+	})
+	wg.Wait()
+
+	if !readerNode.Lenient {
+		for _, err := range errs {
+			if err != nil {
+				return nil, err
 			}
-			return err
 		}
+		return includes, nil
+	}
 
-		if v >= 3.0 && len(includedTaskfile.Dotenv) > 0 {
-			return ErrIncludedTaskfilesCantHaveDotenvs
+	for i, err := range errs {
+		if err == nil {
+			continue
 		}
+		namespace := t.Includes.Keys[i]
+		readerNode.Logger.Debugf("skipping broken include %q: %v", namespace, err)
+		t.BrokenIncludes = append(t.BrokenIncludes, &taskfile.BrokenInclude{Namespace: namespace, Err: err.Error()})
+	}
+	return includes, nil
+}
 
-		if includedTask.AdvancedImport {
-			dir, err := includedTask.FullDirPath()
-			if err != nil {
+// expandGlobIncludes replaces the includes whose path is a glob, like
+// "packages/*/Taskfile.yml", with an include for each match. Their namespace
+// is the name of the directory of the matched Taskfile, or of the matched
+// directory itself.
+func expandGlobIncludes(readerNode *ReaderNode, t *taskfile.Taskfile, v float64) error {
+	hasGlobs := false
+	_ = t.Includes.Range(func(_ string, include taskfile.IncludedTaskfile) error {
+		hasGlobs = hasGlobs || isGlobInclude(include.Taskfile)
+		return nil
+	})
+	if !hasGlobs {
+		return nil
+	}
+
+	expanded := &taskfile.IncludedTaskfiles{}
+	add := func(namespace string, include taskfile.IncludedTaskfile) error {
+		if _, ok := expanded.Mapping[namespace]; ok {
+			return fmt.Errorf(`task: Namespace %q is used by more than one include, including %s`, namespace, include.Taskfile)
+		}
+		expanded.Set(namespace, include)
+		return nil
+	}
+
+	err := t.Includes.Range(func(key string, include taskfile.IncludedTaskfile) error {
+		if !isGlobInclude(include.Taskfile) {
+			return add(key, include)
+		}
+
+		matches, err := globIncludes(readerNode, include, v)
+		if err == nil && len(include.Aliases) > 0 {
+			err = fmt.Errorf(`task: The include %q can't have aliases, since it's a glob`, key)
+		}
+		if err == nil && len(matches) == 0 && !include.Optional {
+			err = fmt.Errorf(`task: No Taskfiles match the include %q: %s`, key, include.Taskfile)
+		}
+		if err != nil {
+			if !readerNode.Lenient {
 				return err
 			}
+			readerNode.Logger.Debugf("skipping broken include %q: %v", key, err)
+			t.BrokenIncludes = append(t.BrokenIncludes, &taskfile.BrokenInclude{Namespace: key, Err: err.Error()})
+			return nil
+		}
 
-			for k, v := range includedTaskfile.Vars.Mapping {
-				o := v
-				o.Dir = dir
-				includedTaskfile.Vars.Mapping[k] = o
+		for _, match := range matches {
+			namespace := filepath.Base(filepath.Dir(match))
+			if fi, err := readerNode.files.stat(match); err == nil && fi.IsDir() {
+				namespace = filepath.Base(match)
 			}
-			for k, v := range includedTaskfile.Env.Mapping {
-				o := v
-				o.Dir = dir
-				includedTaskfile.Env.Mapping[k] = o
+			include := include
+			include.Taskfile = match
+			if err := add(namespace, include); err != nil {
+				return err
 			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	t.Includes = expanded
+	return nil
+}
+
+// isGlobInclude returns true if the path of an include has wildcards
+func isGlobInclude(path string) bool {
+	return strings.ContainsAny(path, "*?[") && !remote.IsRemote(path)
+}
+
+// globIncludes returns the paths matching the glob of an include, relative
+// to the including Taskfile
+func globIncludes(readerNode *ReaderNode, include taskfile.IncludedTaskfile, v float64) ([]string, error) {
+	if v >= 3.0 {
+		readerNode.files.template(include.Taskfile)
+		tr := templater.Templater{Vars: readerNode.vars, RemoveNoValue: true}
+		include.Taskfile = tr.Replace(include.Taskfile)
+		if err := tr.Err(); err != nil {
+			return nil, err
+		}
+	}
+	readerNode.files.expand(include.Taskfile)
+	pattern, err := include.FullTaskfilePath()
+	if err != nil {
+		return nil, err
+	}
+	return readerNode.files.glob(pattern)
+}
+
+// readIncludeVars sets the variables available to template the paths of the
+// includes of the root Taskfile. Its dotenv files are read first, so the
+// values they set can be used as well.
+func readIncludeVars(readerNode *ReaderNode, t *taskfile.Taskfile, v float64, dir string) error {
+	readerNode.vars = compiler.GetEnviron()
+	if v < 3.0 || len(t.Dotenv) == 0 {
+		return nil
+	}
+
+	dotenv, err := readDotenvFiles(readerNode.files, t, readerNode.vars, dir)
+	if err != nil {
+		return err
+	}
+	return dotenv.Env.Range(func(key string, value taskfile.Var) error {
+		if _, ok := readerNode.vars.Mapping[key]; !ok || dotenv.Overrides[key] {
+			readerNode.vars.Set(key, value)
+		}
+		return nil
+	})
+}
 
-			for _, task := range includedTaskfile.Tasks {
-				task.Dir = filepathext.SmartJoin(dir, task.Dir)
-				task.IncludeVars = includedTask.Vars
-				task.IncludedTaskfileVars = includedTaskfile.Vars
-				task.IncludedTaskfile = &includedTask
+func readIncludedTaskfile(ctx context.Context, readerNode *ReaderNode, namespace string, includedTask taskfile.IncludedTaskfile, v float64) (*loadedInclude, error) {
+	if v >= 3.0 {
+		readerNode.files.template(includedTask.Taskfile)
+		readerNode.files.template(includedTask.Dir)
+		tr := templater.Templater{Vars: readerNode.vars, RemoveNoValue: true}
+		includedTask = taskfile.IncludedTaskfile{
+			Taskfile:       tr.Replace(includedTask.Taskfile),
+			Dir:            tr.Replace(includedTask.Dir),
+			Optional:       includedTask.Optional,
+			Internal:       includedTask.Internal,
+			Aliases:        includedTask.Aliases,
+			AdvancedImport: includedTask.AdvancedImport,
+			Vars:           includedTask.Vars,
+			BaseDir:        includedTask.BaseDir,
+			Checksum:       includedTask.Checksum,
+		}
+		if err := tr.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	var remoteDir, origin string
+	if remote.IsRemote(includedTask.Taskfile) {
+		origin = includedTask.Taskfile
+		release, err := readerNode.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		path, err := remote.Fetch(ctx, &remote.Options{
+			Source:   includedTask.Taskfile,
+			Checksum: includedTask.Checksum,
+			CacheDir: remoteCacheDir(readerNode),
+			Offline:  readerNode.Offline,
+		})
+		release()
+		if err != nil {
+			if includedTask.Optional {
+				readerNode.Logger.Debugf("skipping optional include %q: %v", namespace, err)
+				return nil, nil
 			}
+			return nil, err
+		}
+		readerNode.Logger.Debugf("include %q fetched to %s", namespace, path)
+		includedTask.Taskfile = path
+		remoteDir = path
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			remoteDir = filepath.Dir(path)
 		}
+	}
 
-		if err = taskfile.Merge(t, includedTaskfile, &includedTask, namespace); err != nil {
-			return err
+	readerNode.files.expand(includedTask.Taskfile)
+	readerNode.files.expand(includedTask.Dir)
+	path, err := includedTask.FullTaskfilePath()
+	if err != nil {
+		return nil, err
+	}
+	path, err = exists(readerNode.files, path)
+	if err != nil {
+		if includedTask.Optional {
+			readerNode.Logger.Debugf("skipping optional include %q: %v", namespace, err)
+			return nil, nil
 		}
+		return nil, err
+	}
+	readerNode.Logger.Debugf("include %q resolved to %s", namespace, path)
 
-		if includedTaskfile.Tasks["default"] != nil && t.Tasks[namespace] == nil {
-			defaultTaskName := fmt.Sprintf("%s:default", namespace)
-			t.Tasks[defaultTaskName].Aliases = append(t.Tasks[defaultTaskName].Aliases, namespace)
-			t.Tasks[defaultTaskName].Aliases = append(t.Tasks[defaultTaskName].Aliases, includedTask.Aliases...)
+	if includedTask.Checksum != "" {
+		checksum, err := verify.ParseChecksum(includedTask.Checksum)
+		if err != nil {
+			return nil, err
 		}
+		if err := checksum.File(path); err != nil {
+			return nil, fmt.Errorf("task: Included Taskfile %q changed: %w", namespace, err)
+		}
+	}
 
-		return nil
-	})
+	// Archives are extracted into the cache, and their tasks run where they
+	// were extracted to unless the include sets a dir
+	if bundle.IsArchive(path) {
+		dir, manifest, err := bundle.Open(path, packageCacheDir())
+		if err != nil {
+			return nil, err
+		}
+		readerNode.Logger.Debugf("include %q extracted to %s", namespace, dir)
+		if origin == "" {
+			origin = path
+		}
+		path = filepath.Join(dir, filepath.FromSlash(manifest.Taskfile))
+		if includedTask.Dir == "" {
+			includedTask.Dir = dir
+			includedTask.AdvancedImport = true
+		}
+	}
+
+	includeReaderNode := &ReaderNode{
+		Dir:        filepath.Dir(path),
+		Entrypoint: filepath.Base(path),
+		Parent:     readerNode,
+		Optional:   includedTask.Optional,
+		Logger:     readerNode.Logger,
+		Lenient:    readerNode.Lenient,
+		Offline:    readerNode.Offline,
+		Strict:     readerNode.Strict,
+		ctx:        ctx,
+		readers:    readerNode.readers,
+		files:      readerNode.files,
+		vars:       readerNode.vars,
+	}
+
+	if err := checkCircularIncludes(includeReaderNode); err != nil {
+		return nil, err
+	}
+
+	includedTaskfile, _, err := Taskfile(includeReaderNode)
 	if err != nil {
-		return nil, "", err
+		if includedTask.Optional {
+			readerNode.Logger.Debugf("skipping optional include %q: %v", namespace, err)
+			return nil, nil
+		}
+		return nil, err
 	}
 
-	if v < 3.0 {
-		path := filepathext.SmartJoin(readerNode.Dir, fmt.Sprintf("Taskfile_%s.yml", runtime.GOOS))
-		if _, err = os.Stat(path); err == nil {
-			osTaskfile, err := readTaskfile(path)
-			if err != nil {
-				return nil, "", err
+	if v >= 3.0 && len(includedTaskfile.Dotenv) > 0 {
+		return nil, ErrIncludedTaskfilesCantHaveDotenvs
+	}
+
+	if includedTaskfile.Params.Len() > 0 {
+		vars, err := includedTaskfile.Params.Apply(namespace, includedTask.Vars)
+		if err != nil {
+			return nil, err
+		}
+		includedTask.Vars = vars
+		for _, task := range includedTaskfile.Tasks {
+			task.IncludeVars = vars
+		}
+	}
+
+	// The tasks of remote Taskfiles run relative to the including Taskfile,
+	// or to the dir of the include, instead of the cache
+	if remoteDir != "" {
+		dir := includedTask.BaseDir
+		if includedTask.Dir != "" {
+			if dir, err = includedTask.FullDirPath(); err != nil {
+				return nil, err
 			}
-			if err = taskfile.Merge(t, osTaskfile, nil); err != nil {
-				return nil, "", err
+		}
+		for _, task := range includedTaskfile.Tasks {
+			if rel, err := filepath.Rel(remoteDir, task.Dir); err == nil && !strings.HasPrefix(rel, "..") {
+				task.Dir = filepathext.SmartJoin(dir, rel)
 			}
 		}
 	}
 
-	for name, task := range t.Tasks {
-		if task == nil {
-			task = &taskfile.Task{}
-			t.Tasks[name] = task
+	if includedTask.AdvancedImport {
+		dir, err := includedTask.FullDirPath()
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range includedTaskfile.Vars.Mapping {
+			o := v
+			o.Dir = dir
+			includedTaskfile.Vars.Mapping[k] = o
+		}
+		for k, v := range includedTaskfile.Env.Mapping {
+			o := v
+			o.Dir = dir
+			includedTaskfile.Env.Mapping[k] = o
+		}
+
+		for _, task := range includedTaskfile.Tasks {
+			task.Dir = filepathext.SmartJoin(dir, task.Dir)
+			task.IncludeVars = includedTask.Vars
+			task.IncludedTaskfileVars = includedTaskfile.Vars
+			task.IncludedTaskfile = &includedTask
 		}
-		task.Task = name
 	}
 
-	return t, taskFileDir, nil
+	return &loadedInclude{
+		namespace: namespace,
+		include:   &includedTask,
+		taskfile:  includedTaskfile,
+		origin:    origin,
+	}, nil
 }
 
-func readTaskfile(file string) (*taskfile.Taskfile, error) {
-	f, err := os.Open(file)
+func readTaskfile(files *fileRecorder, file string, strict bool) (*taskfile.Taskfile, error) {
+	f, err := os.Open(filepathext.LongPath(file))
 	if err != nil {
 		return nil, err
 	}
+	defer f.Close()
+	var node yaml.Node
+	if err := yaml.NewDecoder(f).Decode(&node); err != nil {
+		return nil, fmt.Errorf("task: Failed to parse %s:\n%w", filepathext.TryAbsToRel(file), err)
+	}
+	if err := resolveTags(files, &node, filepath.Dir(file)); err != nil {
+		return nil, fmt.Errorf("task: Failed to parse %s:\n%w", filepathext.TryAbsToRel(file), err)
+	}
 	var t taskfile.Taskfile
-	if err := yaml.NewDecoder(f).Decode(&t); err != nil {
+	if err := node.Decode(&t); err != nil {
+		// The errors of the validation tell where the problem is and how to
+		// fix it, unlike the ones of the YAML decoder
+		if errs := taskfile.Validate(&node, strict); len(errs) > 0 {
+			errs.SetTaskfile(file)
+			return nil, errs
+		}
 		return nil, fmt.Errorf("task: Failed to parse %s:\n%w", filepathext.TryAbsToRel(file), err)
 	}
+	if strict {
+		if errs := taskfile.Validate(&node, true); len(errs) > 0 {
+			errs.SetTaskfile(file)
+			return nil, errs
+		}
+	}
+	t.Locations.SetTaskfile(file)
 	for _, task := range t.Tasks {
+		if task == nil {
+			continue
+		}
 		task.Taskfile = file
+		task.Location.Taskfile = file
+		task.Locations.SetTaskfile(file)
+		for _, cmd := range task.Cmds {
+			if cmd != nil {
+				cmd.Location.Taskfile = file
+			}
+		}
+		for _, dep := range taskfile.AllDeps(task.Deps) {
+			dep.Location.Taskfile = file
+		}
+	}
+	for _, cmds := range t.Snippets {
+		for _, cmd := range cmds {
+			if cmd != nil {
+				cmd.Location.Taskfile = file
+			}
+		}
 	}
 	return &t, nil
 }
 
 type packageJson struct {
-	Scripts map[string]string `json:"scripts"`
+	Name       string            `json:"name"`
+	Version    string            `json:"version"`
+	Scripts    map[string]string `json:"scripts"`
+	Workspaces packageWorkspaces `json:"workspaces"`
+	Engines    struct {
+		Node string `json:"node"`
+	} `json:"engines"`
 }
 
-func readPackageJson(projectRoot, file string) (*taskfile.Taskfile, error) {
-	f, err := os.Open(file)
-	if err != nil {
-		return nil, err
+// packageWorkspaces are the globs of the "workspaces" of a package.json file,
+// given either as a list or, like Yarn allows, as the "packages" of an object
+type packageWorkspaces []string
+
+func (w *packageWorkspaces) UnmarshalJSON(data []byte) error {
+	var globs []string
+	if err := json.Unmarshal(data, &globs); err == nil {
+		*w = globs
+		return nil
 	}
-	fd, err := ioutil.ReadAll(f)
-	if err != nil {
-		return nil, err
+	var object struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
 	}
+	*w = object.Packages
+	return nil
+}
 
-	var p packageJson
+// pnpmWorkspace is the pnpm-workspace.yaml file, which declares the
+// workspaces of pnpm instead of package.json
+type pnpmWorkspace struct {
+	Packages []string `yaml:"packages"`
+}
 
-	if err = json.Unmarshal(fd, &p); err != nil {
+func readPackageJson(files *fileRecorder, projectRoot, file string) (*taskfile.Taskfile, error) {
+	p, fd, err := parsePackageJson(file)
+	if err != nil {
 		return nil, err
 	}
 
 	t := taskfile.Taskfile{
 		Version: "3",
+		Vars:    &taskfile.Vars{},
+		Env:     &taskfile.Vars{},
 		Tasks:   taskfile.Tasks{},
 	}
 
@@ -288,10 +756,12 @@ func readPackageJson(projectRoot, file string) (*taskfile.Taskfile, error) {
 		relFile = file
 	}
 
-	cmd := "npm"
-	// if yark.lock exists, use yarn instead
-	if _, err := os.Stat(filepath.Join(filepath.Dir(file), "yarn.lock")); err == nil {
-		cmd = "yarn"
+	dir := filepath.Dir(file)
+	cmd := packageManager(files, dir)
+
+	nodeVersions, err := readNodeVersions(files, p, file)
+	if err != nil {
+		return nil, err
 	}
 
 	for name := range p.Scripts {
@@ -306,12 +776,221 @@ func readPackageJson(projectRoot, file string) (*taskfile.Taskfile, error) {
 					Cmd: cmd + " run " + name,
 				},
 			},
+			Env:          packageScriptEnv(p, name),
+			NodeVersions: nodeVersions,
+		}
+	}
+
+	workspaces, err := readPackageWorkspaces(files, p, dir, cmd)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range workspaces {
+		relWorkspaceFile, err := filepath.Rel(wd, w.file)
+		if err != nil {
+			relWorkspaceFile = w.file
+		}
+		for name := range w.p.Scripts {
+			taskName := w.namespace + taskfile.NamespaceSeparator + name
+			if _, ok := t.Tasks[taskName]; ok {
+				return nil, fmt.Errorf(`task: Found multiple tasks (%s) included by "%s"`, taskName, w.namespace)
+			}
+			// Workspace scripts are run from the root, with the package
+			// manager, so the dependencies are installed for all of them
+			t.Tasks[taskName] = &taskfile.Task{
+				Taskfile: w.file,
+				Desc:     fmt.Sprintf("→ %s%s", relWorkspaceFile, findLineNumber(w.data, name)),
+				Cmds: []*taskfile.Cmd{
+					{
+						Cmd: cmd + " install --silent --frozen-lockfile",
+					},
+					{
+						Cmd: workspaceRunCommand(cmd, w, name),
+					},
+				},
+				Env:          packageScriptEnv(w.p, name),
+				NodeVersions: nodeVersions,
+			}
 		}
 	}
 
 	return &t, nil
 }
 
+func parsePackageJson(file string) (packageJson, []byte, error) {
+	var p packageJson
+	fd, err := os.ReadFile(file)
+	if err != nil {
+		return p, nil, err
+	}
+	if err = json.Unmarshal(fd, &p); err != nil {
+		return p, nil, err
+	}
+	return p, fd, nil
+}
+
+// packageManager returns the package manager of the project in the given
+// directory, given by its lock file, defaulting to npm
+func packageManager(files *fileRecorder, dir string) string {
+	for _, lock := range []struct{ file, cmd string }{
+		{"pnpm-lock.yaml", "pnpm"},
+		{"bun.lockb", "bun"},
+		{"bun.lock", "bun"},
+		{"yarn.lock", "yarn"},
+	} {
+		if _, err := files.stat(filepath.Join(dir, lock.file)); err == nil {
+			return lock.cmd
+		}
+	}
+	return "npm"
+}
+
+// packageScriptEnv returns the variables set by "npm run", so scripts
+// relying on them behave the same
+func packageScriptEnv(p packageJson, script string) *taskfile.Vars {
+	env := &taskfile.Vars{}
+	env.Set("npm_package_name", taskfile.Var{Static: p.Name})
+	env.Set("npm_package_version", taskfile.Var{Static: p.Version})
+	env.Set("npm_lifecycle_event", taskfile.Var{Static: script})
+	return env
+}
+
+// packageWorkspace is a package of the workspaces of a package.json file,
+// whose scripts become tasks in the namespace named after its directory
+type packageWorkspace struct {
+	namespace string
+	// rel is the path of its directory, relative to the root of the
+	// workspaces
+	rel  string
+	file string
+	p    packageJson
+	data []byte
+}
+
+// readPackageWorkspaces reads the packages of the workspaces declared by the
+// package.json file, or by the pnpm-workspace.yaml file next to it when
+// pnpm is used. Globs starting with "!" exclude packages.
+func readPackageWorkspaces(files *fileRecorder, p packageJson, dir, cmd string) ([]packageWorkspace, error) {
+	globs := []string(p.Workspaces)
+	if cmd == "pnpm" {
+		pnpmFile := filepath.Join(dir, "pnpm-workspace.yaml")
+		if _, err := files.stat(pnpmFile); err == nil {
+			data, err := os.ReadFile(pnpmFile)
+			if err != nil {
+				return nil, err
+			}
+			var w pnpmWorkspace
+			if err := yaml.Unmarshal(data, &w); err != nil {
+				return nil, fmt.Errorf("task: Failed to parse %s: %w", pnpmFile, err)
+			}
+			globs = append(globs, w.Packages...)
+		}
+	}
+
+	excluded := make(map[string]bool)
+	var dirs []string
+	for _, glob := range globs {
+		exclude := strings.HasPrefix(glob, "!")
+		matches, err := files.glob(filepathext.SmartJoin(dir, strings.TrimPrefix(glob, "!")))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if exclude {
+				excluded[match] = true
+			} else {
+				dirs = append(dirs, match)
+			}
+		}
+	}
+
+	namespaces := make(map[string]string)
+	var workspaces []packageWorkspace
+	for _, workspaceDir := range dirs {
+		file := filepath.Join(workspaceDir, "package.json")
+		if excluded[workspaceDir] || workspaceDir == dir {
+			continue
+		}
+		if _, err := files.stat(file); err != nil {
+			continue
+		}
+		namespace := filepath.Base(workspaceDir)
+		if other, ok := namespaces[namespace]; ok {
+			if other == workspaceDir {
+				continue
+			}
+			return nil, fmt.Errorf(`task: Namespace %q is used by more than one workspace: %s and %s`, namespace, other, workspaceDir)
+		}
+		namespaces[namespace] = workspaceDir
+
+		wp, data, err := parsePackageJson(file)
+		if err != nil {
+			return nil, fmt.Errorf("task: Failed to parse %s: %w", file, err)
+		}
+		rel, err := filepath.Rel(dir, workspaceDir)
+		if err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, packageWorkspace{
+			namespace: namespace,
+			rel:       filepath.ToSlash(rel),
+			file:      file,
+			p:         wp,
+			data:      data,
+		})
+	}
+	return workspaces, nil
+}
+
+// workspaceRunCommand returns the command running the script of a workspace
+// package from the root of the workspaces
+func workspaceRunCommand(cmd string, w packageWorkspace, script string) string {
+	// Packages are selected by name, or by path when they have none
+	selector := w.p.Name
+	if selector == "" {
+		selector = "./" + w.rel
+	}
+	switch cmd {
+	case "pnpm":
+		return fmt.Sprintf("pnpm --filter %s run %s", selector, script)
+	case "bun":
+		return fmt.Sprintf("bun run --filter %s %s", selector, script)
+	case "yarn":
+		return fmt.Sprintf("yarn workspace %s run %s", selector, script)
+	default:
+		return fmt.Sprintf("npm run %s --workspace=%s", script, selector)
+	}
+}
+
+// readNodeVersions returns the Node.js versions required by the "engines" of
+// the package.json file and by the .nvmrc file next to it, if any
+func readNodeVersions(files *fileRecorder, p packageJson, file string) ([]taskfile.NodeVersion, error) {
+	var versions []taskfile.NodeVersion
+	if p.Engines.Node != "" {
+		versions = append(versions, taskfile.NodeVersion{
+			Range:  p.Engines.Node,
+			Source: `"engines.node" of package.json`,
+		})
+	}
+
+	nvmrc := filepath.Join(filepath.Dir(file), ".nvmrc")
+	if _, err := files.stat(nvmrc); err != nil {
+		return versions, nil
+	}
+	data, err := os.ReadFile(nvmrc)
+	if err != nil {
+		return nil, err
+	}
+	// Aliases like "lts/*" or "node" can't be verified
+	if version := strings.TrimSpace(string(data)); nvmrcVersionRegexp.MatchString(version) {
+		versions = append(versions, taskfile.NodeVersion{
+			Range:  version,
+			Source: ".nvmrc",
+		})
+	}
+	return versions, nil
+}
+
 func findLineNumber(f []byte, scriptName string) string {
 	// Splits on newlines by default.
 	scanner := bufio.NewScanner(bytes.NewReader(f))
@@ -333,8 +1012,28 @@ func findLineNumber(f []byte, scriptName string) string {
 	return ""
 }
 
-func exists(path string) (string, error) {
-	fi, err := os.Stat(path)
+// remoteCacheDir returns where the remote Taskfiles are cached: in the
+// ".task/remote" directory of the root Taskfile
+func remoteCacheDir(readerNode *ReaderNode) string {
+	for readerNode.Parent != nil {
+		readerNode = readerNode.Parent
+	}
+	return filepathext.SmartJoin(readerNode.Dir, ".task/remote")
+}
+
+// packageCacheDir returns where included archives are extracted to
+func packageCacheDir() string {
+	if dir := os.Getenv("TASK_PACKAGE_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(cacheDir, "task", "packages")
+	}
+	return filepath.Join(os.TempDir(), "task", "packages")
+}
+
+func exists(files *fileRecorder, path string) (string, error) {
+	fi, err := files.stat(path)
 	if err != nil {
 		return "", err
 	}
@@ -344,7 +1043,7 @@ func exists(path string) (string, error) {
 
 	for _, n := range defaultTaskfiles {
 		fpath := filepathext.SmartJoin(path, n)
-		if _, err := os.Stat(fpath); err == nil {
+		if _, err := files.stat(fpath); err == nil {
 			return fpath, nil
 		}
 	}
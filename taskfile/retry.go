@@ -0,0 +1,62 @@
+package taskfile
+
+import (
+	"errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Retry is how a task is run again when it fails
+type Retry struct {
+	// Count is how many times the task is run again at most
+	Count int
+	// Delay is how long to wait before running the task again, like "5s"
+	Delay string
+	// Backoff multiplies the delay after each attempt. Defaults to 1.
+	Backoff float64
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (r *Retry) UnmarshalYAML(node *yaml.Node) error {
+	var count int
+	if err := node.Decode(&count); err == nil {
+		r.Count = count
+		r.Backoff = 1
+		return r.validate()
+	}
+
+	var retry struct {
+		Count   int
+		Delay   string
+		Backoff float64
+	}
+	if err := node.Decode(&retry); err != nil {
+		return err
+	}
+	r.Count = retry.Count
+	r.Delay = retry.Delay
+	r.Backoff = retry.Backoff
+	if r.Backoff == 0 {
+		r.Backoff = 1
+	}
+	return r.validate()
+}
+
+func (r *Retry) validate() error {
+	if r.Count < 0 {
+		return errors.New("task: The count of retry can't be negative")
+	}
+	if r.Backoff < 1 {
+		return errors.New("task: The backoff of retry can't be less than 1")
+	}
+	return nil
+}
+
+// DeepCopy creates a new instance of Retry and copies
+// data by value from the source struct.
+func (r *Retry) DeepCopy() *Retry {
+	if r == nil {
+		return nil
+	}
+	return &Retry{Count: r.Count, Delay: r.Delay, Backoff: r.Backoff}
+}
@@ -0,0 +1,30 @@
+package taskfile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+func TestRetryParse(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected *taskfile.Retry
+	}{
+		{"3", &taskfile.Retry{Count: 3, Backoff: 1}},
+		{"{count: 2, delay: 5s}", &taskfile.Retry{Count: 2, Delay: "5s", Backoff: 1}},
+		{"{count: 2, delay: 1s, backoff: 1.5}", &taskfile.Retry{Count: 2, Delay: "1s", Backoff: 1.5}},
+	}
+	for _, test := range tests {
+		var r taskfile.Retry
+		assert.NoError(t, yaml.Unmarshal([]byte(test.content), &r))
+		assert.Equal(t, test.expected, &r)
+	}
+
+	var r taskfile.Retry
+	assert.EqualError(t, yaml.Unmarshal([]byte("-1"), &r), "task: The count of retry can't be negative")
+	assert.EqualError(t, yaml.Unmarshal([]byte("{count: 1, backoff: 0.5}"), &r), "task: The backoff of retry can't be less than 1")
+}
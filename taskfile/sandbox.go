@@ -0,0 +1,42 @@
+package taskfile
+
+import "gopkg.in/yaml.v3"
+
+// Sandbox restricts the commands of a task to writing to its sources and
+// generates, catching the ones that change files they didn't declare
+type Sandbox struct {
+	// Network allows the commands to connect to other hosts. It's allowed
+	// unless set to false.
+	Network bool
+
+	// disabled is set by "sandbox: false"
+	disabled bool
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (s *Sandbox) UnmarshalYAML(node *yaml.Node) error {
+	var enabled bool
+	if err := node.Decode(&enabled); err == nil {
+		s.Network = true
+		s.disabled = !enabled
+		return nil
+	}
+
+	var sandbox struct {
+		Network *bool
+	}
+	if err := node.Decode(&sandbox); err != nil {
+		return err
+	}
+	s.Network = sandbox.Network == nil || *sandbox.Network
+	return nil
+}
+
+// DeepCopy creates a new instance of Sandbox and copies
+// data by value from the source struct.
+func (s *Sandbox) DeepCopy() *Sandbox {
+	if s == nil {
+		return nil
+	}
+	return &Sandbox{Network: s.Network}
+}
@@ -0,0 +1,67 @@
+package taskfile
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// StdinInherit makes the commands read the input of Task
+	StdinInherit = "inherit"
+	// StdinNull makes the commands read no input
+	StdinNull = "null"
+	// StdinFile makes the commands read a file
+	StdinFile = "file"
+)
+
+// Stdin is where the commands of a task read their input from
+type Stdin struct {
+	// Policy is StdinInherit, StdinNull or StdinFile
+	Policy string
+	// File is read when the policy is StdinFile, relative to the directory
+	// of the task
+	File string
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (s *Stdin) UnmarshalYAML(node *yaml.Node) error {
+	// "stdin: null" is a YAML null, which isn't decoded as a string
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null" {
+		s.Policy = StdinNull
+		return nil
+	}
+
+	var policy string
+	if err := node.Decode(&policy); err == nil {
+		switch policy {
+		case StdinInherit, StdinNull:
+			s.Policy = policy
+			return nil
+		default:
+			return fmt.Errorf(`task: Invalid stdin %q, expected "inherit", "null" or a file`, policy)
+		}
+	}
+
+	var stdin struct {
+		File string
+	}
+	if err := node.Decode(&stdin); err != nil {
+		return err
+	}
+	if stdin.File == "" {
+		return fmt.Errorf(`task: The file of stdin can't be empty`)
+	}
+	s.Policy = StdinFile
+	s.File = stdin.File
+	return nil
+}
+
+// DeepCopy creates a new instance of Stdin and copies
+// data by value from the source struct.
+func (s *Stdin) DeepCopy() *Stdin {
+	if s == nil {
+		return nil
+	}
+	return &Stdin{Policy: s.Policy, File: s.File}
+}
@@ -1,5 +1,7 @@
 package taskfile
 
+import "gopkg.in/yaml.v3"
+
 // Tasks represents a group of tasks
 type Tasks map[string]*Task
 
@@ -12,13 +14,19 @@ type Task struct {
 	Desc                 string
 	Summary              string
 	Aliases              []string
+	Tags                 []string
+	Icon                 Icon
 	Sources              []string
 	Generates            []string
+	Artifacts            []*Artifact
+	Outputs              map[string]*TaskOutput
+	Umask                string
 	Status               []string
 	Preconditions        []*Precondition
 	Dir                  string
 	Vars                 *Vars
 	Env                  *Vars
+	EnvFile              EnvFiles
 	Silent               bool
 	Interactive          bool
 	Internal             bool
@@ -26,10 +34,64 @@ type Task struct {
 	Prefix               string
 	IgnoreError          bool
 	Run                  string
+	Memoize              *bool
+	WSL                  bool
+	Nix                  *Nix
+	Privileged           bool
+	Notify               bool
+	Tmpdir               bool
+	Flags                *Flags
 	IncludeVars          *Vars
 	IncludedTaskfileVars *Vars
 	IncludedTaskfile     *IncludedTaskfile
 	Taskfile             string
+	Namespace            string
+	// NodeVersions are the Node.js versions required to run the task. They
+	// are only set for the tasks of package.json files.
+	NodeVersions []NodeVersion
+	// Inputs are the outputs of other tasks used by the task. They're only
+	// known after it's compiled.
+	Inputs []Input
+	// EnvPassthrough and EnvBlock are patterns, like "AWS_*", of the
+	// variables of the environment of Task that are passed to the commands,
+	// and of the ones that aren't
+	EnvPassthrough []string
+	EnvBlock       []string
+	// MsgSuccess and MsgFailure are printed when the task finishes, depending
+	// on whether it succeeded
+	MsgSuccess string
+	MsgFailure string
+	// Metadata are free-form annotations, like the owner of the task or its
+	// runbook, which are ignored when running it
+	Metadata map[string]string
+	// Sandbox restricts what the commands of the task may change, when set
+	Sandbox *Sandbox
+	// Stdin is where the commands read their input from. They read the
+	// input of Task when it's nil.
+	Stdin *Stdin
+	// Retry runs the commands again when they fail, when set
+	Retry *Retry
+	// Timeout is how long the commands may run, like "10m", before they're
+	// killed
+	Timeout string
+	// OncePer are the scopes the task runs at most once in, like "day"
+	OncePer OncePer
+	// Matrix expands the task into an instance for each combination of the
+	// values of some variables, when set
+	Matrix *Matrix
+	// VarsHash is the hash of the values of the variables of the compiled
+	// task, only set for the tasks run once per vars
+	VarsHash string
+	// Location is where the task is defined
+	Location  Location
+	Locations Locations
+}
+
+// NodeVersion is a range of Node.js versions, in the syntax used by npm
+type NodeVersion struct {
+	Range string
+	// Source is where the range was declared, like ".nvmrc"
+	Source string
 }
 
 func (t *Task) Name() string {
@@ -39,15 +101,17 @@ func (t *Task) Name() string {
 	return t.Task
 }
 
-func (t *Task) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (t *Task) UnmarshalYAML(node *yaml.Node) error {
+	t.Location = locationOf(node)
+
 	var cmd Cmd
-	if err := unmarshal(&cmd); err == nil && cmd.Cmd != "" {
+	if err := node.Decode(&cmd); err == nil && cmd.Cmd != "" {
 		t.Cmds = append(t.Cmds, &cmd)
 		return nil
 	}
 
 	var cmds []*Cmd
-	if err := unmarshal(&cmds); err == nil && len(cmds) > 0 {
+	if err := node.Decode(&cmds); err == nil && len(cmds) > 0 {
 		t.Cmds = cmds
 		return nil
 	}
@@ -59,13 +123,19 @@ func (t *Task) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		Desc          string
 		Summary       string
 		Aliases       []string
+		Tags          []string
+		Icon          Icon
 		Sources       []string
 		Generates     []string
+		Artifacts     []*Artifact
+		Outputs       map[string]*TaskOutput
+		Umask         string
 		Status        []string
 		Preconditions []*Precondition
 		Dir           string
 		Vars          *Vars
 		Env           *Vars
+		EnvFile       EnvFiles `yaml:"env_file"`
 		Silent        bool
 		Interactive   bool
 		Internal      bool
@@ -73,8 +143,29 @@ func (t *Task) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		Prefix        string
 		IgnoreError   bool `yaml:"ignore_error"`
 		Run           string
+		Memoize       *bool
+		WSL           bool `yaml:"wsl"`
+		Nix           *Nix
+		Privileged    bool
+		Notify        bool
+		Tmpdir        bool
+		Flags         *Flags
+		// Filters of the environment of Task
+		EnvPassthrough []string `yaml:"env_passthrough"`
+		EnvBlock       []string `yaml:"env_block"`
+		// Messages printed when the task finishes
+		MsgSuccess string `yaml:"msg_success"`
+		MsgFailure string `yaml:"msg_failure"`
+		Metadata   map[string]string
+		Sandbox    *Sandbox
+		// A node, since "stdin: null" is a YAML null
+		Stdin   yaml.Node
+		Retry   *Retry
+		Timeout string
+		OncePer OncePer `yaml:"once_per"`
+		Matrix  *Matrix
 	}
-	if err := unmarshal(&task); err != nil {
+	if err := node.Decode(&task); err != nil {
 		return err
 	}
 	t.Cmds = task.Cmds
@@ -82,14 +173,20 @@ func (t *Task) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	t.Label = task.Label
 	t.Desc = task.Desc
 	t.Aliases = task.Aliases
+	t.Tags = task.Tags
+	t.Icon = task.Icon
 	t.Summary = task.Summary
 	t.Sources = task.Sources
 	t.Generates = task.Generates
+	t.Artifacts = task.Artifacts
+	t.Outputs = task.Outputs
+	t.Umask = task.Umask
 	t.Status = task.Status
 	t.Preconditions = task.Preconditions
 	t.Dir = task.Dir
 	t.Vars = task.Vars
 	t.Env = task.Env
+	t.EnvFile = task.EnvFile
 	t.Silent = task.Silent
 	t.Interactive = task.Interactive
 	t.Internal = task.Internal
@@ -97,6 +194,32 @@ func (t *Task) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	t.Prefix = task.Prefix
 	t.IgnoreError = task.IgnoreError
 	t.Run = task.Run
+	t.Memoize = task.Memoize
+	t.WSL = task.WSL
+	t.Nix = task.Nix
+	t.Privileged = task.Privileged
+	t.Notify = task.Notify
+	t.Tmpdir = task.Tmpdir
+	t.Flags = task.Flags
+	t.EnvPassthrough = task.EnvPassthrough
+	t.EnvBlock = task.EnvBlock
+	t.MsgSuccess = task.MsgSuccess
+	t.MsgFailure = task.MsgFailure
+	t.Metadata = task.Metadata
+	if task.Sandbox != nil && !task.Sandbox.disabled {
+		t.Sandbox = task.Sandbox
+	}
+	if !task.Stdin.IsZero() {
+		t.Stdin = &Stdin{}
+		if err := t.Stdin.UnmarshalYAML(&task.Stdin); err != nil {
+			return err
+		}
+	}
+	t.Retry = task.Retry
+	t.Timeout = task.Timeout
+	t.OncePer = task.OncePer
+	t.Matrix = task.Matrix
+	t.Locations = keyLocations(node)
 	return nil
 }
 
@@ -111,13 +234,20 @@ func (t *Task) DeepCopy() *Task {
 		Desc:                 t.Desc,
 		Summary:              t.Summary,
 		Aliases:              deepCopySlice(t.Aliases),
+		Tags:                 deepCopySlice(t.Tags),
+		Icon:                 t.Icon,
 		Sources:              deepCopySlice(t.Sources),
 		Generates:            deepCopySlice(t.Generates),
+		Artifacts:            deepCopySlice(t.Artifacts),
+		Outputs:              deepCopyMap(t.Outputs),
+		Inputs:               deepCopySlice(t.Inputs),
+		Umask:                t.Umask,
 		Status:               deepCopySlice(t.Status),
 		Preconditions:        deepCopySlice(t.Preconditions),
 		Dir:                  t.Dir,
 		Vars:                 t.Vars.DeepCopy(),
 		Env:                  t.Env.DeepCopy(),
+		EnvFile:              deepCopySlice(t.EnvFile),
 		Silent:               t.Silent,
 		Interactive:          t.Interactive,
 		Internal:             t.Internal,
@@ -125,10 +255,33 @@ func (t *Task) DeepCopy() *Task {
 		Prefix:               t.Prefix,
 		IgnoreError:          t.IgnoreError,
 		Run:                  t.Run,
+		Memoize:              t.Memoize,
+		WSL:                  t.WSL,
+		Nix:                  t.Nix.DeepCopy(),
+		Privileged:           t.Privileged,
+		Notify:               t.Notify,
+		Tmpdir:               t.Tmpdir,
+		Flags:                t.Flags.DeepCopy(),
 		IncludeVars:          t.IncludeVars.DeepCopy(),
 		IncludedTaskfileVars: t.IncludedTaskfileVars.DeepCopy(),
 		IncludedTaskfile:     t.IncludedTaskfile.DeepCopy(),
 		Taskfile:             t.Taskfile,
+		Namespace:            t.Namespace,
+		NodeVersions:         deepCopySlice(t.NodeVersions),
+		EnvPassthrough:       deepCopySlice(t.EnvPassthrough),
+		EnvBlock:             deepCopySlice(t.EnvBlock),
+		MsgSuccess:           t.MsgSuccess,
+		MsgFailure:           t.MsgFailure,
+		Metadata:             deepCopyMap(t.Metadata),
+		Sandbox:              t.Sandbox.DeepCopy(),
+		Stdin:                t.Stdin.DeepCopy(),
+		Retry:                t.Retry.DeepCopy(),
+		Timeout:              t.Timeout,
+		OncePer:              deepCopySlice(t.OncePer),
+		Matrix:               t.Matrix.DeepCopy(),
+		VarsHash:             t.VarsHash,
+		Location:             t.Location,
+		Locations:            t.Locations,
 	}
 	return c
 }
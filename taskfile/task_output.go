@@ -0,0 +1,49 @@
+package taskfile
+
+import (
+	"errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidTaskOutput is returned when an output of a task doesn't set
+// exactly one of "file" and "value"
+var ErrInvalidTaskOutput = errors.New(`task: Outputs must set exactly one of "file" and "value"`)
+
+// TaskOutput is something produced by a task that other tasks can use with
+// the "output" template function
+type TaskOutput struct {
+	// File is a file created by the task, relative to its directory
+	File string
+	// Value is a value computed by the task
+	Value string
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (o *TaskOutput) UnmarshalYAML(node *yaml.Node) error {
+	var file string
+	if err := node.Decode(&file); err == nil {
+		o.File = file
+		return nil
+	}
+
+	var output struct {
+		File  string
+		Value string
+	}
+	if err := node.Decode(&output); err != nil {
+		return err
+	}
+	if (output.File == "") == (output.Value == "") {
+		return ErrInvalidTaskOutput
+	}
+	o.File = output.File
+	o.Value = output.Value
+	return nil
+}
+
+// Input is an output of another task used by a task
+type Input struct {
+	Task   string
+	Output string
+}
@@ -0,0 +1,33 @@
+package taskfile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+func TestTaskOutputParse(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected *taskfile.TaskOutput
+	}{
+		{"bin/app", &taskfile.TaskOutput{File: "bin/app"}},
+		{"file: bin/app", &taskfile.TaskOutput{File: "bin/app"}},
+		{"value: v1.2.3", &taskfile.TaskOutput{Value: "v1.2.3"}},
+	}
+	for _, test := range tests {
+		var output taskfile.TaskOutput
+		err := yaml.Unmarshal([]byte(test.content), &output)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, &output)
+	}
+
+	for _, content := range []string{"{}", "{file: bin/app, value: v1.2.3}"} {
+		var output taskfile.TaskOutput
+		err := yaml.Unmarshal([]byte(content), &output)
+		assert.ErrorIs(t, err, taskfile.ErrInvalidTaskOutput, content)
+	}
+}
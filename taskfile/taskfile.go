@@ -3,6 +3,8 @@ package taskfile
 import (
 	"fmt"
 	"strconv"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Taskfile represents a Taskfile.yml
@@ -16,29 +18,69 @@ type Taskfile struct {
 	Env        *Vars
 	Tasks      Tasks
 	Silent     bool
-	Dotenv     []string
-	Run        string
-	Interval   string
+	Dotenv     []DotenvFile
+	// DotenvOverride is whether the values of dotenv files replace the
+	// environment variables already set, unless set per file
+	DotenvOverride bool
+	Run            string
+	Interval       string
+	TaskNameCase   string
+	ToolVersions   string
+	// Sort is the default order of the listed tasks
+	Sort string
+	// OnInterrupt is the task run when a run is interrupted by a signal, to
+	// clean up what it left behind
+	OnInterrupt string
+	// ChecksumScope is what the checksums of sources are kept apart by:
+	// "project", "worktree" or "branch"
+	ChecksumScope string
+	// Notifications are the webhooks called when a run finishes. Only the
+	// ones of the entrypoint are used.
+	Notifications []*Notification
+	// Snippets are named lists of commands that tasks can use in their own
+	Snippets map[string][]*Cmd
+	// Params are the vars the Taskfile expects to be given when included
+	Params *Params
+	// Package describes how the Taskfile is bundled by "task --package"
+	Package *Package
+	// BrokenIncludes are the included Taskfiles that couldn't be read, which
+	// are skipped when reading leniently
+	BrokenIncludes []*BrokenInclude
+	// IncludedParams are the params of the included Taskfiles, by namespace
+	IncludedParams map[string]*Params
+	// IncludeTree is the Taskfile as read, with the tree of its includes
+	IncludeTree *IncludeNode
+	Locations   Locations
 }
 
 // UnmarshalYAML implements yaml.Unmarshaler interface
-func (tf *Taskfile) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (tf *Taskfile) UnmarshalYAML(node *yaml.Node) error {
 	var taskfile struct {
-		Version    string
-		Expansions int
-		Output     Output
-		Method     string
-		Includes   *IncludedTaskfiles
-		Vars       *Vars
-		Env        *Vars
-		Tasks      Tasks
-		Silent     bool
-		Dotenv     []string
-		Run        string
-		Interval   string
+		Version        string
+		Expansions     int
+		Output         Output
+		Method         string
+		Includes       *IncludedTaskfiles
+		Vars           *Vars
+		Env            *Vars
+		Tasks          Tasks
+		Snippets       map[string][]*Cmd
+		Silent         bool
+		Dotenv         []DotenvFile
+		DotenvOverride bool `yaml:"dotenv_override"`
+		Run            string
+		Interval       string
+		TaskNameCase   string `yaml:"task_name_case"`
+		ToolVersions   string `yaml:"tool_versions"`
+		Sort           string
+		OnInterrupt    string `yaml:"on_interrupt"`
+		ChecksumScope  string `yaml:"checksum_scope"`
+		Notifications  []*Notification
+		Params         *Params
+		Package        *Package
 	}
 
-	if err := unmarshal(&taskfile); err != nil {
+	if err := node.Decode(&taskfile); err != nil {
 		return err
 	}
 
@@ -50,10 +92,21 @@ func (tf *Taskfile) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	tf.Vars = taskfile.Vars
 	tf.Env = taskfile.Env
 	tf.Tasks = taskfile.Tasks
+	tf.Snippets = taskfile.Snippets
 	tf.Silent = taskfile.Silent
 	tf.Dotenv = taskfile.Dotenv
+	tf.DotenvOverride = taskfile.DotenvOverride
 	tf.Run = taskfile.Run
 	tf.Interval = taskfile.Interval
+	tf.TaskNameCase = taskfile.TaskNameCase
+	tf.ToolVersions = taskfile.ToolVersions
+	tf.Sort = taskfile.Sort
+	tf.OnInterrupt = taskfile.OnInterrupt
+	tf.ChecksumScope = taskfile.ChecksumScope
+	tf.Notifications = taskfile.Notifications
+	tf.Params = taskfile.Params
+	tf.Package = taskfile.Package
+	tf.Locations = keyLocations(node)
 
 	if tf.Expansions <= 0 {
 		tf.Expansions = 2
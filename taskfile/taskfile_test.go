@@ -21,6 +21,8 @@ vars:
 `
 		yamlDeferredCall = `defer: { task: some_task, vars: { PARAM1: "var" } }`
 		yamlDeferredCmd  = `defer: echo 'test'`
+		yamlTTYCmd       = `{cmd: docker exec -it app sh, tty: true}`
+		yamlInteractive  = `{cmd: ssh host, interactive: true}`
 	)
 	tests := []struct {
 		content  string
@@ -30,7 +32,7 @@ vars:
 		{
 			yamlCmd,
 			&taskfile.Cmd{},
-			&taskfile.Cmd{Cmd: `echo "a string command"`},
+			&taskfile.Cmd{Cmd: `echo "a string command"`, Location: taskfile.Location{Line: 1, Column: 1}},
 		},
 		{
 			yamlTaskCall,
@@ -41,12 +43,12 @@ vars:
 					"PARAM1": taskfile.Var{Static: "VALUE1"},
 					"PARAM2": taskfile.Var{Static: "VALUE2"},
 				},
-			}},
+			}, Location: taskfile.Location{Line: 2, Column: 1}},
 		},
 		{
 			yamlDeferredCmd,
 			&taskfile.Cmd{},
-			&taskfile.Cmd{Cmd: "echo 'test'", Defer: true},
+			&taskfile.Cmd{Cmd: "echo 'test'", Defer: true, Location: taskfile.Location{Line: 1, Column: 1}},
 		},
 		{
 			yamlDeferredCall,
@@ -56,12 +58,22 @@ vars:
 				Mapping: map[string]taskfile.Var{
 					"PARAM1": taskfile.Var{Static: "var"},
 				},
-			}, Defer: true},
+			}, Defer: true, Location: taskfile.Location{Line: 1, Column: 1}},
+		},
+		{
+			yamlTTYCmd,
+			&taskfile.Cmd{},
+			&taskfile.Cmd{Cmd: "docker exec -it app sh", TTY: true, Location: taskfile.Location{Line: 1, Column: 1}},
+		},
+		{
+			yamlInteractive,
+			&taskfile.Cmd{},
+			&taskfile.Cmd{Cmd: "ssh host", TTY: true, Location: taskfile.Location{Line: 1, Column: 1}},
 		},
 		{
 			yamlDep,
 			&taskfile.Dep{},
-			&taskfile.Dep{Task: "task-name"},
+			&taskfile.Dep{Task: "task-name", Location: taskfile.Location{Line: 1, Column: 1}},
 		},
 		{
 			yamlTaskCall,
@@ -72,7 +84,7 @@ vars:
 					"PARAM1": taskfile.Var{Static: "VALUE1"},
 					"PARAM2": taskfile.Var{Static: "VALUE2"},
 				},
-			}},
+			}, Location: taskfile.Location{Line: 2, Column: 1}},
 		},
 	}
 	for _, test := range tests {
@@ -81,3 +93,196 @@ vars:
 		assert.Equal(t, test.expected, test.v)
 	}
 }
+
+func TestAnchorsAndMergeKeys(t *testing.T) {
+	const yamlTaskfile = `
+version: '3'
+
+x-common-vars: &common-vars
+  A: common a
+  B: common b
+
+x-extra-vars: &extra-vars
+  B: extra b
+  C: extra c
+
+includes:
+  <<:
+    lib: ./lib
+  docs: ./docs
+
+vars:
+  <<: [*common-vars, *extra-vars]
+  D: d
+
+tasks:
+  base: &base
+    desc: shared description
+    dir: some/dir
+    vars:
+      <<: *common-vars
+      A: overridden a
+    cmds:
+      - &echo echo {{.A}}
+      - *echo
+
+  copy:
+    <<: *base
+    desc: own description
+
+  with-flags:
+    flags:
+      <<: &flags
+        force:
+          type: bool
+      name: {}
+`
+	var tf taskfile.Taskfile
+	assert.NoError(t, yaml.Unmarshal([]byte(yamlTaskfile), &tf))
+
+	assert.Equal(t, []string{"A", "B", "C", "D"}, tf.Vars.Keys)
+	assert.Equal(t, "common a", tf.Vars.Mapping["A"].Static)
+	assert.Equal(t, "common b", tf.Vars.Mapping["B"].Static)
+	assert.Equal(t, "extra c", tf.Vars.Mapping["C"].Static)
+
+	assert.Equal(t, []string{"lib", "docs"}, tf.Includes.Keys)
+	assert.Equal(t, "./lib", tf.Includes.Mapping["lib"].Taskfile)
+
+	base := tf.Tasks["base"]
+	assert.Equal(t, []string{"A", "B"}, base.Vars.Keys)
+	assert.Equal(t, "overridden a", base.Vars.Mapping["A"].Static)
+	assert.Len(t, base.Cmds, 2)
+	assert.Equal(t, "echo {{.A}}", base.Cmds[1].Cmd)
+
+	copied := tf.Tasks["copy"]
+	assert.Equal(t, "own description", copied.Desc)
+	assert.Equal(t, "some/dir", copied.Dir)
+	assert.Equal(t, base.Vars, copied.Vars)
+	assert.Len(t, copied.Cmds, 2)
+
+	flags := tf.Tasks["with-flags"].Flags
+	assert.Equal(t, []string{"force", "name"}, flags.Keys)
+	assert.Equal(t, "bool", flags.Mapping["force"].Type)
+}
+
+func TestInvalidMergeKey(t *testing.T) {
+	var vars taskfile.Vars
+	err := yaml.Unmarshal([]byte("<<: [not, a, map]"), &vars)
+	assert.ErrorContains(t, err, "merge key at line 1 must be a map or a list of maps")
+}
+
+func TestLocations(t *testing.T) {
+	const yamlTaskfile = `
+version: '3'
+
+interval: 1x
+
+tasks:
+  default:
+    run: sometimes
+    deps: [a]
+    cmds:
+      - echo default
+`
+	var tf taskfile.Taskfile
+	assert.NoError(t, yaml.Unmarshal([]byte(yamlTaskfile), &tf))
+
+	assert.Equal(t, taskfile.Location{Line: 4, Column: 11}, tf.Locations["interval"])
+	task := tf.Tasks["default"]
+	assert.Equal(t, taskfile.Location{Line: 8, Column: 10}, task.Locations["run"])
+	assert.Equal(t, taskfile.Location{Line: 9, Column: 12}, task.Deps[0].Location)
+	assert.Equal(t, taskfile.Location{Line: 11, Column: 9}, task.Cmds[0].Location)
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			"vars",
+			"version: '3'\nvars:\n  A: one\n  A: two\n",
+			`line 4: mapping key "A" already defined at line 3`,
+		},
+		{
+			"env",
+			"version: '3'\nenv:\n  A: one\n  B: two\n  A: three\n",
+			`line 5: mapping key "A" already defined at line 3`,
+		},
+		{
+			"task vars",
+			"version: '3'\ntasks:\n  default:\n    vars:\n      A: one\n      A: two\n",
+			`line 6: mapping key "A" already defined at line 5`,
+		},
+		{
+			"tasks",
+			"version: '3'\ntasks:\n  default: echo one\n  default: echo two\n",
+			`line 4: mapping key "default" already defined at line 3`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var tf taskfile.Taskfile
+			err := yaml.Unmarshal([]byte(test.content), &tf)
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+
+	// Keys overriding merged ones are not duplicates
+	var vars taskfile.Vars
+	assert.NoError(t, yaml.Unmarshal([]byte("<<: {A: one}\nA: two\n"), &vars))
+	assert.Equal(t, "two", vars.Mapping["A"].Static)
+}
+
+func TestDotenvParse(t *testing.T) {
+	var tf taskfile.Taskfile
+	assert.NoError(t, yaml.Unmarshal([]byte("version: '3'\ndotenv_override: true\ndotenv:\n  - .env\n  - path: .env.local\n    override: false\n"), &tf))
+	assert.True(t, tf.DotenvOverride)
+	assert.Len(t, tf.Dotenv, 2)
+	assert.Equal(t, taskfile.DotenvFile{Path: ".env"}, tf.Dotenv[0])
+	assert.Equal(t, ".env.local", tf.Dotenv[1].Path)
+	assert.False(t, *tf.Dotenv[1].Override)
+
+	err := yaml.Unmarshal([]byte("version: '3'\ndotenv:\n  - override: true\n"), &tf)
+	assert.ErrorContains(t, err, `task: dotenv files must have a "path"`)
+}
+
+func TestStdinParse(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected *taskfile.Stdin
+	}{
+		{"cmds: [cat]", nil},
+		{"{stdin: inherit, cmds: [cat]}", &taskfile.Stdin{Policy: taskfile.StdinInherit}},
+		{"{stdin: null, cmds: [cat]}", &taskfile.Stdin{Policy: taskfile.StdinNull}},
+		{"{stdin: {file: input.txt}, cmds: [cat]}", &taskfile.Stdin{Policy: taskfile.StdinFile, File: "input.txt"}},
+	}
+	for _, test := range tests {
+		var task taskfile.Task
+		assert.NoError(t, yaml.Unmarshal([]byte(test.content), &task))
+		assert.Equal(t, test.expected, task.Stdin)
+	}
+
+	var task taskfile.Task
+	assert.EqualError(t, yaml.Unmarshal([]byte("{stdin: keyboard, cmds: [cat]}"), &task), `task: Invalid stdin "keyboard", expected "inherit", "null" or a file`)
+}
+
+func TestResolveReference(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		expected  string
+	}{
+		{"build", "", "build"},
+		{"build", "docker", "build"},
+		{".:build", "", "build"},
+		{".:build", "docker", "docker:build"},
+		{".:build", "ci:docker", "ci:docker:build"},
+		{":build", "docker", "build"},
+		{"::build", "docker", "build"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, taskfile.ResolveReference(test.name, test.namespace), "%s in %q", test.name, test.namespace)
+	}
+}
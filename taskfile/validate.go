@@ -0,0 +1,404 @@
+package taskfile
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError is a value of a Taskfile that doesn't have the structure
+// Task expects, like a string where a list is expected
+type ValidationError struct {
+	Location Location
+	// Key is the path of the value, like "tasks.build.cmds"
+	Key     string
+	Message string
+	// Suggestion tells how to fix the value, if known
+	Suggestion string
+}
+
+func (err *ValidationError) Error() string {
+	msg := fmt.Sprintf("task: %s: %s: %s", err.Location, err.Key, err.Message)
+	if err.Suggestion != "" {
+		msg += "\n  " + err.Suggestion
+	}
+	return msg
+}
+
+// ValidationErrors are all the problems found in a Taskfile
+type ValidationErrors []*ValidationError
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// SetTaskfile sets the path of the Taskfile of the location of all errors
+func (errs ValidationErrors) SetTaskfile(taskfile string) {
+	for _, err := range errs {
+		err.Location.Taskfile = taskfile
+	}
+}
+
+// shape is the structure expected for a value of a Taskfile. A value may
+// have more than one form, like a command given as a string or as a map.
+type shape struct {
+	// any accepts any value, for values whose structure isn't checked
+	any bool
+	// scalar accepts strings, numbers and booleans, or only the values with
+	// the given tag when set
+	scalar bool
+	tag    string
+	// fields are the keys of a map with known keys
+	fields map[string]*shape
+	// values is the shape of the values of a map with any keys
+	values *shape
+	// items is the shape of the items of a list
+	items *shape
+
+	// name describes the value, like "a list of commands"
+	name string
+	// example shows a valid value, used as suggestion
+	example string
+}
+
+var (
+	anyShape     = &shape{any: true}
+	stringShape  = &shape{scalar: true, name: "a string"}
+	boolShape    = &shape{scalar: true, tag: "!!bool", name: "a boolean", example: "true"}
+	intShape     = &shape{scalar: true, tag: "!!int", name: "a number", example: "2"}
+	stringsShape = &shape{items: stringShape, name: "a list of strings", example: "[a, b]"}
+	varsShape    = &shape{values: anyShape, name: "a map of variables", example: "{NAME: value}"}
+
+	cmdShape = &shape{
+		scalar: true,
+		fields: map[string]*shape{
+			"cmd":          stringShape,
+			"silent":       boolShape,
+			"privileged":   boolShape,
+			"tty":          boolShape,
+			"interactive":  boolShape,
+			"ignore_error": boolShape,
+			"defer":        anyShape,
+			"task":         stringShape,
+			"vars":         varsShape,
+			"use":          stringShape,
+			"fetch":        anyShape,
+			"verify":       anyShape,
+			"wait_for":     anyShape,
+			"copy":         anyShape,
+			"mkdir":        stringShape,
+			"rm":           stringShape,
+			"template":     anyShape,
+		},
+		name:    "a command",
+		example: "echo hello",
+	}
+	cmdsShape = &shape{items: cmdShape, name: "a list of commands", example: "[echo hello]"}
+
+	depShape = &shape{
+		scalar: true,
+		fields: map[string]*shape{
+			"task":     stringShape,
+			"vars":     varsShape,
+			"optional": boolShape,
+		},
+		name:    "a dependency",
+		example: "build",
+	}
+	depsShape = &shape{items: depShape, name: "a list of dependencies", example: "[build]"}
+
+	taskShape = &shape{
+		scalar: true,
+		items:  cmdShape,
+		fields: map[string]*shape{
+			"cmds":            cmdsShape,
+			"deps":            depsShape,
+			"label":           stringShape,
+			"desc":            stringShape,
+			"summary":         stringShape,
+			"aliases":         stringsShape,
+			"tags":            stringsShape,
+			"icon":            anyShape,
+			"sources":         stringsShape,
+			"generates":       stringsShape,
+			"artifacts":       &shape{items: anyShape, name: "a list of artifacts"},
+			"outputs":         &shape{values: anyShape, name: "a map of outputs"},
+			"umask":           stringShape,
+			"status":          stringsShape,
+			"preconditions":   &shape{items: anyShape, name: "a list of preconditions", example: "[test -f .env]"},
+			"dir":             stringShape,
+			"vars":            varsShape,
+			"env":             varsShape,
+			"env_file":        &shape{scalar: true, items: stringShape, name: "a file or a list of files", example: ".env"},
+			"silent":          boolShape,
+			"interactive":     boolShape,
+			"internal":        boolShape,
+			"method":          stringShape,
+			"prefix":          stringShape,
+			"ignore_error":    boolShape,
+			"run":             stringShape,
+			"memoize":         boolShape,
+			"wsl":             boolShape,
+			"nix":             anyShape,
+			"privileged":      boolShape,
+			"notify":          boolShape,
+			"tmpdir":          boolShape,
+			"flags":           &shape{values: anyShape, name: "a map of flags"},
+			"env_passthrough": stringsShape,
+			"env_block":       stringsShape,
+			"msg_success":     stringShape,
+			"msg_failure":     stringShape,
+			"metadata":        &shape{values: stringShape, name: "a map of strings", example: "{owner: team}"},
+			"sandbox":         anyShape,
+			"stdin":           anyShape,
+			"retry": &shape{
+				scalar: true,
+				tag:    "!!int",
+				fields: map[string]*shape{
+					"count":   intShape,
+					"delay":   stringShape,
+					"backoff": &shape{scalar: true, name: "a number", example: "2"},
+				},
+				name:    "a number of retries",
+				example: "3",
+			},
+			"timeout":  stringShape,
+			"once_per": &shape{scalar: true, items: stringShape, name: "a scope or a list of scopes", example: "day"},
+			"matrix":   &shape{values: anyShape, name: "a map of variables to lists of values", example: "{OS: [linux, darwin]}"},
+		},
+		name:    "a task",
+		example: "{cmds: [echo hello]}",
+	}
+
+	taskfileShape = &shape{
+		fields: map[string]*shape{
+			"version":         stringShape,
+			"expansions":      intShape,
+			"output":          anyShape,
+			"method":          stringShape,
+			"includes":        &shape{values: anyShape, name: "a map of includes", example: "{docs: ./docs}"},
+			"vars":            varsShape,
+			"env":             varsShape,
+			"tasks":           &shape{values: taskShape, name: "a map of tasks", example: "{build: {cmds: [go build]}}"},
+			"snippets":        &shape{values: cmdsShape, name: "a map of snippets"},
+			"silent":          boolShape,
+			"dotenv":          &shape{items: anyShape, name: "a list of files", example: "[.env]"},
+			"dotenv_override": boolShape,
+			"run":             stringShape,
+			"interval":        stringShape,
+			"task_name_case":  stringShape,
+			"tool_versions":   stringShape,
+			"sort":            stringShape,
+			"on_interrupt":    stringShape,
+			"checksum_scope":  stringShape,
+			"notifications":   &shape{items: anyShape, name: "a list of notifications"},
+			"params":          anyShape,
+			"package":         anyShape,
+		},
+		name: "a map",
+	}
+)
+
+func init() {
+	// Serial dependencies are a list of dependencies themselves
+	depShape.fields["serial"] = depsShape
+}
+
+// Validate checks the structure of a Taskfile, given as a node decoded
+// from YAML, returning the problems found. Unknown keys are only reported
+// when strict, since older versions of Task ignore them. Taskfiles before
+// version 3 aren't checked.
+func Validate(node *yaml.Node, strict bool) ValidationErrors {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if content, err := mappingContent(node); err == nil {
+		for i := 0; i < len(content); i += 2 {
+			if content[i].Value != "version" {
+				continue
+			}
+			if v, err := strconv.ParseFloat(content[i+1].Value, 64); err == nil && v < 3 {
+				return nil
+			}
+		}
+	}
+
+	v := &validator{strict: strict}
+	v.check(node, taskfileShape, "")
+	return v.errs
+}
+
+type validator struct {
+	strict bool
+	errs   ValidationErrors
+}
+
+func (v *validator) check(node *yaml.Node, s *shape, key string) {
+	node = resolveAlias(node)
+	if s.any || node.ShortTag() == "!!null" {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if s.scalar && (s.tag == "" || node.ShortTag() == s.tag) {
+			return
+		}
+	case yaml.SequenceNode:
+		if s.items != nil {
+			for i, item := range node.Content {
+				v.check(item, s.items, fmt.Sprintf("%s[%d]", key, i))
+			}
+			return
+		}
+	case yaml.MappingNode:
+		if s.fields != nil {
+			v.checkFields(node, s, key)
+			return
+		}
+		if s.values != nil {
+			content, _ := mappingContent(node)
+			for i := 0; i < len(content); i += 2 {
+				v.check(content[i+1], s.values, joinKey(key, content[i].Value))
+			}
+			return
+		}
+	}
+
+	err := &ValidationError{
+		Location: locationOf(node),
+		Key:      displayKey(key),
+		Message:  fmt.Sprintf("expected %s, got %s", s.name, describe(node)),
+	}
+	if s.example != "" {
+		err.Suggestion = fmt.Sprintf("Write it like %q", lastKey(key)+": "+s.example)
+	}
+	v.errs = append(v.errs, err)
+}
+
+func (v *validator) checkFields(node *yaml.Node, s *shape, key string) {
+	content, err := mappingContent(node)
+	if err != nil {
+		return
+	}
+	for i := 0; i < len(content); i += 2 {
+		keyNode, valueNode := content[i], content[i+1]
+		field, ok := s.fields[keyNode.Value]
+		if ok {
+			v.check(valueNode, field, joinKey(key, keyNode.Value))
+			continue
+		}
+		if !v.strict {
+			continue
+		}
+		err := &ValidationError{
+			Location: locationOf(keyNode),
+			Key:      displayKey(joinKey(key, keyNode.Value)),
+			Message:  "unknown key",
+		}
+		if suggestion := closestKey(keyNode.Value, s.fields); suggestion != "" {
+			err.Suggestion = fmt.Sprintf("Did you mean %q?", suggestion)
+		}
+		v.errs = append(v.errs, err)
+	}
+}
+
+func joinKey(key, name string) string {
+	if key == "" {
+		return name
+	}
+	return key + "." + name
+}
+
+// displayKey returns the key shown in errors, which is "the Taskfile" for
+// the root
+func displayKey(key string) string {
+	if key == "" {
+		return "the Taskfile"
+	}
+	return key
+}
+
+// lastKey returns the name of the value of the given key, like "cmds" for
+// "tasks.build.cmds"
+func lastKey(key string) string {
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		key = key[i+1:]
+	}
+	if i := strings.IndexByte(key, '['); i >= 0 {
+		key = key[:i]
+	}
+	return key
+}
+
+func describe(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		return "a list"
+	case yaml.MappingNode:
+		return "a map"
+	}
+	switch node.ShortTag() {
+	case "!!bool":
+		return "a boolean"
+	case "!!int", "!!float":
+		return "a number"
+	default:
+		return "a string"
+	}
+}
+
+// closestKey returns the known key closest to the given unknown one, if it
+// looks like a typo of it
+func closestKey(key string, fields map[string]*shape) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best, bestDistance := "", 3
+	for _, name := range names {
+		if d := levenshtein(strings.ToLower(key), name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
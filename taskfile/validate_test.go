@@ -0,0 +1,63 @@
+package taskfile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+func TestValidate(t *testing.T) {
+	const content = `
+version: '3'
+
+vars: &vars
+  NAME: value
+
+tasks:
+  build:
+    cmds: echo hello
+    sorces: [a.go]
+    silent: maybe
+  test:
+    vars: *vars
+    deps:
+      - build
+      - {task: build, optinal: true}
+      - serial: [build, {task: build, vrs: {}}]
+`
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(content), &node))
+
+	errs := taskfile.Validate(&node, false)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "tasks.build.cmds", errs[0].Key)
+	assert.Equal(t, "expected a list of commands, got a string", errs[0].Message)
+	assert.Equal(t, 9, errs[0].Location.Line)
+	assert.Equal(t, 11, errs[0].Location.Column)
+	assert.Equal(t, `Write it like "cmds: [echo hello]"`, errs[0].Suggestion)
+	assert.Equal(t, "tasks.build.silent", errs[1].Key)
+	assert.Equal(t, "expected a boolean, got a string", errs[1].Message)
+
+	errs = taskfile.Validate(&node, true)
+	require.Len(t, errs, 5)
+	assert.Equal(t, "tasks.build.sorces", errs[1].Key)
+	assert.Equal(t, "unknown key", errs[1].Message)
+	assert.Equal(t, `Did you mean "sources"?`, errs[1].Suggestion)
+	assert.Equal(t, "tasks.test.deps[1].optinal", errs[3].Key)
+	assert.Equal(t, `Did you mean "optional"?`, errs[3].Suggestion)
+	assert.Equal(t, "tasks.test.deps[2].serial[1].vrs", errs[4].Key)
+	assert.Equal(t, `Did you mean "vars"?`, errs[4].Suggestion)
+
+	errs.SetTaskfile("Taskfile.yml")
+	assert.Equal(t, "task: Taskfile.yml:9:11: tasks.build.cmds: expected a list of commands, got a string\n  Write it like \"cmds: [echo hello]\"", errs[0].Error())
+}
+
+func TestValidateSkipsOldVersions(t *testing.T) {
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte("version: '2'\nunknown: true\n"), &node))
+	assert.Empty(t, taskfile.Validate(&node, true))
+}
@@ -22,9 +22,13 @@ func (vs *Vars) UnmarshalYAML(node *yaml.Node) error {
 	// NOTE(@andreynering): on this style of custom unmarsheling,
 	// even number contains the keys, while odd numbers contains
 	// the values.
-	for i := 0; i < len(node.Content); i += 2 {
-		keyNode := node.Content[i]
-		valueNode := node.Content[i+1]
+	content, err := mappingContent(node)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(content); i += 2 {
+		keyNode := content[i]
+		valueNode := content[i+1]
 
 		var v Var
 		if err := valueNode.Decode(&v); err != nil {
@@ -108,12 +112,18 @@ func (vs *Vars) Len() int {
 	return len(vs.Keys)
 }
 
+// RedactedValue is displayed instead of the value of secret variables
+const RedactedValue = "*****"
+
 // Var represents either a static or dynamic variable.
 type Var struct {
 	Static string
 	Live   interface{}
 	Sh     string
 	Dir    string
+	Prompt string
+	Secret bool
+	Enum   []string
 }
 
 // UnmarshalYAML implements yaml.Unmarshaler interface.
@@ -125,11 +135,23 @@ func (v *Var) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	}
 
 	var sh struct {
-		Sh string
+		Sh     string
+		Prompt string
+		Secret bool
+		Enum   []string
 	}
 	if err := unmarshal(&sh); err != nil {
 		return err
 	}
 	v.Sh = sh.Sh
+	v.Prompt = sh.Prompt
+	v.Secret = sh.Secret
+	v.Enum = sh.Enum
 	return nil
 }
+
+// NeedsPrompt returns true if the variable has no value and should be
+// asked to the user when not set elsewhere.
+func (v Var) NeedsPrompt() bool {
+	return v.Static == "" && v.Sh == "" && v.Live == nil && (v.Prompt != "" || v.Secret || len(v.Enum) > 0)
+}
@@ -0,0 +1,15 @@
+package taskfile
+
+// Verify is a check of a file against a checksum or a signature, run by a
+// command instead of a shell command
+type Verify struct {
+	File string
+	// Checksum is the expected checksum of the file, like "sha256:<hex>"
+	Checksum string
+	// ChecksumsFile is a file with the checksums of several files, in the
+	// format written by sha256sum
+	ChecksumsFile string `yaml:"checksums_file"`
+	// Signature is a cosign signature of the file, verified with Key
+	Signature string
+	Key       string
+}
@@ -0,0 +1,17 @@
+package taskfile
+
+// WaitFor is a condition a command waits for, instead of running a shell
+// command. Exactly one of TCP, HTTP, File and Cmd is set.
+type WaitFor struct {
+	// TCP is an address, like "localhost:5432", that accepts connections
+	TCP string
+	// HTTP is a URL that responds with a 2xx status
+	HTTP string
+	// File is a path that exists
+	File string
+	// Cmd is a shell command that succeeds
+	Cmd string
+	// Timeout and Interval are durations, like "30s"
+	Timeout  string
+	Interval string
+}
@@ -0,0 +1,83 @@
+package taskfile
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mappingContent returns the keys and values of the given mapping node, like
+// its Content, but with merge keys ("<<") resolved, so the custom decoders
+// that iterate over the nodes support them like the standard decoding does.
+// As defined by the YAML spec, keys set explicitly take precedence over merged
+// ones, and mappings listed first take precedence over the following ones.
+func mappingContent(node *yaml.Node) ([]*yaml.Node, error) {
+	node = resolveAlias(node)
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("task: expected a map at line %d", node.Line)
+	}
+
+	var (
+		content      []*yaml.Node
+		index        = make(map[string]int, len(node.Content)/2)
+		explicit     []*yaml.Node
+		explicitKeys = make(map[string]*yaml.Node, len(node.Content)/2)
+	)
+	add := func(keyNode, valueNode *yaml.Node, override bool) {
+		if i, ok := index[keyNode.Value]; ok {
+			if override {
+				content[i+1] = valueNode
+			}
+			return
+		}
+		index[keyNode.Value] = len(content)
+		content = append(content, keyNode, valueNode)
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := resolveAlias(node.Content[i])
+		valueNode := node.Content[i+1]
+		if !isMergeKey(keyNode) {
+			// Like the standard decoding of maps, duplicated keys are an
+			// error instead of silently keeping the last value
+			if previous, ok := explicitKeys[keyNode.Value]; ok {
+				return nil, fmt.Errorf("task: line %d: mapping key %q already defined at line %d", keyNode.Line, keyNode.Value, previous.Line)
+			}
+			explicitKeys[keyNode.Value] = keyNode
+			explicit = append(explicit, keyNode, valueNode)
+			continue
+		}
+
+		merged := []*yaml.Node{valueNode}
+		if valueNode = resolveAlias(valueNode); valueNode.Kind == yaml.SequenceNode {
+			merged = valueNode.Content
+		}
+		for _, m := range merged {
+			if resolveAlias(m).Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("task: merge key at line %d must be a map or a list of maps", keyNode.Line)
+			}
+			mergedContent, err := mappingContent(m)
+			if err != nil {
+				return nil, err
+			}
+			for j := 0; j < len(mergedContent); j += 2 {
+				add(mergedContent[j], mergedContent[j+1], false)
+			}
+		}
+	}
+	for i := 0; i < len(explicit); i += 2 {
+		add(explicit[i], explicit[i+1], true)
+	}
+	return content, nil
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+func isMergeKey(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Value == "<<" && (node.Tag == "" || node.Tag == "!" || node.Tag == "!!merge" || node.Tag == "tag:yaml.org,2002:merge")
+}
@@ -0,0 +1 @@
+echo "hello from the package"
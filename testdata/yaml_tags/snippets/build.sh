@@ -0,0 +1 @@
+echo "building from a snippet"
@@ -0,0 +1,43 @@
+package task
+
+import (
+	"os"
+	"regexp"
+
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// unsafeTmpdirChars matches the characters of task names that are replaced
+// in the names of their temporary directories
+var unsafeTmpdirChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// withTmpdir creates the temporary directory of a task with "tmpdir: true"
+// and returns the task compiled again with it available as TMP_DIR. The
+// returned function removes the directory.
+func (e *Executor) withTmpdir(t *taskfile.Task, call taskfile.Call) (*taskfile.Task, func(), error) {
+	dir, err := os.MkdirTemp("", "task-"+unsafeTmpdirChars.ReplaceAllString(t.Task, "-")+"-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	e.Logger.Debugf("[%s] created temporary directory %s", e.redact(t.Name()), dir)
+	remove := func() {
+		if err := os.RemoveAll(dir); err != nil {
+			e.Logger.Errf(logger.Yellow, "task: [%s] could not remove temporary directory %s: %v", e.redact(t.Name()), dir, err)
+		}
+	}
+
+	vars := call.Vars.DeepCopy()
+	if vars == nil {
+		vars = &taskfile.Vars{}
+	}
+	vars.Set("TMP_DIR", taskfile.Var{Static: dir})
+	call.Vars = vars
+
+	compiled, err := e.CompiledTask(call)
+	if err != nil {
+		remove()
+		return nil, nil, err
+	}
+	return compiled, remove, nil
+}
@@ -0,0 +1,84 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// taskUmask returns the umask of the task, if set
+func taskUmask(t *taskfile.Task) (os.FileMode, bool, error) {
+	if t.Umask == "" || runtime.GOOS == "windows" {
+		return 0, false, nil
+	}
+	umask, err := strconv.ParseUint(t.Umask, 8, 32)
+	if err != nil || umask > 0o777 {
+		err := fmt.Errorf(`task: invalid umask "%s". It should be an octal number, like "022"`, t.Umask)
+		return 0, false, taskfile.WithLocation(err, t.Locations["umask"])
+	}
+	return os.FileMode(umask), true, nil
+}
+
+// umaskMode returns the permissions a file with the given mode should have
+// with the umask: the ones of an executable when any execute bit is set, of
+// a regular file otherwise
+func umaskMode(mode, umask os.FileMode) os.FileMode {
+	base := os.FileMode(0o666)
+	if mode&0o111 != 0 {
+		base = 0o777
+	}
+	return base &^ umask
+}
+
+// applyUmask sets the permissions given by the umask of the task to the
+// files it generated, so they're the same whatever the umask of the machine
+func (e *Executor) applyUmask(t *taskfile.Task) error {
+	umask, ok, err := taskUmask(t)
+	if err != nil || !ok || e.Dry {
+		return err
+	}
+
+	for _, file := range e.generatedFiles(t) {
+		path := filepathext.SmartJoin(t.Dir, file)
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		mode := umaskMode(info.Mode(), umask)
+		if info.Mode().Perm() == mode {
+			continue
+		}
+		e.Logger.Debugf("[%s] setting the permissions of %s to %04o", e.redact(t.Name()), file, mode)
+		if err := os.Chmod(path, mode); err != nil {
+			return fmt.Errorf("task: Failed to set the permissions of %q: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// isTaskUpToDateUmask checks that the files generated by the task have the
+// permissions given by its umask
+func (e *Executor) isTaskUpToDateUmask(t *taskfile.Task) (bool, error) {
+	umask, ok, err := taskUmask(t)
+	if err != nil || !ok {
+		return true, err
+	}
+
+	for _, file := range e.generatedFiles(t) {
+		info, err := os.Stat(filepathext.SmartJoin(t.Dir, file))
+		if err != nil {
+			return false, nil
+		}
+		if mode := umaskMode(info.Mode(), umask); info.Mode().Perm() != mode {
+			e.Logger.VerboseErrf(logger.Yellow, "task: %s has permissions %04o instead of %04o", file, info.Mode().Perm(), mode)
+			e.Logger.Debugf("[%s] not up to date because %s has permissions %04o instead of %04o", e.redact(t.Name()), file, info.Mode().Perm(), mode)
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/go-task/task/v3/internal/status"
 	"github.com/go-task/task/v3/internal/templater"
 	"github.com/go-task/task/v3/taskfile"
+	"github.com/go-task/task/v3/taskfile/read"
 )
 
 // CompiledTask returns a copy of a task, but replacing variables in almost all
@@ -26,6 +27,10 @@ func (e *Executor) compiledTask(call taskfile.Call, evaluateShVars bool) (*taskf
 	if err != nil {
 		return nil, err
 	}
+	call = withMatchVars(call, origTask)
+	if call, err = withFlagVars(call, origTask, nil); err != nil {
+		return nil, err
+	}
 
 	var vars *taskfile.Vars
 	if evaluateShVars {
@@ -36,13 +41,19 @@ func (e *Executor) compiledTask(call taskfile.Call, evaluateShVars bool) (*taskf
 	if err != nil {
 		return nil, err
 	}
+	e.registerSecrets(vars)
 
 	v, err := e.Taskfile.ParsedVersion()
 	if err != nil {
 		return nil, err
 	}
 
-	r := templater.Templater{Vars: vars, RemoveNoValue: v >= 3.0}
+	var inputs []taskfile.Input
+	r := templater.Templater{
+		Vars:          vars,
+		RemoveNoValue: v >= 3.0,
+		Funcs:         e.outputFuncs(origTask.Namespace, &inputs),
+	}
 
 	new := taskfile.Task{
 		Task:                 origTask.Task,
@@ -50,8 +61,11 @@ func (e *Executor) compiledTask(call taskfile.Call, evaluateShVars bool) (*taskf
 		Desc:                 r.Replace(origTask.Desc),
 		Summary:              r.Replace(origTask.Summary),
 		Aliases:              origTask.Aliases,
+		Tags:                 origTask.Tags,
+		Icon:                 origTask.Icon,
 		Sources:              r.ReplaceSlice(origTask.Sources),
 		Generates:            r.ReplaceSlice(origTask.Generates),
+		Umask:                origTask.Umask,
 		Dir:                  r.Replace(origTask.Dir),
 		Vars:                 nil,
 		Env:                  nil,
@@ -62,9 +76,41 @@ func (e *Executor) compiledTask(call taskfile.Call, evaluateShVars bool) (*taskf
 		Prefix:               r.Replace(origTask.Prefix),
 		IgnoreError:          origTask.IgnoreError,
 		Run:                  r.Replace(origTask.Run),
+		Memoize:              origTask.Memoize,
+		WSL:                  origTask.WSL,
+		Privileged:           origTask.Privileged,
+		Notify:               origTask.Notify,
+		Tmpdir:               origTask.Tmpdir,
+		Flags:                origTask.Flags,
 		IncludeVars:          origTask.IncludeVars,
 		IncludedTaskfileVars: origTask.IncludedTaskfileVars,
 		Taskfile:             origTask.Taskfile,
+		Namespace:            origTask.Namespace,
+		NodeVersions:         origTask.NodeVersions,
+		EnvPassthrough:       origTask.EnvPassthrough,
+		EnvBlock:             origTask.EnvBlock,
+		MsgSuccess:           r.Replace(origTask.MsgSuccess),
+		MsgFailure:           r.Replace(origTask.MsgFailure),
+		Metadata:             origTask.Metadata,
+		Sandbox:              origTask.Sandbox,
+		Stdin:                origTask.Stdin,
+		Timeout:              r.Replace(origTask.Timeout),
+		OncePer:              origTask.OncePer,
+		Matrix:               origTask.Matrix,
+		Location:             origTask.Location,
+		Locations:            origTask.Locations,
+	}
+	if origTask.Stdin != nil {
+		new.Stdin = &taskfile.Stdin{Policy: origTask.Stdin.Policy, File: r.Replace(origTask.Stdin.File)}
+	}
+	if origTask.Matrix != nil && new.Label == "" {
+		new.Label = matrixLabel(origTask, vars)
+	}
+	if origTask.OncePer.Has(taskfile.OncePerVars) {
+		new.VarsHash = varsHash(vars)
+	}
+	if origTask.Retry != nil {
+		new.Retry = &taskfile.Retry{Count: origTask.Retry.Count, Delay: r.Replace(origTask.Retry.Delay), Backoff: origTask.Retry.Backoff}
 	}
 	new.Dir, err = execext.Expand(new.Dir)
 	if err != nil {
@@ -73,12 +119,32 @@ func (e *Executor) compiledTask(call taskfile.Call, evaluateShVars bool) (*taskf
 	if e.Dir != "" {
 		new.Dir = filepathext.SmartJoin(e.Dir, new.Dir)
 	}
+	// Catch-all tasks are displayed with the name they were called with
+	if isCatchAllTask(new.Task) {
+		if new.Label == "" {
+			new.Label = call.Task
+		}
+		if new.Prefix == "" {
+			new.Prefix = call.Task
+		}
+	}
 	if new.Prefix == "" {
 		new.Prefix = new.Task
 	}
+	if origTask.Nix != nil {
+		new.Nix = &taskfile.Nix{Shell: r.Replace(origTask.Nix.Shell)}
+	}
 
 	new.Env = &taskfile.Vars{}
 	new.Env.Merge(r.ReplaceVars(e.Taskfile.Env))
+	if len(origTask.EnvFile) > 0 {
+		new.EnvFile = r.ReplaceSlice(origTask.EnvFile)
+		envFiles, err := read.EnvFiles(new.EnvFile, new.Dir)
+		if err != nil {
+			return nil, err
+		}
+		new.Env.Merge(envFiles)
+	}
 	new.Env.Merge(r.ReplaceVars(origTask.Env))
 	if evaluateShVars {
 		err = new.Env.Range(func(k string, v taskfile.Var) error {
@@ -100,29 +166,50 @@ func (e *Executor) compiledTask(call taskfile.Call, evaluateShVars bool) (*taskf
 			if cmd == nil {
 				continue
 			}
-			new.Cmds = append(new.Cmds, &taskfile.Cmd{
-				Task:        r.Replace(cmd.Task),
-				Silent:      cmd.Silent,
-				Cmd:         r.Replace(cmd.Cmd),
-				Vars:        r.ReplaceVars(cmd.Vars),
-				IgnoreError: cmd.IgnoreError,
-				Defer:       cmd.Defer,
-			})
+			if cmd.Use != "" {
+				cmds, err := e.snippetCmds(cmd, origTask.Namespace, vars, &r)
+				if err != nil {
+					return nil, err
+				}
+				new.Cmds = append(new.Cmds, cmds...)
+				continue
+			}
+			new.Cmds = append(new.Cmds, e.compiledCmd(cmd, origTask.Namespace, &r))
 		}
 	}
 	if len(origTask.Deps) > 0 {
-		new.Deps = make([]*taskfile.Dep, 0, len(origTask.Deps))
-		for _, dep := range origTask.Deps {
-			if dep == nil {
+		new.Deps = e.compiledDeps(origTask.Deps, origTask.Namespace, &r)
+	}
+
+	if len(origTask.Artifacts) > 0 {
+		new.Artifacts = make([]*taskfile.Artifact, 0, len(origTask.Artifacts))
+		for _, artifact := range origTask.Artifacts {
+			if artifact == nil {
 				continue
 			}
-			new.Deps = append(new.Deps, &taskfile.Dep{
-				Task: r.Replace(dep.Task),
-				Vars: r.ReplaceVars(dep.Vars),
+			new.Artifacts = append(new.Artifacts, &taskfile.Artifact{
+				Dir: r.Replace(artifact.Dir),
+				S3:  r.Replace(artifact.S3),
+				// Templated for each file, when {{.ARTIFACT}} is known
+				Cmd: artifact.Cmd,
 			})
 		}
 	}
 
+	if len(origTask.Outputs) > 0 {
+		new.Outputs = make(map[string]*taskfile.TaskOutput, len(origTask.Outputs))
+		for name, output := range origTask.Outputs {
+			if output == nil {
+				continue
+			}
+			compiled := &taskfile.TaskOutput{Value: r.Replace(output.Value)}
+			if output.File != "" {
+				compiled.File = filepathext.SmartJoin(new.Dir, r.Replace(output.File))
+			}
+			new.Outputs[name] = compiled
+		}
+	}
+
 	if len(origTask.Preconditions) > 0 {
 		new.Preconditions = make([]*taskfile.Precondition, 0, len(origTask.Preconditions))
 		for _, precond := range origTask.Preconditions {
@@ -152,5 +239,83 @@ func (e *Executor) compiledTask(call taskfile.Call, evaluateShVars bool) (*taskf
 		new.Status = r.ReplaceSlice(origTask.Status)
 	}
 
+	new.Inputs = inputs
 	return &new, r.Err()
 }
+
+// compiledCmd resolves the name of the task called and replaces the
+// variables of the given command
+func (e *Executor) compiledCmd(cmd *taskfile.Cmd, namespace string, r *templater.Templater) *taskfile.Cmd {
+	compiled := &taskfile.Cmd{
+		Task:        taskfile.ResolveReference(r.Replace(cmd.Task), namespace),
+		Silent:      cmd.Silent,
+		Privileged:  cmd.Privileged,
+		TTY:         cmd.TTY,
+		Cmd:         r.Replace(cmd.Cmd),
+		Vars:        r.ReplaceVars(cmd.Vars),
+		IgnoreError: cmd.IgnoreError,
+		Defer:       cmd.Defer,
+		Location:    cmd.Location,
+	}
+	if cmd.Fetch != nil {
+		compiled.Fetch = &taskfile.Fetch{
+			URL:        r.Replace(cmd.Fetch.URL),
+			Dest:       r.Replace(cmd.Fetch.Dest),
+			Checksum:   r.Replace(cmd.Fetch.Checksum),
+			Executable: cmd.Fetch.Executable,
+		}
+	}
+	if cmd.FileOp != nil {
+		compiled.FileOp = &taskfile.FileOp{
+			Op:   cmd.FileOp.Op,
+			Path: r.Replace(cmd.FileOp.Path),
+			Dest: r.Replace(cmd.FileOp.Dest),
+		}
+	}
+	if cmd.Verify != nil {
+		compiled.Verify = &taskfile.Verify{
+			File:          r.Replace(cmd.Verify.File),
+			Checksum:      r.Replace(cmd.Verify.Checksum),
+			ChecksumsFile: r.Replace(cmd.Verify.ChecksumsFile),
+			Signature:     r.Replace(cmd.Verify.Signature),
+			Key:           r.Replace(cmd.Verify.Key),
+		}
+	}
+	if cmd.WaitFor != nil {
+		compiled.WaitFor = &taskfile.WaitFor{
+			TCP:      r.Replace(cmd.WaitFor.TCP),
+			HTTP:     r.Replace(cmd.WaitFor.HTTP),
+			File:     r.Replace(cmd.WaitFor.File),
+			Cmd:      r.Replace(cmd.WaitFor.Cmd),
+			Timeout:  r.Replace(cmd.WaitFor.Timeout),
+			Interval: r.Replace(cmd.WaitFor.Interval),
+		}
+	}
+	return compiled
+}
+
+// compiledDeps resolves the names and replaces the variables of the given
+// dependencies, including the ones of serial groups
+func (e *Executor) compiledDeps(deps []*taskfile.Dep, namespace string, r *templater.Templater) []*taskfile.Dep {
+	compiled := make([]*taskfile.Dep, 0, len(deps))
+	for _, dep := range deps {
+		if dep == nil {
+			continue
+		}
+		if dep.Serial != nil {
+			compiled = append(compiled, &taskfile.Dep{
+				Serial:   e.compiledDeps(dep.Serial, namespace, r),
+				Optional: dep.Optional,
+				Location: dep.Location,
+			})
+			continue
+		}
+		compiled = append(compiled, &taskfile.Dep{
+			Task:     taskfile.ResolveReference(r.Replace(dep.Task), namespace),
+			Vars:     r.ReplaceVars(dep.Vars),
+			Optional: dep.Optional,
+			Location: dep.Location,
+		})
+	}
+	return compiled
+}
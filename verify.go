@@ -0,0 +1,59 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/verify"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// ErrNothingToVerify is returned when a "verify" command has neither a
+// checksum, a checksums file or a signature
+var ErrNothingToVerify = errors.New(`task: "verify" needs a "checksum", a "checksums_file" or a "signature"`)
+
+// ErrSignatureWithoutKey is returned when a "verify" command has a signature,
+// but no key to verify it with
+var ErrSignatureWithoutKey = errors.New(`task: "verify" needs a "key" to check the "signature"`)
+
+// runVerify checks a file against the checksum or signature of a "verify"
+// command, failing if it doesn't match
+func (e *Executor) runVerify(ctx context.Context, t *taskfile.Task, cmd *taskfile.Cmd) error {
+	v := cmd.Verify
+	file := filepathext.SmartJoin(t.Dir, v.File)
+	if e.Verbose || (!cmd.Silent && !t.Silent && !e.Taskfile.Silent && !e.Silent) {
+		e.Logger.Errf(logger.Green, "task: [%s] verify %s", e.redact(t.Name()), file)
+	}
+	if e.Dry {
+		return nil
+	}
+
+	var err error
+	switch {
+	case v.Checksum != "":
+		var checksum *verify.Checksum
+		if checksum, err = verify.ParseChecksum(v.Checksum); err == nil {
+			err = checksum.File(file)
+		}
+	case v.ChecksumsFile != "":
+		var checksum *verify.Checksum
+		if checksum, err = verify.LookupChecksum(filepathext.SmartJoin(t.Dir, v.ChecksumsFile), v.File); err == nil {
+			err = checksum.File(file)
+		}
+	case v.Signature != "" && v.Key == "":
+		err = ErrSignatureWithoutKey
+	case v.Signature != "":
+		key := v.Key
+		// Keys may also be references to a KMS, like "awskms://..."
+		if !strings.Contains(key, "://") {
+			key = filepathext.SmartJoin(t.Dir, key)
+		}
+		err = verify.Signature(ctx, file, filepathext.SmartJoin(t.Dir, v.Signature), key)
+	default:
+		err = ErrNothingToVerify
+	}
+	return taskfile.WithLocation(err, cmd.Location)
+}
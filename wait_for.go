@@ -0,0 +1,145 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-task/task/v3/internal/execext"
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+const (
+	defaultWaitForTimeout  = time.Minute
+	defaultWaitForInterval = time.Second
+)
+
+// ErrInvalidWaitFor is returned when a "wait_for" command doesn't have
+// exactly one condition
+var ErrInvalidWaitFor = errors.New(`task: "wait_for" needs exactly one of "tcp", "http", "file" or "cmd"`)
+
+// runWaitFor checks the condition of a "wait_for" command every interval,
+// until it's met or the timeout is reached
+func (e *Executor) runWaitFor(ctx context.Context, t *taskfile.Task, cmd *taskfile.Cmd) error {
+	w := cmd.WaitFor
+	check, what, err := e.waitForCheck(t, w)
+	if err != nil {
+		return taskfile.WithLocation(err, cmd.Location)
+	}
+	timeout, err := parseWaitForDuration("timeout", w.Timeout, defaultWaitForTimeout)
+	if err != nil {
+		return taskfile.WithLocation(err, cmd.Location)
+	}
+	interval, err := parseWaitForDuration("interval", w.Interval, defaultWaitForInterval)
+	if err != nil {
+		return taskfile.WithLocation(err, cmd.Location)
+	}
+
+	if e.Verbose || (!cmd.Silent && !t.Silent && !e.Taskfile.Silent && !e.Silent) {
+		e.Logger.Errf(logger.Green, "task: [%s] wait for %s", e.redact(t.Name()), e.redact(what))
+	}
+	if e.Dry {
+		return nil
+	}
+	if w.Cmd != "" {
+		if err := e.checkPolicy(ctx, t, &PolicyCommand{Kind: "wait_for", Cmd: w.Cmd}); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if check(ctx) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("task: Timed out after %s waiting for %s", timeout, e.redact(what))
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitForCheck returns the check of the condition of a "wait_for" command,
+// and its description
+func (e *Executor) waitForCheck(t *taskfile.Task, w *taskfile.WaitFor) (func(context.Context) bool, string, error) {
+	var count int
+	for _, s := range []string{w.TCP, w.HTTP, w.File, w.Cmd} {
+		if s != "" {
+			count++
+		}
+	}
+	if count != 1 {
+		return nil, "", ErrInvalidWaitFor
+	}
+
+	switch {
+	case w.TCP != "":
+		return func(ctx context.Context) bool {
+			var d net.Dialer
+			conn, err := d.DialContext(ctx, "tcp", w.TCP)
+			if err != nil {
+				return false
+			}
+			conn.Close()
+			return true
+		}, "tcp " + w.TCP, nil
+	case w.HTTP != "":
+		return func(ctx context.Context) bool {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.HTTP, nil)
+			if err != nil {
+				return false
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return false
+			}
+			defer resp.Body.Close()
+			return resp.StatusCode >= 200 && resp.StatusCode < 300
+		}, "http " + w.HTTP, nil
+	case w.File != "":
+		path := filepathext.SmartJoin(t.Dir, w.File)
+		return func(context.Context) bool {
+			_, err := os.Stat(path)
+			return err == nil
+		}, "file " + path, nil
+	default:
+		environ := e.getEnviron(t)
+		return func(ctx context.Context) bool {
+			return execext.RunCommand(ctx, &execext.RunCommandOptions{
+				Command: w.Cmd,
+				Dir:     t.Dir,
+				Env:     environ,
+				Stdout:  io.Discard,
+				Stderr:  io.Discard,
+			}) == nil
+		}, "cmd " + w.Cmd, nil
+	}
+}
+
+func parseWaitForDuration(name, value string, def time.Duration) (time.Duration, error) {
+	if value == "" {
+		return def, nil
+	}
+	v, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf(`task: Could not parse wait_for %s "%s": %v`, name, value, err)
+	}
+	if v <= 0 {
+		return 0, fmt.Errorf(`task: wait_for %s must be positive, got "%s"`, name, value)
+	}
+	return v, nil
+}
@@ -7,10 +7,14 @@ import (
 	"os/signal"
 	"path/filepath"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/go-task/task/v3/internal/dashboard"
+	"github.com/go-task/task/v3/internal/filepathext"
 	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/output"
 	"github.com/go-task/task/v3/internal/status"
 	"github.com/go-task/task/v3/taskfile"
 	"github.com/radovskyb/watcher"
@@ -27,22 +31,32 @@ func (e *Executor) watchTasks(calls ...taskfile.Call) error {
 
 	e.Logger.Errf(logger.Green, "task: Started watching for tasks: %s", strings.Join(tasks, ", "))
 
-	ctx, cancel := context.WithCancel(context.Background())
-	for _, c := range calls {
-		c := c
-		go func() {
-			if err := e.RunTask(ctx, c); err != nil && !isContextError(err) {
-				e.Logger.Errf(logger.Red, "%v", err)
-			}
-		}()
+	e.setupWatchOutput(calls)
+
+	runs := newWatchRuns(e, calls)
+
+	w := watcher.New()
+	defer w.Close()
+	w.SetMaxEvents(1)
+
+	if e.Dashboard && e.OutputStyle.Name != "plain" && isTerminal(e.Stdin) && isTerminal(e.Stdout) {
+		stop, err := e.startDashboard(runs, tasks, w)
+		if err != nil {
+			return err
+		}
+		defer stop()
 	}
 
+	runs.run(nil)
+
 	var watchIntervalString string
+	var watchIntervalLocation taskfile.Location
 
 	if e.Interval != "" {
 		watchIntervalString = e.Interval
 	} else if e.Taskfile.Interval != "" {
 		watchIntervalString = e.Taskfile.Interval
+		watchIntervalLocation = e.Taskfile.Locations["interval"]
 	}
 
 	watchInterval := defaultWatchInterval
@@ -51,16 +65,19 @@ func (e *Executor) watchTasks(calls ...taskfile.Call) error {
 		var err error
 		watchInterval, err = parseWatchInterval(watchIntervalString)
 		if err != nil {
-			cancel()
-			return err
+			runs.cancel()
+			return taskfile.WithLocation(err, watchIntervalLocation)
 		}
 	}
 
 	e.Logger.VerboseOutf(logger.Green, "task: Watching for changes every %v", watchInterval)
 
-	w := watcher.New()
-	defer w.Close()
-	w.SetMaxEvents(1)
+	// The Taskfiles are watched before starting, since the watcher may
+	// report files added while it polls as removed
+	if err := e.registerTaskfiles(w, runs); err != nil {
+		runs.cancel()
+		return err
+	}
 
 	closeOnInterrupt(w)
 
@@ -70,19 +87,30 @@ func (e *Executor) watchTasks(calls ...taskfile.Call) error {
 			case event := <-w.Event:
 				e.Logger.VerboseErrf(logger.Magenta, "task: received watch event: %v", event)
 
-				cancel()
-				ctx, cancel = context.WithCancel(context.Background())
+				if runs.isTaskfile(event.Path) {
+					// The runs are stopped first, since they use the Taskfile
+					// being replaced
+					runs.cancel()
+					runs.wait()
+					runs.reloading.Lock()
+					err := e.reloadTaskfile()
+					runs.reloading.Unlock()
+					if err != nil {
+						e.Logger.Errf(logger.Red, "%v", err)
+						e.Logger.Errf(logger.Red, "task: Waiting for the Taskfile to be fixed")
+						continue
+					}
+					e.setupWatchOutput(calls)
+					e.Logger.Errf(logger.Green, "task: Taskfile changed, reloaded it")
+					e.resetExecutions()
+					runs.run(nil)
+					continue
+				}
 
 				e.Compiler.ResetCache()
+				e.resetExecutions()
 
-				for _, c := range calls {
-					c := c
-					go func() {
-						if err := e.RunTask(ctx, c); err != nil && !isContextError(err) {
-							e.Logger.Errf(logger.Red, "%v", err)
-						}
-					}()
-				}
+				runs.run(runs.owners(event.Path))
 			case err := <-w.Error:
 				switch err {
 				case watcher.ErrWatchedFileDeleted:
@@ -90,7 +118,7 @@ func (e *Executor) watchTasks(calls ...taskfile.Call) error {
 					e.Logger.Errf(logger.Red, "%v", err)
 				}
 			case <-w.Closed:
-				cancel()
+				runs.cancel()
 				return
 			}
 		}
@@ -99,9 +127,14 @@ func (e *Executor) watchTasks(calls ...taskfile.Call) error {
 	go func() {
 		// re-register every 5 seconds because we can have new files, but this process is expensive to run
 		for {
-			if err := e.registerWatchedFiles(w, calls...); err != nil {
+			runs.reloading.RLock()
+			if err := e.registerTaskfiles(w, runs); err != nil {
+				e.Logger.Errf(logger.Red, "%v", err)
+			}
+			if err := e.registerWatchedFiles(w, runs, calls...); err != nil {
 				e.Logger.Errf(logger.Red, "%v", err)
 			}
+			runs.reloading.RUnlock()
 			time.Sleep(watchInterval)
 		}
 	}()
@@ -109,6 +142,60 @@ func (e *Executor) watchTasks(calls ...taskfile.Call) error {
 	return w.Start(watchInterval)
 }
 
+// setupWatchOutput prefixes the output of the tasks when watching several
+// of them, since each one is re-run on its own, so their output is told apart
+func (e *Executor) setupWatchOutput(calls []taskfile.Call) {
+	if len(calls) > 1 && !e.OutputStyle.IsSet() {
+		e.Output = output.Prefixed{}
+	}
+}
+
+// reloadTaskfile reads the Taskfile again, with its includes and dotenv
+// files, and prepares it like Setup does. The current Taskfile is kept when
+// the new one is invalid, so watching goes on until it's fixed.
+func (e *Executor) reloadTaskfile() error {
+	var (
+		prevTaskfile        = e.Taskfile
+		prevDir             = e.Dir
+		prevCompiler        = e.Compiler
+		prevTaskvars        = e.taskvars
+		prevFoldedTaskNames = e.foldedTaskNames
+		prevEnvOverrides    = e.envOverrides
+		prevDotenvFiles     = e.dotenvFiles
+		prevPinnedTools     = e.pinnedTools
+		prevShimsPath       = e.shimsPath
+		prevChecksumScope   = e.checksumScope
+		prevFuzzyModel      = e.fuzzyModel
+		prevOutputStyle     = e.OutputStyle
+		prevOutput          = e.Output
+		prevLoggerStdout    = e.Logger.Stdout
+		prevLoggerStderr    = e.Logger.Stderr
+		prevReport          = e.report
+	)
+
+	err := e.setupTaskfile()
+	if err != nil {
+		e.Taskfile = prevTaskfile
+		e.Dir = prevDir
+		e.Compiler = prevCompiler
+		e.taskvars = prevTaskvars
+		e.foldedTaskNames = prevFoldedTaskNames
+		e.envOverrides = prevEnvOverrides
+		e.dotenvFiles = prevDotenvFiles
+		e.pinnedTools = prevPinnedTools
+		e.shimsPath = prevShimsPath
+		e.checksumScope = prevChecksumScope
+		e.fuzzyModel = prevFuzzyModel
+		e.OutputStyle = prevOutputStyle
+		e.Output = prevOutput
+		e.Logger.Stdout, e.Logger.Stderr = prevLoggerStdout, prevLoggerStderr
+		e.report = prevReport
+		return err
+	}
+	e.setupConcurrencyState()
+	return nil
+}
+
 func isContextError(err error) bool {
 	if taskRunErr, ok := err.(*TaskRunError); ok {
 		err = taskRunErr.err
@@ -126,17 +213,212 @@ func closeOnInterrupt(w *watcher.Watcher) {
 	}()
 }
 
-func (e *Executor) registerWatchedFiles(w *watcher.Watcher, calls ...taskfile.Call) error {
+// watchRuns are the runs of the watched tasks, which are canceled and
+// started again independently
+type watchRuns struct {
+	e     *Executor
+	calls []taskfile.Call
+
+	// dashboard shows the status of the runs, when set
+	dashboard *dashboard.Dashboard
+
+	mutex   sync.Mutex
+	running sync.WaitGroup
+	cancels []context.CancelFunc
+	// generations count the runs of each call, so a canceled run doesn't
+	// report its status over the one replacing it
+	generations []int
+	// files are the indexes of the calls whose sources include each file
+	files map[string]map[int]bool
+	// taskfiles are the Taskfiles and dotenv files, which reload the
+	// Taskfile when changed
+	taskfiles map[string]bool
+	// reloading keeps the watched files from being registered while the
+	// Taskfile is reloaded
+	reloading sync.RWMutex
+}
+
+func newWatchRuns(e *Executor, calls []taskfile.Call) *watchRuns {
+	return &watchRuns{
+		e:           e,
+		calls:       calls,
+		cancels:     make([]context.CancelFunc, len(calls)),
+		generations: make([]int, len(calls)),
+		files:       make(map[string]map[int]bool),
+		taskfiles:   make(map[string]bool),
+	}
+}
+
+// run cancels the given calls, and runs them again. All calls are run when
+// none is given.
+func (r *watchRuns) run(indexes []int) {
+	if len(indexes) == 0 {
+		indexes = make([]int, len(r.calls))
+		for i := range r.calls {
+			indexes[i] = i
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, i := range indexes {
+		if r.cancels[i] != nil {
+			r.cancels[i]()
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		r.cancels[i] = cancel
+		r.generations[i]++
+
+		i, c, generation := i, r.calls[i], r.generations[i]
+		if r.dashboard != nil {
+			r.dashboard.Start(i)
+		}
+		r.running.Add(1)
+		go func() {
+			defer r.running.Done()
+			err := r.e.RunTask(ctx, c)
+			if err != nil && !isContextError(err) {
+				r.e.Logger.Errf(logger.Red, "%v", err)
+			}
+			r.finish(i, generation, err)
+		}()
+	}
+}
+
+// finish reports the status of a finished run to the dashboard
+func (r *watchRuns) finish(i, generation int, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.dashboard == nil || r.generations[i] != generation {
+		return
+	}
+	switch {
+	case err == nil:
+		r.dashboard.Finish(i, dashboard.Succeeded)
+	case isContextError(err):
+		r.dashboard.Finish(i, dashboard.Stopped)
+	default:
+		r.dashboard.Finish(i, dashboard.Failed)
+	}
+}
+
+// stop cancels the run of the call at index i
+func (r *watchRuns) stop(i int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.cancels[i] != nil {
+		r.cancels[i]()
+	}
+}
+
+// cancel cancels all runs
+func (r *watchRuns) cancel() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, cancel := range r.cancels {
+		if cancel != nil {
+			cancel()
+		}
+	}
+}
+
+// wait waits for the canceled runs to finish
+func (r *watchRuns) wait() {
+	r.running.Wait()
+}
+
+// owners returns the indexes of the calls watching the given file, in order
+func (r *watchRuns) owners(file string) []int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	owners := r.files[filepathext.Normalize(file)]
+	indexes := make([]int, 0, len(owners))
+	for i := range r.calls {
+		if owners[i] {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func (r *watchRuns) addOwner(file string, i int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.files[file] == nil {
+		r.files[file] = make(map[int]bool)
+	}
+	r.files[file][i] = true
+}
+
+// isTaskfile returns whether the given file is a Taskfile or dotenv file
+func (r *watchRuns) isTaskfile(file string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.taskfiles[filepathext.Normalize(file)]
+}
+
+// registerTaskfiles watches the Taskfiles and dotenv files, so the Taskfile
+// is reloaded when they change
+func (e *Executor) registerTaskfiles(w *watcher.Watcher, runs *watchRuns) error {
+	watchedFiles := w.WatchedFiles()
+
+	for _, f := range e.taskfileFiles() {
+		absFile, err := filepath.Abs(f)
+		if err != nil {
+			return err
+		}
+		absFile = filepathext.Normalize(absFile)
+		// Missing dotenv files are watched once they are created
+		if _, err := os.Stat(absFile); err != nil {
+			continue
+		}
+		runs.mutex.Lock()
+		runs.taskfiles[absFile] = true
+		runs.mutex.Unlock()
+		if _, ok := watchedFiles[absFile]; ok {
+			continue
+		}
+		if err := w.Add(absFile); err != nil {
+			return err
+		}
+		e.Logger.VerboseOutf(logger.Green, "task: watching Taskfile: %v", absFile)
+	}
+	return nil
+}
+
+func (e *Executor) registerWatchedFiles(w *watcher.Watcher, runs *watchRuns, calls ...taskfile.Call) error {
 	watchedFiles := w.WatchedFiles()
 
+	// index is the index of the watched call the task is run by
+	var index int
 	var registerTaskFiles func(taskfile.Call) error
 	registerTaskFiles = func(c taskfile.Call) error {
+		if isTaskPattern(c.Task) {
+			calls, err := e.expandCalls(c)
+			if err != nil {
+				return err
+			}
+			for _, c := range calls {
+				if err := registerTaskFiles(c); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
 		task, err := e.CompiledTask(c)
 		if err != nil {
 			return err
 		}
 
-		for _, d := range task.Deps {
+		for _, d := range taskfile.AllDeps(task.Deps) {
 			if err := registerTaskFiles(taskfile.Call{Task: d.Task, Vars: d.Vars}); err != nil {
 				return err
 			}
@@ -159,9 +441,11 @@ func (e *Executor) registerWatchedFiles(w *watcher.Watcher, calls ...taskfile.Ca
 				if err != nil {
 					return err
 				}
+				absFile = filepathext.Normalize(absFile)
 				if shouldIgnoreFile(absFile) {
 					continue
 				}
+				runs.addOwner(absFile, index)
 				if _, ok := watchedFiles[absFile]; ok {
 					continue
 				}
@@ -174,7 +458,8 @@ func (e *Executor) registerWatchedFiles(w *watcher.Watcher, calls ...taskfile.Ca
 		return nil
 	}
 
-	for _, c := range calls {
+	for i, c := range calls {
+		index = i
 		if err := registerTaskFiles(c); err != nil {
 			return err
 		}
@@ -0,0 +1,114 @@
+package task
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-task/task/v3/internal/dashboard"
+	"github.com/radovskyb/watcher"
+	"golang.org/x/term"
+)
+
+const dashboardRefresh = 100 * time.Millisecond
+
+// isTerminal returns true if the given reader or writer is an interactive
+// terminal
+func isTerminal(v interface{}) bool {
+	f, ok := v.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// startDashboard shows a dashboard of the watched tasks on the terminal,
+// in place of their output, until the returned function is called. Keys
+// select a task, rerun it, stop it, or quit watching.
+func (e *Executor) startDashboard(runs *watchRuns, tasks []string, w *watcher.Watcher) (func(), error) {
+	stdin := e.Stdin.(*os.File)
+	stdout := e.Stdout.(*os.File)
+
+	state, err := term.MakeRaw(int(stdin.Fd()))
+	if err != nil {
+		return nil, err
+	}
+
+	d := dashboard.New(tasks)
+	runs.dashboard = d
+	origStdout, origStderr := e.Stdout, e.Stderr
+	origLoggerStdout, origLoggerStderr := e.Logger.Stdout, e.Logger.Stderr
+	e.Stdout, e.Stderr = d, d
+	e.Logger.Stdout, e.Logger.Stderr = d, d
+
+	// The alternate screen keeps the scrollback of the terminal intact
+	io.WriteString(stdout, "\x1b[?1049h\x1b[?25l")
+
+	done := make(chan struct{})
+	go renderDashboard(d, stdout, done)
+	go e.readDashboardKeys(d, runs, stdin, w)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			e.Stdout, e.Stderr = origStdout, origStderr
+			e.Logger.Stdout, e.Logger.Stderr = origLoggerStdout, origLoggerStderr
+			io.WriteString(stdout, "\x1b[?25h\x1b[?1049l")
+			_ = term.Restore(int(stdin.Fd()), state)
+		})
+	}, nil
+}
+
+// renderDashboard draws the dashboard whenever it changes, and every second
+// while tasks run, so their durations are updated
+func renderDashboard(d *dashboard.Dashboard, stdout *os.File, done <-chan struct{}) {
+	ticker := time.NewTicker(dashboardRefresh)
+	defer ticker.Stop()
+
+	var last time.Time
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		if !d.Dirty() && time.Since(last) < time.Second {
+			continue
+		}
+		last = time.Now()
+
+		width, height, err := term.GetSize(int(stdout.Fd()))
+		if err != nil || width <= 0 || height <= 0 {
+			width, height = 80, 24
+		}
+		lines := d.Render(width, height)
+		// Raw mode doesn't translate "\n" to "\r\n"
+		io.WriteString(stdout, "\x1b[H\x1b[2J"+strings.Join(lines, "\r\n"))
+	}
+}
+
+// readDashboardKeys handles the keys pressed on the dashboard
+func (e *Executor) readDashboardKeys(d *dashboard.Dashboard, runs *watchRuns, stdin *os.File, w *watcher.Watcher) {
+	buf := make([]byte, 16)
+	for {
+		n, err := stdin.Read(buf)
+		if err != nil {
+			return
+		}
+		switch key := string(buf[:n]); key {
+		case "\x1b[A", "k":
+			d.Select(-1)
+		case "\x1b[B", "j", "\t":
+			d.Select(1)
+		case "r":
+			e.Compiler.ResetCache()
+			e.resetExecutions()
+			runs.run([]int{d.Selected()})
+		case "s":
+			runs.stop(d.Selected())
+		case "q", "\x03":
+			w.Close()
+			return
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package task
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// isTaskPattern returns true if the given task name is a wildcard pattern
+// like "test:*" instead of a plain task name.
+func isTaskPattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// matchingTasks returns the names of the tasks matching the given wildcard
+// pattern, sorted by name. Wildcards don't cross namespaces, so "test:*"
+// matches "test:unit" but not "test:unit:fast". Internal and catch-all tasks
+// are never matched.
+func (e *Executor) matchingTasks(pattern string) ([]string, error) {
+	pathPattern := strings.ReplaceAll(pattern, taskfile.NamespaceSeparator, "/")
+
+	var names []string
+	for name, t := range e.Taskfile.Tasks {
+		if t.Internal || isCatchAllTask(name) {
+			continue
+		}
+		ok, err := path.Match(pathPattern, strings.ReplaceAll(name, taskfile.NamespaceSeparator, "/"))
+		if err != nil {
+			return nil, &invalidTaskPatternError{pattern: pattern, err: err}
+		}
+		if ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, &taskNotFoundError{taskName: pattern}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// expandCalls replaces calls made with wildcard patterns by one call for each
+// matching task.
+func (e *Executor) expandCalls(calls ...taskfile.Call) ([]taskfile.Call, error) {
+	expanded := make([]taskfile.Call, 0, len(calls))
+	for _, c := range calls {
+		if !isTaskPattern(c.Task) {
+			expanded = append(expanded, c)
+			continue
+		}
+		names, err := e.matchingTasks(c.Task)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			expanded = append(expanded, taskfile.Call{Task: name, Vars: c.Vars})
+		}
+	}
+	return expanded, nil
+}
+
+// runMatchingTasks runs all tasks matching the wildcard pattern of the given
+// call in parallel. The concurrency limit is still respected, since it's
+// acquired by each task. When shuffling, they run one after the other in a
+// random order instead, so the order can be reproduced with the seed.
+func (e *Executor) runMatchingTasks(ctx context.Context, call taskfile.Call, isDep bool) error {
+	names, err := e.matchingTasks(call.Task)
+	if err != nil {
+		return err
+	}
+
+	if e.Shuffle {
+		e.shuffle(len(names), func(i, j int) { names[i], names[j] = names[j], names[i] })
+		for _, name := range names {
+			if err := e.runTask(ctx, taskfile.Call{Task: name, Vars: call.Vars}, isDep); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	for _, name := range names {
+		c := taskfile.Call{Task: name, Vars: call.Vars}
+		g.Go(func() error { return e.runTask(ctx, c, isDep) })
+	}
+	return g.Wait()
+}
+
+// isCatchAllTask returns true if the given task name is a catch-all, like
+// "*" or "deploy-*", that runs when no other task matches a call.
+func isCatchAllTask(name string) bool {
+	return strings.Contains(name, "*")
+}
+
+// matchCatchAll matches the given catch-all task name against a called
+// task name, returning the text matched by each wildcard.
+func matchCatchAll(pattern, name string) ([]string, bool) {
+	parts := strings.Split(pattern, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	re := regexp.MustCompile("^" + strings.Join(parts, "(.*)") + "$")
+
+	matches := re.FindStringSubmatch(name)
+	if matches == nil {
+		return nil, false
+	}
+	return matches[1:], true
+}
+
+// getCatchAllTask returns the catch-all task matching the given name, if
+// any. When more than one matches, the most specific one (the one with more
+// literal characters) is preferred.
+func (e *Executor) getCatchAllTask(name string) *taskfile.Task {
+	var (
+		best            *taskfile.Task
+		bestSpecificity = -1
+	)
+	for taskName, t := range e.Taskfile.Tasks {
+		if !isCatchAllTask(taskName) {
+			continue
+		}
+		if _, ok := matchCatchAll(taskName, name); !ok {
+			continue
+		}
+		specificity := len(strings.ReplaceAll(taskName, "*", ""))
+		if specificity > bestSpecificity || (specificity == bestSpecificity && taskName < best.Task) {
+			best, bestSpecificity = t, specificity
+		}
+	}
+	return best
+}
+
+// withMatchVars returns a copy of the given call with the MATCHED_NAME and
+// MATCH variables set, if it was resolved to a catch-all task.
+func withMatchVars(call taskfile.Call, t *taskfile.Task) taskfile.Call {
+	if !isCatchAllTask(t.Task) || t.Task == call.Task {
+		return call
+	}
+	captures, ok := matchCatchAll(t.Task, call.Task)
+	if !ok {
+		return call
+	}
+
+	vars := &taskfile.Vars{}
+	vars.Set("MATCHED_NAME", taskfile.Var{Static: call.Task})
+	vars.Set("MATCH", taskfile.Var{Live: captures})
+	vars.Merge(call.Vars)
+	return taskfile.Call{Task: call.Task, Vars: vars}
+}